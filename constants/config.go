@@ -6,3 +6,29 @@ const (
 	WHITELIST FliterMode = "WhiteList" // 白名单
 	BLACKLIST FliterMode = "BlackList" // 黑名单
 )
+
+type SourcePolicy string // 远程存储熔断时媒体源的处理方式
+
+const (
+	SourcePolicyNone SourcePolicy = "None" // 不处理
+	SourcePolicySort SourcePolicy = "Sort" // 排至最后
+	SourcePolicyHide SourcePolicy = "Hide" // 隐藏（全部媒体源都熔断时不隐藏）
+)
+
+type ChineseConvertMode string // 字幕繁简转换模式
+
+const (
+	ChineseConvertNone ChineseConvertMode = "None" // 不转换
+	ChineseConvertS2T  ChineseConvertMode = "S2T"  // 简体转繁体（台湾正体）
+	ChineseConvertT2S  ChineseConvertMode = "T2S"  // 繁体转简体
+)
+
+type RouteRuleAction string // 自定义路由规则的动作
+
+const (
+	RouteRuleRedirect RouteRuleAction = "Redirect" // 跳转
+	RouteRuleRewrite  RouteRuleAction = "Rewrite"  // 重写请求路径后继续匹配
+	RouteRuleHeader   RouteRuleAction = "Header"   // 只修改请求头，继续匹配
+	RouteRuleBlock    RouteRuleAction = "Block"    // 拒绝请求
+	RouteRuleProxy    RouteRuleAction = "Proxy"    // 转发至其他上游
+)
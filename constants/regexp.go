@@ -13,11 +13,15 @@ type RouterRegexps struct {
 	ModifyIndex          *regexp.Regexp // Web 首页
 	ModifyPlaybackInfo   *regexp.Regexp // 播放信息处理接口
 	ModifySubtitles      *regexp.Regexp // 字幕处理接口
+	BilingualSubtitles   *regexp.Regexp // MediaWarp 添加的双语虚拟字幕流
 	StreamStrmHandler    *regexp.Regexp // 匹配 /emby/videos/{id}/stream.strm
+	SessionsPlaying      *regexp.Regexp // 开始播放上报接口
+	StrmEndpoint         *regexp.Regexp // MediaWarp 提供的 Strm 下载链接接口，供上游转码、外部播放器或脚本获取最新的下载链接
 }
 
 type OthersRegexps struct {
 	VideoRedirectReg *regexp.Regexp // 视频重定向匹配，统一视频请求格式
+	VideoItemIDReg   *regexp.Regexp // 从视频流请求路径中提取 ItemId
 }
 
 var EmbyRegexp = &EmbyRegexps{
@@ -26,10 +30,60 @@ var EmbyRegexp = &EmbyRegexps{
 		ModifyBaseHtmlPlayer: regexp.MustCompile(`(?i)^/web/modules/htmlvideoplayer/basehtmlplayer.js$`),
 		ModifyIndex:          regexp.MustCompile(`^/web/index.html$`),
 		ModifyPlaybackInfo:   regexp.MustCompile(`(?i)^(/emby)?/Items/\d+/PlaybackInfo$`),
-		ModifySubtitles:      regexp.MustCompile(`(?i)^(/emby)?/Videos/\d+/\w+/subtitles$`),
+		ModifySubtitles:      regexp.MustCompile(`(?i)^(/emby)?/Videos/\d+/[^/]+/Subtitles/\d+/(\d+/)?Stream\.\w+$`),
+		BilingualSubtitles:   regexp.MustCompile(`(?i)^(/emby)?/Videos/(\d+)/([^/]+)/Subtitles/(\d{6,})/(\d+/)?Stream\.\w+$`),
 		StreamStrmHandler:    regexp.MustCompile(`(?i)^(/emby)?/videos/\d+/stream\.strm$`),
+		SessionsPlaying:      regexp.MustCompile(`(?i)^(/emby)?/Sessions/Playing$`),
+		StrmEndpoint:         regexp.MustCompile(`(?i)^/MediaWarp/strm(?:/(\d+))?$`),
 	},
 	Others: OthersRegexps{
 		VideoRedirectReg: regexp.MustCompile(`(?i)^(/emby)?/videos/(.*)/stream/(.*)`),
+		VideoItemIDReg:   regexp.MustCompile(`(?i)/videos/(\d+)/`),
+	},
+}
+
+type JellyfinRegexps struct {
+	Router JellyfinRouterRegexps
+}
+
+type JellyfinRouterRegexps struct {
+	VideosHandler      *regexp.Regexp // 视频处理接口匹配（Jellyfin 使用 GUID 作为 ID）
+	ModifyIndex        *regexp.Regexp // Web 首页
+	ModifyPlaybackInfo *regexp.Regexp // 播放信息处理接口
+	ModifySubtitles    *regexp.Regexp // 字幕处理接口
+}
+
+var JellyfinRegexp = &JellyfinRegexps{
+	Router: JellyfinRouterRegexps{
+		VideosHandler:      regexp.MustCompile(`(?i)^/videos/[0-9a-f-]{32,36}/(stream|original)(\.\w+)?$`),
+		ModifyIndex:        regexp.MustCompile(`^/web/(index.html)?$`),
+		ModifyPlaybackInfo: regexp.MustCompile(`(?i)^/Items/[0-9a-f-]{32,36}/PlaybackInfo$`),
+		ModifySubtitles:    regexp.MustCompile(`(?i)^/Videos/[0-9a-f-]{32,36}/[0-9a-f-]{32,36}/Subtitles/\d+/(\d+/)?Stream\.\w+$`),
+	},
+}
+
+type PlexRegexps struct {
+	Router PlexRouterRegexps
+	Others PlexOthersRegexps
+}
+
+type PlexRouterRegexps struct {
+	VideosHandler   *regexp.Regexp // 媒体文件分段下载接口匹配
+	DecisionHandler *regexp.Regexp // 播放决策接口
+	MetadataHandler *regexp.Regexp // 媒体元数据接口
+}
+
+type PlexOthersRegexps struct {
+	MetadataPathReg *regexp.Regexp // 从决策接口的 path 参数中提取 ratingKey
+}
+
+var PlexRegexp = &PlexRegexps{
+	Router: PlexRouterRegexps{
+		VideosHandler:   regexp.MustCompile(`^/library/parts/(\d+)/(\d+/)?file(\.\w+)?$`),
+		DecisionHandler: regexp.MustCompile(`^/video/:/transcode/universal/decision$`),
+		MetadataHandler: regexp.MustCompile(`^/library/metadata/\d+$`),
+	},
+	Others: PlexOthersRegexps{
+		MetadataPathReg: regexp.MustCompile(`^/library/metadata/(\d+)`),
 	},
 }
@@ -4,5 +4,15 @@ type StrmFileType string // Strm 文件类型
 
 var (
 	HTTPStrm    StrmFileType = "HTTPStrm"
+	AlistStrm   StrmFileType = "AlistStrm"
 	UnknownStrm StrmFileType = "UnknownStrm"
 )
+
+type StreamMode string // 视频流处理方式
+
+const (
+	RedirectStream StreamMode = "Redirect" // 302 重定向
+	ProxyStream    StreamMode = "Proxy"    // 代理推流
+)
+
+const StreamModeKey = "MediaWarp.StreamMode" // gin.Context 中记录视频流处理方式的键
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// LRUCache 按总字节数限制容量的 LRU 缓存
+//
+// 用于缓存按内容哈希索引的处理结果（如转换后的字幕），超出容量时淘汰最久未使用的条目
+type LRUCache struct {
+	mutex    sync.Mutex
+	maxBytes int
+	size     int
+	order    *list.List               // 最近使用的条目在前
+	entries  map[string]*list.Element // 键 -> 条目
+}
+
+type lruEntry struct {
+	key   string
+	value []byte
+}
+
+// NewLRUCache 创建 LRU 缓存，maxBytes 为全部值的总字节数上限
+func NewLRUCache(maxBytes int) *LRUCache {
+	return &LRUCache{maxBytes: maxBytes, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// Get 获取缓存的值
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*lruEntry).value, true
+}
+
+// Set 缓存值，超过总容量的值不缓存
+func (c *LRUCache) Set(key string, value []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+	if len(value) > c.maxBytes {
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value})
+	c.size += len(value)
+	for c.size > c.maxBytes {
+		c.remove(c.order.Back())
+	}
+}
+
+// Clear 清空缓存
+func (c *LRUCache) Clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.order.Init()
+	c.entries = make(map[string]*list.Element)
+	c.size = 0
+}
+
+// Len 缓存的条目数
+func (c *LRUCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.entries)
+}
+
+func (c *LRUCache) remove(element *list.Element) {
+	entry := c.order.Remove(element).(*lruEntry)
+	delete(c.entries, entry.key)
+	c.size -= len(entry.value)
+}
@@ -0,0 +1,31 @@
+package cache_test
+
+import (
+	"MediaWarp/internal/cache"
+	"testing"
+)
+
+func TestLRUCache(t *testing.T) {
+	lru := cache.NewLRUCache(10)
+	lru.Set("a", []byte("1234"))
+	lru.Set("b", []byte("1234"))
+	if _, ok := lru.Get("a"); !ok { // a 成为最近使用的条目
+		t.Fatal("未找到缓存 a")
+	}
+	lru.Set("c", []byte("1234"))
+	if _, ok := lru.Get("b"); ok {
+		t.Error("超出容量时应淘汰最久未使用的 b")
+	}
+	if value, ok := lru.Get("a"); !ok || string(value) != "1234" {
+		t.Errorf("缓存 a 不应被淘汰：%q", value)
+	}
+
+	lru.Set("a", []byte("12345678"))
+	if lru.Len() != 1 {
+		t.Errorf("更新后应按新值的大小淘汰，实际条目数：%d", lru.Len())
+	}
+	lru.Set("huge", make([]byte, 11))
+	if _, ok := lru.Get("huge"); ok {
+		t.Error("超过总容量的值不应缓存")
+	}
+}
@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"MediaWarp/internal/logging"
 	"sync"
 	"time"
 )
@@ -17,6 +18,8 @@ type SafeCache struct {
 	mutex   sync.RWMutex
 	cleanup *time.Ticker
 	done    chan bool
+	store   Store  // 持久化存储，为 nil 时仅保存在内存中
+	bucket  string // 持久化存储中的 bucket 名称
 }
 
 // NewSafeCache 创建新的安全缓存
@@ -53,23 +56,58 @@ func (c *SafeCache) Get(key string) (CacheItem, bool) {
 	return item, true
 }
 
-// Set 设置缓存项
-func (c *SafeCache) Set(key string, url string, expireTime time.Time) {
+// Persist 使用持久化存储
+//
+// 加载存储中未过期的缓存项，之后的写入和删除会同步到存储中，返回加载的缓存项数量
+func (c *SafeCache) Persist(store Store, bucket string) (int, error) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
+	loaded := 0
+	err := store.Load(bucket, func(key string, value []byte, expireTime time.Time) {
+		c.data[key] = CacheItem{
+			URL:        string(value),
+			ExpireTime: expireTime,
+		}
+		loaded++
+	})
+	if err != nil {
+		return loaded, err
+	}
+	c.store = store
+	c.bucket = bucket
+	return loaded, nil
+}
+
+// Set 设置缓存项
+func (c *SafeCache) Set(key string, url string, expireTime time.Time) {
+	c.mutex.Lock()
 	c.data[key] = CacheItem{
 		URL:        url,
 		ExpireTime: expireTime,
 	}
+	store, bucket := c.store, c.bucket
+	c.mutex.Unlock()
+
+	if store != nil {
+		if err := store.Put(bucket, key, []byte(url), expireTime); err != nil {
+			logging.Warning("写入持久化缓存失败：", err)
+		}
+	}
 }
 
 // Delete 删除缓存项
 func (c *SafeCache) Delete(key string) {
 	c.mutex.Lock()
-	defer c.mutex.Unlock()
-
 	delete(c.data, key)
+	store, bucket := c.store, c.bucket
+	c.mutex.Unlock()
+
+	if store != nil {
+		if err := store.Delete(bucket, key); err != nil {
+			logging.Warning("删除持久化缓存失败：", err)
+		}
+	}
 }
 
 // Size 获取缓存大小
@@ -83,9 +121,15 @@ func (c *SafeCache) Size() int {
 // Clear 清空缓存
 func (c *SafeCache) Clear() {
 	c.mutex.Lock()
-	defer c.mutex.Unlock()
-
 	c.data = make(map[string]CacheItem)
+	store, bucket := c.store, c.bucket
+	c.mutex.Unlock()
+
+	if store != nil {
+		if err := store.Clear(bucket); err != nil {
+			logging.Warning("清空持久化缓存失败：", err)
+		}
+	}
 }
 
 // startCleanup 启动清理过期项的协程
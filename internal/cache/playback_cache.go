@@ -2,13 +2,23 @@ package cache
 
 import (
 	"MediaWarp/constants"
+	"MediaWarp/internal/logging"
 	"MediaWarp/internal/service/emby"
+	"MediaWarp/internal/service/jellyfin"
 	"crypto/md5"
 	"encoding/hex"
+	"encoding/json"
 	"sync"
 	"time"
 )
 
+// 持久化存储中的 bucket 名称
+const (
+	itemInfoBucket  = "item_info"
+	alistLinkBucket = "alist_link"
+	playbackBucket  = "playback"
+)
+
 // PlaybackInfoCache 播放信息专用缓存管理器
 type PlaybackInfoCache struct {
 	itemInfoCache  map[string]*CachedItemInfo
@@ -19,13 +29,15 @@ type PlaybackInfoCache struct {
 	cleanup        *time.Ticker
 	done           chan bool
 	stats          *CacheStats
+	store          Store // 持久化存储，为 nil 时仅保存在内存中（Strm 类型解析开销小，不持久化）
 }
 
 // CachedItemInfo 缓存的媒体项信息
 type CachedItemInfo struct {
-	EmbyItem  *emby.EmbyResponse `json:"emby_item,omitempty"`
-	Timestamp time.Time          `json:"timestamp"`
-	TTL       time.Duration      `json:"ttl"`
+	EmbyItem     *emby.EmbyResponse `json:"emby_item,omitempty"`
+	JellyfinItem *jellyfin.Response `json:"jellyfin_item,omitempty"`
+	Timestamp    time.Time          `json:"timestamp"`
+	TTL          time.Duration      `json:"ttl"`
 }
 
 // CachedStrmType 缓存的Strm文件类型
@@ -132,14 +144,50 @@ func (pic *PlaybackInfoCache) GetItemInfo(mediaSourceID string) (*CachedItemInfo
 // SetItemInfo 设置媒体项信息缓存
 func (pic *PlaybackInfoCache) SetItemInfo(mediaSourceID string, embyItem *emby.EmbyResponse, ttl time.Duration) {
 	pic.mutex.Lock()
-	defer pic.mutex.Unlock()
 
 	key := pic.generateKey("item", mediaSourceID)
-	pic.itemInfoCache[key] = &CachedItemInfo{
+	cached := &CachedItemInfo{
 		EmbyItem:  embyItem,
 		Timestamp: time.Now(),
 		TTL:       ttl,
 	}
+	pic.itemInfoCache[key] = cached
+	store := pic.store
+	pic.mutex.Unlock()
+
+	saveToStore(store, itemInfoBucket, key, cached, cached.Timestamp.Add(ttl))
+}
+
+// GetJellyfinItemInfo 获取 Jellyfin 媒体项信息
+func (pic *PlaybackInfoCache) GetJellyfinItemInfo(mediaSourceID string) (*CachedItemInfo, bool) {
+	pic.mutex.RLock()
+	defer pic.mutex.RUnlock()
+
+	key := pic.generateKey("jellyfin_item", mediaSourceID)
+	if cached, exists := pic.itemInfoCache[key]; exists && !cached.IsExpired() {
+		pic.stats.incrementItemInfoHits()
+		return cached, true
+	}
+
+	pic.stats.incrementItemInfoMisses()
+	return nil, false
+}
+
+// SetJellyfinItemInfo 设置 Jellyfin 媒体项信息缓存
+func (pic *PlaybackInfoCache) SetJellyfinItemInfo(mediaSourceID string, jellyfinItem *jellyfin.Response, ttl time.Duration) {
+	pic.mutex.Lock()
+
+	key := pic.generateKey("jellyfin_item", mediaSourceID)
+	cached := &CachedItemInfo{
+		JellyfinItem: jellyfinItem,
+		Timestamp:    time.Now(),
+		TTL:          ttl,
+	}
+	pic.itemInfoCache[key] = cached
+	store := pic.store
+	pic.mutex.Unlock()
+
+	saveToStore(store, itemInfoBucket, key, cached, cached.Timestamp.Add(ttl))
 }
 
 // GetStrmType 获取Strm文件类型（消除重复解析）
@@ -189,16 +237,20 @@ func (pic *PlaybackInfoCache) GetAlistLink(filePath string) (*CachedAlistLink, b
 // SetAlistLink 设置Alist下载链接缓存
 func (pic *PlaybackInfoCache) SetAlistLink(filePath, downloadURL, sign, rawURL string, ttl time.Duration) {
 	pic.mutex.Lock()
-	defer pic.mutex.Unlock()
 
 	key := pic.generateKey("alist", filePath)
-	pic.alistLinkCache[key] = &CachedAlistLink{
+	cached := &CachedAlistLink{
 		DownloadURL: downloadURL,
 		Sign:        sign,
 		RawURL:      rawURL,
 		Timestamp:   time.Now(),
 		TTL:         ttl,
 	}
+	pic.alistLinkCache[key] = cached
+	store := pic.store
+	pic.mutex.Unlock()
+
+	saveToStore(store, alistLinkBucket, key, cached, cached.Timestamp.Add(ttl))
 }
 
 // GetPlaybackInfo 获取完整播放信息
@@ -219,14 +271,95 @@ func (pic *PlaybackInfoCache) GetPlaybackInfo(mediaSourceID string) (*CachedPlay
 // SetPlaybackInfo 设置完整播放信息缓存
 func (pic *PlaybackInfoCache) SetPlaybackInfo(mediaSourceID string, embyResponse *emby.PlaybackInfoResponse, ttl time.Duration) {
 	pic.mutex.Lock()
-	defer pic.mutex.Unlock()
 
 	key := pic.generateKey("playback", mediaSourceID)
-	pic.playbackCache[key] = &CachedPlaybackInfo{
+	cached := &CachedPlaybackInfo{
 		EmbyResponse: embyResponse,
 		Timestamp:    time.Now(),
 		TTL:          ttl,
 	}
+	pic.playbackCache[key] = cached
+	store := pic.store
+	pic.mutex.Unlock()
+
+	saveToStore(store, playbackBucket, key, cached, cached.Timestamp.Add(ttl))
+}
+
+// Persist 使用持久化存储
+//
+// 加载存储中未过期的媒体项信息、Alist 链接和播放信息，之后的写入会同步到存储中，返回加载的缓存项数量
+func (pic *PlaybackInfoCache) Persist(store Store) (int, error) {
+	pic.mutex.Lock()
+	defer pic.mutex.Unlock()
+
+	loaded := 0
+	err := store.Load(itemInfoBucket, func(key string, value []byte, _ time.Time) {
+		var cached CachedItemInfo
+		if json.Unmarshal(value, &cached) == nil {
+			pic.itemInfoCache[key] = &cached
+			loaded++
+		}
+	})
+	if err != nil {
+		return loaded, err
+	}
+	err = store.Load(alistLinkBucket, func(key string, value []byte, _ time.Time) {
+		var cached CachedAlistLink
+		if json.Unmarshal(value, &cached) == nil {
+			pic.alistLinkCache[key] = &cached
+			loaded++
+		}
+	})
+	if err != nil {
+		return loaded, err
+	}
+	err = store.Load(playbackBucket, func(key string, value []byte, _ time.Time) {
+		var cached CachedPlaybackInfo
+		if json.Unmarshal(value, &cached) == nil {
+			pic.playbackCache[key] = &cached
+			loaded++
+		}
+	})
+	if err != nil {
+		return loaded, err
+	}
+	pic.store = store
+	return loaded, nil
+}
+
+// saveToStore 写入持久化存储
+//
+// 在释放锁后调用，避免在持有锁时等待磁盘同步
+func saveToStore(store Store, bucket string, key string, value any, expireTime time.Time) {
+	if store == nil {
+		return
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		logging.Warning("序列化缓存项失败：", err)
+		return
+	}
+	if err := store.Put(bucket, key, data, expireTime); err != nil {
+		logging.Warning("写入持久化缓存失败：", err)
+	}
+}
+
+// Clear 清空全部缓存（包括持久化存储）
+func (pic *PlaybackInfoCache) Clear() {
+	pic.mutex.Lock()
+	defer pic.mutex.Unlock()
+
+	pic.itemInfoCache = make(map[string]*CachedItemInfo)
+	pic.strmTypeCache = make(map[string]*CachedStrmType)
+	pic.alistLinkCache = make(map[string]*CachedAlistLink)
+	pic.playbackCache = make(map[string]*CachedPlaybackInfo)
+	if pic.store != nil {
+		for _, bucket := range []string{itemInfoBucket, alistLinkBucket, playbackBucket} {
+			if err := pic.store.Clear(bucket); err != nil {
+				logging.Warning("清空持久化缓存失败：", err)
+			}
+		}
+	}
 }
 
 // startCleanup 启动清理过期项的协程
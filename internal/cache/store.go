@@ -0,0 +1,170 @@
+package cache
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Store 缓存持久化存储接口
+//
+// 每条记录附带过期时间，按 bucket 区分不同的缓存
+type Store interface {
+	Load(bucket string, fn func(key string, value []byte, expireTime time.Time)) error // 遍历 bucket 中未过期的记录
+	Put(bucket string, key string, value []byte, expireTime time.Time) error           // 写入记录
+	Delete(bucket string, key string) error                                            // 删除记录
+	Clear(bucket string) error                                                         // 清空 bucket
+	Compact() (int, error)                                                             // 删除全部过期记录，返回删除数量
+	Close() error                                                                      // 关闭存储
+}
+
+// BoltStore 基于 bbolt 的持久化存储
+//
+// 记录格式：8 字节过期时间（UnixNano，大端序）+ 值
+type BoltStore struct {
+	db   *bolt.DB
+	done chan bool
+}
+
+var errInvalidRecord = errors.New("缓存记录格式错误")
+
+// OpenBoltStore 打开（不存在时创建）持久化缓存文件
+func OpenBoltStore(path string) (*BoltStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 3 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+	return &BoltStore{db: db, done: make(chan bool)}, nil
+}
+
+func encodeRecord(value []byte, expireTime time.Time) []byte {
+	record := make([]byte, 8+len(value))
+	binary.BigEndian.PutUint64(record, uint64(expireTime.UnixNano()))
+	copy(record[8:], value)
+	return record
+}
+
+func decodeRecord(record []byte) ([]byte, time.Time, error) {
+	if len(record) < 8 {
+		return nil, time.Time{}, errInvalidRecord
+	}
+	expireTime := time.Unix(0, int64(binary.BigEndian.Uint64(record)))
+	return record[8:], expireTime, nil
+}
+
+// Load 遍历 bucket 中未过期的记录
+//
+// 传入 fn 的 value 仅在回调内有效
+func (s *BoltStore) Load(bucket string, fn func(key string, value []byte, expireTime time.Time)) error {
+	now := time.Now()
+	return s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			value, expireTime, err := decodeRecord(v)
+			if err != nil || now.After(expireTime) {
+				return nil
+			}
+			fn(string(k), value, expireTime)
+			return nil
+		})
+	})
+}
+
+// Put 写入记录
+//
+// 使用 Batch 合并并发写入，减少磁盘同步次数
+func (s *BoltStore) Put(bucket string, key string, value []byte, expireTime time.Time) error {
+	record := encodeRecord(value, expireTime)
+	return s.db.Batch(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(bucket))
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(key), record)
+	})
+}
+
+// Delete 删除记录
+func (s *BoltStore) Delete(bucket string, key string) error {
+	return s.db.Batch(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+		return b.Delete([]byte(key))
+	})
+}
+
+// Clear 清空 bucket
+func (s *BoltStore) Clear(bucket string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket([]byte(bucket)); err != nil && !errors.Is(err, bolt.ErrBucketNotFound) {
+			return err
+		}
+		return nil
+	})
+}
+
+// Compact 删除全部 bucket 中的过期记录和无法解析的记录
+func (s *BoltStore) Compact() (int, error) {
+	removed := 0
+	now := time.Now()
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			var expiredKeys [][]byte // 遍历时不能删除，先收集过期的键
+			err := b.ForEach(func(k, v []byte) error {
+				if _, expireTime, err := decodeRecord(v); err != nil || now.After(expireTime) {
+					expiredKeys = append(expiredKeys, append([]byte(nil), k...))
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			for _, k := range expiredKeys {
+				if err := b.Delete(k); err != nil {
+					return err
+				}
+			}
+			removed += len(expiredKeys)
+			return nil
+		})
+	})
+	return removed, err
+}
+
+// StartCompaction 定期删除过期记录
+func (s *BoltStore) StartCompaction(interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := s.Compact(); err != nil && onError != nil {
+					onError(err)
+				}
+			case <-s.done:
+				return
+			}
+		}
+	}()
+}
+
+// Close 关闭存储
+func (s *BoltStore) Close() error {
+	close(s.done)
+	return s.db.Close()
+}
+
+var _ Store = (*BoltStore)(nil) // 确保 BoltStore 实现 Store 接口
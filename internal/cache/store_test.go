@@ -0,0 +1,77 @@
+package cache_test
+
+import (
+	"MediaWarp/internal/cache"
+	"MediaWarp/internal/logging"
+	"MediaWarp/internal/service/emby"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPersistentCache(t *testing.T) {
+	logging.Init()
+	path := filepath.Join(t.TempDir(), "cache.db")
+	store, err := cache.OpenBoltStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	redirectCache := cache.NewSafeCache(time.Minute)
+	defer redirectCache.Close()
+	if _, err := redirectCache.Persist(store, "redirect_url"); err != nil {
+		t.Fatal(err)
+	}
+	redirectCache.Set("/movie.mkv|UA", "https://cdn.example.com/movie.mkv", time.Now().Add(time.Hour))
+	redirectCache.Set("/expired.mkv|UA", "https://cdn.example.com/expired.mkv", time.Now().Add(50*time.Millisecond))
+	redirectCache.Set("/deleted.mkv|UA", "https://cdn.example.com/deleted.mkv", time.Now().Add(time.Hour))
+	redirectCache.Delete("/deleted.mkv|UA")
+
+	playbackCache := cache.NewPlaybackInfoCache(time.Minute)
+	defer playbackCache.Close()
+	if _, err := playbackCache.Persist(store); err != nil {
+		t.Fatal(err)
+	}
+	path1 := "/media/movie.strm"
+	playbackCache.SetItemInfo("1", &emby.EmbyResponse{Items: []emby.BaseItemDto{{Path: &path1}}}, time.Hour)
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	// 模拟重启
+	store, err = cache.OpenBoltStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if removed, err := store.Compact(); err != nil || removed != 1 {
+		t.Errorf("清理过期记录错误：%d，%v", removed, err)
+	}
+
+	reloaded := cache.NewSafeCache(time.Minute)
+	defer reloaded.Close()
+	if loaded, err := reloaded.Persist(store, "redirect_url"); err != nil || loaded != 1 {
+		t.Fatalf("加载下载链接缓存错误：%d，%v", loaded, err)
+	}
+	if item, ok := reloaded.Get("/movie.mkv|UA"); !ok || item.URL != "https://cdn.example.com/movie.mkv" {
+		t.Errorf("下载链接缓存错误：%+v", item)
+	}
+
+	reloadedPlayback := cache.NewPlaybackInfoCache(time.Minute)
+	defer reloadedPlayback.Close()
+	if loaded, err := reloadedPlayback.Persist(store); err != nil || loaded != 1 {
+		t.Fatalf("加载媒体项信息缓存错误：%d，%v", loaded, err)
+	}
+	cached, ok := reloadedPlayback.GetItemInfo("1")
+	if !ok || cached.TTL != time.Hour || len(cached.EmbyItem.Items) != 1 || *cached.EmbyItem.Items[0].Path != path1 {
+		t.Errorf("媒体项信息缓存错误：%+v", cached)
+	}
+
+	reloadedPlayback.Clear()
+	cleared := cache.NewPlaybackInfoCache(time.Minute)
+	defer cleared.Close()
+	if loaded, _ := cleared.Persist(store); loaded != 0 {
+		t.Errorf("清空后仍加载了 %d 条缓存", loaded)
+	}
+}
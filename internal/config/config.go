@@ -2,10 +2,14 @@ package config
 
 import (
 	"MediaWarp/constants"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/spf13/viper"
@@ -21,16 +25,109 @@ var (
 		Arch:       runtime.GOARCH,
 	}
 
-	Port         int                 // MediaWarp开放端口
-	MediaServer  MediaServerSetting  // 上游媒体服务器设置
-	Logger       LoggerSetting       // 日志设置
-	Web          WebSetting          // Web服务器设置
-	ClientFilter ClientFilterSetting // 客户端过滤设置
-	MediaSync    MediaSyncSetting    // 媒体同步设置（合并 HTTPStrm 和 RcloneSync）
-	Subtitle     SubtitleSetting     // 字幕设置
-	Debug        bool                // 是否开启调试模式
+	settings atomic.Pointer[Snapshot] // 当前配置，加载和热重载时整体替换
 )
 
+// MediaWarp开放端口
+func Port() int {
+	return current().Port
+}
+
+// 上游媒体服务器设置（默认上游，即 MediaServers 的第一项）
+func MediaServer() MediaServerSetting {
+	return current().MediaServer
+}
+
+// 全部上游媒体服务器设置
+func MediaServers() []MediaServerSetting {
+	return current().MediaServers
+}
+
+// 配置验证警告（如 MediaSync 的本地路径不存在），不影响启动和热重载
+func Warnings() []ValidationError {
+	return current().warnings
+}
+
+// 日志设置
+func Logger() LoggerSetting {
+	return current().Logger
+}
+
+// Web服务器设置
+func Web() WebSetting {
+	return current().Web
+}
+
+// 客户端过滤设置
+func ClientFilter() ClientFilterSetting {
+	return current().ClientFilter
+}
+
+// HTTPStrm 设置
+func HTTPStrm() HTTPStrmSetting {
+	return current().HTTPStrm
+}
+
+// 媒体同步设置（合并 HTTPStrm 和 RcloneSync）
+func MediaSync() MediaSyncSetting {
+	return current().MediaSync
+}
+
+// AlistStrm 设置
+func AlistStrm() AlistStrmSetting {
+	return current().AlistStrm
+}
+
+// 代理推流设置
+func ProxyStream() ProxyStreamSetting {
+	return current().ProxyStream
+}
+
+// 字幕设置
+func Subtitle() SubtitleSetting {
+	return current().Subtitle
+}
+
+// 缓存设置
+func Cache() CacheSetting {
+	return current().Cache
+}
+
+// 网盘接口限流和熔断设置
+func RemoteLimit() RemoteLimitSetting {
+	return current().RemoteLimit
+}
+
+// 预加载设置
+func Preload() PreloadSetting {
+	return current().Preload
+}
+
+// 多版本媒体源设置
+func MediaSource() MediaSourceSetting {
+	return current().MediaSource
+}
+
+// User-Agent 规范化设置
+func UserAgent() UserAgentSetting {
+	return current().UserAgent
+}
+
+// 响应改写规则
+func Rewriters() []RewriterRule {
+	return current().Rewriters
+}
+
+// 自定义路由规则
+func Rules() []RouteRule {
+	return current().Rules
+}
+
+// 是否开启调试模式
+func Debug() bool {
+	return current().Debug
+}
+
 // 获取版本信息
 func Version() *VersionInfo {
 	return &version
@@ -55,6 +152,29 @@ func ConfigPath() string {
 	return filepath.Join(ConfigDir(), "config.yaml")
 }
 
+// 持久化缓存文件路径
+func CachePath() string {
+	if path := Cache().Path; path != "" {
+		return path
+	}
+	return filepath.Join(ConfigDir(), "cache.db")
+}
+
+// 获取字体目录
+//
+// 用于 ASS 字幕字体子集化
+func FontDir() string {
+	if fontDir := Subtitle().FontDir; fontDir != "" {
+		return fontDir
+	}
+	return filepath.Join(ConfigDir(), "fonts")
+}
+
+// 字幕时间轴调整的保存路径
+func SubtitleOffsetPath() string {
+	return filepath.Join(ConfigDir(), "subtitle_offsets.json")
+}
+
 // 获取日志目录
 //
 // 总日志目录
@@ -92,11 +212,14 @@ func CostomDir() string {
 //
 // 监听所有网卡
 func ListenAddr() string {
-	return fmt.Sprintf(":%d", Port)
+	return fmt.Sprintf(":%d", Port())
 }
 
 // 初始化configManager
 func Init(path string) error {
+	if err := bindEnv(viper.GetViper()); err != nil {
+		return err
+	}
 	if err := loadConfig(path); err != nil {
 		return err
 	}
@@ -106,6 +229,34 @@ func Init(path string) error {
 	return nil
 }
 
+// 配置快照
+//
+// 配置文件先解析到快照并验证，验证通过后再整体替换当前配置；
+// 替换后的快照不再修改，请求处理过程中可以并发读取
+type Snapshot struct {
+	Port         int
+	MediaServer  MediaServerSetting
+	MediaServers []MediaServerSetting
+	Logger       LoggerSetting
+	Web          WebSetting
+	ClientFilter ClientFilterSetting
+	HTTPStrm     HTTPStrmSetting
+	MediaSync    MediaSyncSetting
+	AlistStrm    AlistStrmSetting
+	ProxyStream  ProxyStreamSetting
+	Subtitle     SubtitleSetting
+	Cache        CacheSetting
+	RemoteLimit  RemoteLimitSetting
+	Preload      PreloadSetting
+	MediaSource  MediaSourceSetting
+	UserAgent    UserAgentSetting
+	Rewriters    []RewriterRule
+	Rules        []RouteRule
+	Debug        bool
+
+	warnings ValidationErrors // 验证警告
+}
+
 // 读取并解析配置文件
 func loadConfig(path string) error {
 	if path != "" {
@@ -118,31 +269,215 @@ func loadConfig(path string) error {
 	if err := viper.ReadInConfig(); err != nil {
 		return fmt.Errorf("读取配置文件失败: %v", err)
 	}
+	s, err := parseConfig(viper.GetViper())
+	if err != nil {
+		return err
+	}
+	if err := s.validate(); err != nil {
+		return err
+	}
+	s.apply()
+	return nil
+}
 
-	Port = viper.GetInt("Port")
-	MediaServer.Type = constants.MediaServerType(viper.GetString("MediaServer.Type"))
-	MediaServer.ADDR = viper.GetString("MediaServer.ADDR")
-	MediaServer.AUTH = viper.GetString("MediaServer.AUTH")
+// 解析并验证配置文件，不修改当前配置
+//
+// path 为空时使用默认配置文件
+func ValidateConfig(path string) error {
+	v := viper.New()
+	if err := bindEnv(v); err != nil {
+		return err
+	}
+	if path != "" {
+		v.SetConfigFile(path)
+	} else {
+		v.AddConfigPath(ConfigDir())
+		v.SetConfigName("config")
+	}
 
-	if err := viper.UnmarshalKey("Logger", &Logger); err != nil {
-		return fmt.Errorf("LoggerSetting 解析失败, %v", err)
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("读取配置文件失败: %v", err)
+	}
+	s, err := parseConfig(v)
+	if err != nil {
+		return err
+	}
+	err = s.validate()
+	if len(s.warnings) == 0 {
+		return err
+	}
+	var validationErrors ValidationErrors // 验证配置文件时警告也视为错误
+	errors.As(err, &validationErrors)
+	return append(validationErrors, s.warnings...)
+}
+
+// 将 viper 读取的配置解析为快照
+func parseConfig(v *viper.Viper) (*Snapshot, error) {
+	v, err := mergeEnv(v)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Snapshot{Port: v.GetInt("Port")}
+	if err := s.loadMediaServers(v); err != nil {
+		return nil, err
+	}
+
+	if err := v.UnmarshalKey("Logger", &s.Logger); err != nil {
+		return nil, fmt.Errorf("LoggerSetting 解析失败, %v", err)
+	}
+	if err := v.UnmarshalKey("Web", &s.Web); err != nil {
+		return nil, fmt.Errorf("WebSetting  解析失败, %v", err)
+	}
+	if err := v.UnmarshalKey("ClientFilter", &s.ClientFilter); err != nil {
+		return nil, fmt.Errorf("ClientFilterSetting  解析失败, %v", err)
+	}
+	if err := v.UnmarshalKey("MediaSync", &s.MediaSync); err != nil {
+		return nil, fmt.Errorf("MediaSyncSetting 解析失败, %v", err)
+	}
+	if err := v.UnmarshalKey("AlistStrm", &s.AlistStrm); err != nil {
+		return nil, fmt.Errorf("AlistStrmSetting 解析失败, %v", err)
+	}
+	if err := v.UnmarshalKey("ProxyStream", &s.ProxyStream); err != nil {
+		return nil, fmt.Errorf("ProxyStreamSetting 解析失败, %v", err)
+	}
+	if err := v.UnmarshalKey("Subtitle", &s.Subtitle); err != nil {
+		return nil, fmt.Errorf("SubtitleSetting  解析失败, %v", err)
 	}
-	if err := viper.UnmarshalKey("Web", &Web); err != nil {
-		return fmt.Errorf("WebSetting  解析失败, %v", err)
+	if err := v.UnmarshalKey("Cache", &s.Cache); err != nil {
+		return nil, fmt.Errorf("CacheSetting 解析失败, %v", err)
 	}
-	if err := viper.UnmarshalKey("ClientFilter", &ClientFilter); err != nil {
-		return fmt.Errorf("ClientFilterSetting  解析失败, %v", err)
+	if err := v.UnmarshalKey("RemoteLimit", &s.RemoteLimit); err != nil {
+		return nil, fmt.Errorf("RemoteLimitSetting 解析失败, %v", err)
 	}
-	if err := viper.UnmarshalKey("MediaSync", &MediaSync); err != nil {
-		return fmt.Errorf("MediaSyncSetting 解析失败, %v", err)
+	if err := v.UnmarshalKey("Preload", &s.Preload); err != nil {
+		return nil, fmt.Errorf("PreloadSetting 解析失败, %v", err)
 	}
-	if err := viper.UnmarshalKey("Subtitle", &Subtitle); err != nil {
-		return fmt.Errorf("SubtitleSetting  解析失败, %v", err)
+	if err := v.UnmarshalKey("MediaSource", &s.MediaSource); err != nil {
+		return nil, fmt.Errorf("MediaSourceSetting 解析失败, %v", err)
 	}
-	Debug = viper.GetBool("Debug")
+	if err := v.UnmarshalKey("HTTPStrm", &s.HTTPStrm); err != nil {
+		return nil, fmt.Errorf("HTTPStrmSetting 解析失败, %v", err)
+	}
+	if err := v.UnmarshalKey("UserAgent", &s.UserAgent); err != nil {
+		return nil, fmt.Errorf("UserAgentSetting 解析失败, %v", err)
+	}
+	if err := v.UnmarshalKey("Rewriters", &s.Rewriters); err != nil {
+		return nil, fmt.Errorf("Rewriters 解析失败, %v", err)
+	}
+	if err := v.UnmarshalKey("Rules", &s.Rules); err != nil {
+		return nil, fmt.Errorf("Rules 解析失败, %v", err)
+	}
+	s.Debug = v.GetBool("Debug")
+	return s, nil
+}
+
+// 读取上游媒体服务器设置
+//
+// 配置了 MediaServers 时使用其中的全部上游，否则使用 MediaServer 作为唯一上游
+func (s *Snapshot) loadMediaServers(v *viper.Viper) error {
+	if v.IsSet("MediaServers") {
+		if err := v.UnmarshalKey("MediaServers", &s.MediaServers); err != nil {
+			return fmt.Errorf("MediaServers 解析失败, %v", err)
+		}
+	}
+	if len(s.MediaServers) == 0 {
+		if err := v.UnmarshalKey("MediaServer", &s.MediaServer); err != nil {
+			return fmt.Errorf("MediaServerSetting 解析失败, %v", err)
+		}
+		s.MediaServers = []MediaServerSetting{s.MediaServer}
+	}
+
+	for i := range s.MediaServers {
+		if s.MediaServers[i].Name == "" {
+			s.MediaServers[i].Name = fmt.Sprintf("upstream-%d", i+1)
+		}
+		if prefix := s.MediaServers[i].PathPrefix; prefix != "" {
+			s.MediaServers[i].PathPrefix = "/" + strings.Trim(prefix, "/")
+		}
+	}
+	s.MediaServer = s.MediaServers[0]
 	return nil
 }
 
+// 验证配置快照
+//
+// 收集全部错误后一次返回，不影响运行的问题记录为快照的警告
+func (s *Snapshot) validate() error {
+	validator := NewConfigValidator()
+	validator.ValidatePort("Port", s.Port)
+
+	names := make([]string, 0, len(s.MediaServers))
+	for i, server := range s.MediaServers {
+		fieldPrefix := fmt.Sprintf("MediaServers[%s]", server.Name)
+		if len(s.MediaServers) == 1 && i == 0 {
+			fieldPrefix = "MediaServer"
+		}
+		validator.ValidateRequired(fieldPrefix+".ADDR", server.ADDR)
+		validator.ValidateAddr(fieldPrefix+".ADDR", server.ADDR)
+		validator.ValidateRequired(fieldPrefix+".Type", string(server.Type))
+		validator.ValidateEnum(fieldPrefix+".Type", string(server.Type), []string{string(constants.EMBY), string(constants.JELLYFIN), string(constants.PLEX)})
+		names = append(names, server.Name)
+	}
+	validator.ValidateUnique("MediaServers.Name", names)
+
+	names = names[:0]
+	for i, server := range s.MediaSync {
+		fieldPrefix := fmt.Sprintf("MediaSync[%d]", i)
+		if server.Name != "" {
+			fieldPrefix = fmt.Sprintf("MediaSync[%s]", server.Name)
+		}
+		validator.ValidateRequired(fieldPrefix+".Remote", server.Remote)
+		validator.ValidateRequired(fieldPrefix+".LocalPath", server.LocalPath)
+		validator.ValidatePath(fieldPrefix+".LocalPath", server.LocalPath, false)
+		validator.ValidatePathExists(fieldPrefix+".LocalPath", server.LocalPath)
+		validator.ValidateAddr(fieldPrefix+".Resolver.ADDR", server.Resolver.ADDR)
+		names = append(names, server.Name)
+	}
+	validator.ValidateUnique("MediaSync.Name", names)
+
+	if s.ClientFilter.Enable {
+		validator.ValidateRequired("ClientFilter.Mode", string(s.ClientFilter.Mode))
+		validator.ValidateEnum("ClientFilter.Mode", string(s.ClientFilter.Mode), []string{string(constants.WHITELIST), string(constants.BLACKLIST)})
+	}
+	validator.ValidateASSStyle("Subtitle.ASSStyle", s.Subtitle.ASSStyle)
+	chineseConvertModes := []string{string(constants.ChineseConvertNone), string(constants.ChineseConvertS2T), string(constants.ChineseConvertT2S)}
+	validator.ValidateEnum("Subtitle.ChineseConvert.Mode", string(s.Subtitle.ChineseConvert.Mode), chineseConvertModes)
+	for i, rule := range s.Subtitle.ChineseConvert.Rules {
+		validator.ValidateEnum(fmt.Sprintf("Subtitle.ChineseConvert.Rules[%d].Mode", i), string(rule.Mode), chineseConvertModes)
+	}
+	validator.ValidateEnum("MediaSource.FailingPolicy", string(s.MediaSource.FailingPolicy), []string{string(constants.SourcePolicyNone), string(constants.SourcePolicySort), string(constants.SourcePolicyHide)})
+	validator.validateRouteRules(s.Rules)
+	s.warnings = validator.GetWarnings()
+	return validator.Err()
+}
+
+// 使用快照替换当前配置
+func (s *Snapshot) apply() {
+	settings.Store(s)
+}
+
+// 当前配置的快照
+func current() *Snapshot {
+	if s := settings.Load(); s != nil {
+		return s
+	}
+	return &Snapshot{}
+}
+
+// 修改当前配置
+//
+// 复制当前配置，调用 modify 修改后编译自定义路由规则并整体替换，返回恢复原配置的函数；用于测试
+func Update(modify func(s *Snapshot)) (restore func()) {
+	previous := current()
+	s := *previous
+	modify(&s)
+	s.Rules = slices.Clone(s.Rules)
+	NewConfigValidator().validateRouteRules(s.Rules)
+	s.apply()
+	return previous.apply
+}
+
 // 创建文件夹
 func createDir() error {
 	if err := os.MkdirAll(ConfigDir(), os.ModePerm); err != nil {
@@ -0,0 +1,230 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	envPrefix     = "MEDIAWARP" // 环境变量前缀，如 MEDIAWARP_MEDIASERVER_AUTH 对应 MediaServer.AUTH，MEDIAWARP_MEDIASERVERS_0_AUTH 对应 MediaServers[0].AUTH
+	envFileSuffix = "_FILE"     // 从文件读取配置值的环境变量后缀，如 MEDIAWARP_MEDIASERVER_AUTH_FILE=/run/secrets/emby_api_key
+)
+
+const mediaServersKey = "mediaservers" // 上游媒体服务器列表的配置项
+
+var envKeyReplacer = strings.NewReplacer(".", "_")
+
+// 全部配置项，按配置快照的字段生成（如 mediaserver.auth）
+var configKeys = sync.OnceValue(func() []string {
+	return structKeys(reflect.TypeOf(Snapshot{}), "")
+})
+
+// 上游媒体服务器的配置项（如 auth）
+var mediaServerKeys = sync.OnceValue(func() []string {
+	return structKeys(reflect.TypeOf(MediaServerSetting{}), "")
+})
+
+// 结构体中的配置项
+//
+// 本包中的结构体展开为子配置项，其余类型（包括列表）作为单个配置项
+func structKeys(t reflect.Type, prefix string) []string {
+	var keys []string
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key := strings.ToLower(field.Name)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if field.Type.Kind() == reflect.Struct && field.Type.PkgPath() == t.PkgPath() {
+			keys = append(keys, structKeys(field.Type, key)...)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// 配置项对应的环境变量名
+func envName(key string) string {
+	return envPrefix + "_" + strings.ToUpper(envKeyReplacer.Replace(key))
+}
+
+// 读取环境变量中的配置
+//
+// MEDIAWARP_ 开头的环境变量覆盖配置文件中的同名配置项，列表使用逗号分隔；
+// 上游媒体服务器列表不绑定（MEDIAWARP_MEDIASERVERS 无法解析为列表），其配置项见 mergeMediaServersEnv。
+// 每个 viper 实例只需设置一次，热重载时重新读取配置文件后仍然生效
+func bindEnv(v *viper.Viper) error {
+	v.SetEnvPrefix(envPrefix)
+	v.SetEnvKeyReplacer(envKeyReplacer)
+	for _, key := range configKeys() {
+		if key == mediaServersKey {
+			continue
+		}
+		if err := v.BindEnv(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 合并环境变量中的配置
+//
+// 带 _FILE 后缀时从文件读取配置值（如 Docker Secrets），去除首尾空白字符；
+// 上游媒体服务器列表的配置项见 mergeMediaServersEnv。
+// 返回合并后的新实例，不修改 v 中的配置，热重载时仍读取配置文件的最新内容
+func mergeEnv(v *viper.Viper) (*viper.Viper, error) {
+	values := v.AllSettings()
+	for _, key := range configKeys() {
+		name := envName(key)
+		if _, ok := os.LookupEnv(name + envFileSuffix); !ok {
+			continue
+		}
+		value, _, err := lookupEnv(name)
+		if err != nil {
+			return nil, err
+		}
+		setNested(values, strings.Split(key, "."), value)
+	}
+	if err := mergeMediaServersEnv(values); err != nil {
+		return nil, err
+	}
+
+	merged := viper.New()
+	if err := merged.MergeConfigMap(values); err != nil {
+		return nil, fmt.Errorf("合并环境变量失败: %v", err)
+	}
+	return merged, nil
+}
+
+// 合并上游媒体服务器列表的环境变量
+//
+// MEDIAWARP_MEDIASERVERS_<序号>_<配置项> 覆盖配置文件中对应上游的配置项，如 MEDIAWARP_MEDIASERVERS_1_AUTH_FILE；
+// 配置文件未配置 MediaServers 时按带序号的环境变量生成列表，MediaServer 作为列表的第一项。
+// MEDIAWARP_MEDIASERVER_<配置项> 同时作用于默认上游（列表的第一项），带序号的环境变量优先
+func mergeMediaServersEnv(values map[string]any) error {
+	servers, ok := values[mediaServersKey].([]any)
+	if !ok {
+		count := mediaServersEnvCount()
+		if count == 0 {
+			return nil
+		}
+		servers = make([]any, count)
+		for i := range servers {
+			servers[i] = make(map[string]any)
+		}
+		if server, ok := values["mediaserver"].(map[string]any); ok {
+			for key, value := range server {
+				servers[0].(map[string]any)[key] = value
+			}
+		}
+		values[mediaServersKey] = servers
+	}
+	for i, server := range servers {
+		setting, ok := server.(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, key := range mediaServerKeys() {
+			names := []string{mediaServersEnvName(i, key)}
+			if i == 0 {
+				names = append(names, envName("mediaserver."+key))
+			}
+			for _, name := range names {
+				value, ok, err := lookupEnv(name)
+				if err != nil {
+					return err
+				}
+				if ok {
+					setFold(setting, key, value)
+					break
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// 上游媒体服务器列表的配置项对应的环境变量名
+func mediaServersEnvName(index int, key string) string {
+	return fmt.Sprintf("%s_MEDIASERVERS_%d_%s", envPrefix, index, strings.ToUpper(key))
+}
+
+// 带序号的环境变量配置的上游数量（最大序号 + 1），未设置时返回 0
+func mediaServersEnvCount() int {
+	prefix := envPrefix + "_MEDIASERVERS_"
+	count := 0
+	for _, env := range os.Environ() {
+		name, value, _ := strings.Cut(env, "=")
+		if !strings.HasPrefix(name, prefix) || value == "" {
+			continue
+		}
+		index, key, ok := strings.Cut(strings.TrimPrefix(name, prefix), "_")
+		if !ok {
+			continue
+		}
+		i, err := strconv.Atoi(index)
+		if err != nil || i < count {
+			continue
+		}
+		key = strings.TrimSuffix(key, envFileSuffix)
+		for _, k := range mediaServerKeys() {
+			if strings.EqualFold(k, key) {
+				count = i + 1
+				break
+			}
+		}
+	}
+	return count
+}
+
+// 读取环境变量的值
+//
+// 设置了 _FILE 后缀的环境变量时从文件读取；均未设置或值为空时返回 false
+func lookupEnv(name string) (string, bool, error) {
+	path, ok := os.LookupEnv(name + envFileSuffix)
+	if !ok {
+		value := os.Getenv(name)
+		return value, value != "", nil
+	}
+	if os.Getenv(name) != "" {
+		return "", false, fmt.Errorf("环境变量 %s 和 %s 不能同时设置", name, name+envFileSuffix)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("读取环境变量 %s 指定的文件失败: %v", name+envFileSuffix, err)
+	}
+	return strings.TrimSpace(string(content)), true, nil
+}
+
+// 设置配置中的值，键不区分大小写
+func setFold(values map[string]any, key string, value any) {
+	for k := range values {
+		if strings.EqualFold(k, key) {
+			delete(values, k)
+		}
+	}
+	values[key] = value
+}
+
+// 设置嵌套配置中的值
+func setNested(values map[string]any, path []string, value any) {
+	for _, key := range path[:len(path)-1] {
+		child, ok := values[key].(map[string]any)
+		if !ok {
+			child = make(map[string]any)
+			values[key] = child
+		}
+		values = child
+	}
+	values[path[len(path)-1]] = value
+}
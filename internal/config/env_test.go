@@ -0,0 +1,141 @@
+package config_test
+
+import (
+	"MediaWarp/internal/config"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestEnvOverride(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	content := "Port: 9096\nMediaServer:\n  Type: Emby\n  ADDR: 127.0.0.1:8096\n  AUTH: plain-text-key\nWeb:\n  Danmaku: False\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	secretPath := filepath.Join(dir, "emby_api_key")
+	if err := os.WriteFile(secretPath, []byte("5fc6d09d96c34eb7b0636f0e770d3c68\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("MEDIAWARP_PORT", "9097")
+	t.Setenv("MEDIAWARP_WEB_DANMAKU", "true")
+	t.Setenv("MEDIAWARP_CLIENTFILTER_CLIENTLIST", "Infuse,VidHub")
+	t.Setenv("MEDIAWARP_MEDIASERVER_AUTH_FILE", secretPath)
+	if err := config.Init(path); err != nil {
+		t.Fatal(err)
+	}
+	if config.Port() != 9097 || !config.Web().Danmaku {
+		t.Errorf("环境变量未覆盖配置文件：%d %v", config.Port(), config.Web().Danmaku)
+	}
+	if !slices.Equal(config.ClientFilter().ClientList, []string{"Infuse", "VidHub"}) {
+		t.Errorf("列表解析错误：%v", config.ClientFilter().ClientList)
+	}
+	if config.MediaServer().AUTH != "5fc6d09d96c34eb7b0636f0e770d3c68" || config.MediaServers()[0].AUTH != config.MediaServer().AUTH {
+		t.Errorf("未从文件读取 AUTH：%s", config.MediaServer().AUTH)
+	}
+	if config.MediaServer().ADDR != "127.0.0.1:8096" {
+		t.Errorf("未设置环境变量的配置项应保留配置文件中的值：%s", config.MediaServer().ADDR)
+	}
+
+	t.Setenv("MEDIAWARP_MEDIASERVER_AUTH", "another-key")
+	if err := config.ValidateConfig(path); err == nil || !strings.Contains(err.Error(), "不能同时设置") {
+		t.Errorf("同时设置环境变量和 _FILE 时应返回错误，实际: %v", err)
+	}
+
+	t.Setenv("MEDIAWARP_MEDIASERVER_AUTH", "")
+	t.Setenv("MEDIAWARP_MEDIASERVER_AUTH_FILE", filepath.Join(dir, "missing"))
+	if err := config.ValidateConfig(path); err == nil || !strings.Contains(err.Error(), "MEDIAWARP_MEDIASERVER_AUTH_FILE") {
+		t.Errorf("文件不存在时应返回错误，实际: %v", err)
+	}
+}
+
+func TestEnvOverrideMediaServers(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	content := "Port: 9096\nMediaServers:\n  - Name: 4K\n    Type: Emby\n    ADDR: 127.0.0.1:8096\n    AUTH: plain-text-key\n  - Name: 1080P\n    Type: Emby\n    ADDR: 127.0.0.1:8097\n    PathPrefix: /1080p\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	secretPath := filepath.Join(dir, "emby_api_key")
+	if err := os.WriteFile(secretPath, []byte("5fc6d09d96c34eb7b0636f0e770d3c68\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("MEDIAWARP_MEDIASERVER_AUTH_FILE", secretPath)
+	t.Setenv("MEDIAWARP_MEDIASERVERS_0_HOSTS", "4k.example.com,uhd.example.com")
+	t.Setenv("MEDIAWARP_MEDIASERVERS_1_AUTH", "another-key")
+	if err := config.Init(path); err != nil {
+		t.Fatal(err)
+	}
+	servers := config.MediaServers()
+	if len(servers) != 2 {
+		t.Fatalf("上游数量错误：%d", len(servers))
+	}
+	if servers[0].AUTH != "5fc6d09d96c34eb7b0636f0e770d3c68" || config.MediaServer().AUTH != servers[0].AUTH {
+		t.Errorf("MEDIAWARP_MEDIASERVER_AUTH_FILE 应作用于第一个上游：%s", servers[0].AUTH)
+	}
+	if !slices.Equal(servers[0].Hosts, []string{"4k.example.com", "uhd.example.com"}) {
+		t.Errorf("带序号的列表配置项解析错误：%v", servers[0].Hosts)
+	}
+	if servers[1].AUTH != "another-key" || servers[1].ADDR != "127.0.0.1:8097" {
+		t.Errorf("带序号的环境变量未覆盖对应上游：%+v", servers[1])
+	}
+
+	t.Setenv("MEDIAWARP_MEDIASERVERS_0_AUTH", "indexed-key")
+	if err := config.Init(path); err != nil {
+		t.Fatal(err)
+	}
+	if auth := config.MediaServers()[0].AUTH; auth != "indexed-key" {
+		t.Errorf("带序号的环境变量应优先：%s", auth)
+	}
+}
+
+func TestEnvMediaServersWithoutList(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	content := "Port: 9096\nMediaServer:\n  Type: Emby\n  ADDR: 127.0.0.1:8096\n  AUTH: plain-text-key\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("MEDIAWARP_MEDIASERVERS", "stray")
+	t.Setenv("MEDIAWARP_MEDIASERVERS_1_NAME", "1080P")
+	t.Setenv("MEDIAWARP_MEDIASERVERS_1_TYPE", "Emby")
+	t.Setenv("MEDIAWARP_MEDIASERVERS_1_ADDR", "127.0.0.1:8097")
+	t.Setenv("MEDIAWARP_MEDIASERVERS_1_PATHPREFIX", "/1080p")
+	t.Setenv("MEDIAWARP_MEDIASERVERS_1_AUTH", "another-key")
+	if err := config.Init(path); err != nil {
+		t.Fatal(err)
+	}
+	servers := config.MediaServers()
+	if len(servers) != 2 {
+		t.Fatalf("应按带序号的环境变量生成上游列表：%+v", servers)
+	}
+	if servers[0].ADDR != "127.0.0.1:8096" || servers[0].AUTH != "plain-text-key" || config.MediaServer().ADDR != servers[0].ADDR {
+		t.Errorf("MediaServer 应作为第一个上游：%+v", servers[0])
+	}
+	if servers[1].Name != "1080P" || servers[1].ADDR != "127.0.0.1:8097" || servers[1].AUTH != "another-key" || servers[1].PathPrefix != "/1080p" {
+		t.Errorf("带序号的环境变量解析错误：%+v", servers[1])
+	}
+}
+
+func TestEnvStrayMediaServers(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	content := "Port: 9096\nMediaServers:\n  - Name: 4K\n    Type: Emby\n    ADDR: 127.0.0.1:8096\n    AUTH: plain-text-key\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("MEDIAWARP_MEDIASERVERS", "stray")
+	if err := config.Init(path); err != nil {
+		t.Fatal(err)
+	}
+	if servers := config.MediaServers(); len(servers) != 1 || servers[0].Name != "4K" {
+		t.Errorf("MEDIAWARP_MEDIASERVERS 不应覆盖配置文件中的上游列表：%+v", servers)
+	}
+}
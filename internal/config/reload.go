@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// 配置文件变化后等待的时间，编辑器保存时可能连续触发多次写入事件
+const watchDebounce = time.Second
+
+var (
+	reloadMutex sync.Mutex
+	reloadHooks []func() error
+)
+
+// 注册热重载后执行的函数
+//
+// 按注册顺序执行，用于按新配置重建路由规则、中间件等；任一函数出错时恢复原配置
+func OnReload(hook func() error) {
+	reloadMutex.Lock()
+	defer reloadMutex.Unlock()
+	reloadHooks = append(reloadHooks, hook)
+}
+
+// 重新加载配置文件
+//
+// 解析并验证配置文件，验证通过后替换全局配置并执行 OnReload 注册的函数；
+// 验证失败时不修改全局配置，执行函数出错时恢复原配置并重新执行。
+// 返回已修改但需要重启才能生效的配置项
+func Reload() ([]string, error) {
+	reloadMutex.Lock()
+	defer reloadMutex.Unlock()
+
+	if err := viper.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("读取配置文件失败: %v", err)
+	}
+	s, err := parseConfig(viper.GetViper())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+
+	previous := current()
+	s.apply()
+	if err := runReloadHooks(); err != nil {
+		previous.apply()
+		runReloadHooks()
+		return nil, fmt.Errorf("应用新配置失败，已恢复原配置: %w", err)
+	}
+	return s.restartRequired(previous), nil
+}
+
+// 已修改但需要重启才能生效的配置项
+func (s *Snapshot) restartRequired(previous *Snapshot) []string {
+	var fields []string
+	if s.Port != previous.Port {
+		fields = append(fields, "Port")
+	}
+	if !reflect.DeepEqual(s.Logger, previous.Logger) {
+		fields = append(fields, "Logger")
+	}
+	if s.Cache.Persist != previous.Cache.Persist || s.Cache.Path != previous.Cache.Path {
+		fields = append(fields, "Cache.Persist", "Cache.Path")
+	}
+	return fields
+}
+
+// 执行热重载函数
+func runReloadHooks() error {
+	for _, hook := range reloadHooks {
+		if err := hook(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 监听配置文件变化
+//
+// 文件变化且不再继续变化后调用 reload 重新加载配置
+func Watch(reload func()) {
+	var (
+		timer *time.Timer
+		mutex sync.Mutex
+	)
+	viper.OnConfigChange(func(event fsnotify.Event) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.AfterFunc(watchDebounce, reload)
+	})
+	viper.WatchConfig()
+}
@@ -0,0 +1,74 @@
+package config_test
+
+import (
+	"MediaWarp/internal/config"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig := func(port int, extra string) {
+		content := fmt.Sprintf("Port: %d\nMediaServer:\n  Type: Emby\n  ADDR: 127.0.0.1:8096\n", port) + extra
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeConfig(9096, "ClientFilter:\n  Enable: True\n  Mode: BlackList\n  ClientList: [Infuse]\n")
+	if err := config.Init(path); err != nil {
+		t.Fatal(err)
+	}
+	var (
+		reloads   int
+		hookError error
+	)
+	config.OnReload(func() error {
+		reloads++
+		return hookError
+	})
+
+	writeConfig(9096, "ClientFilter:\n  Enable: True\n  Mode: BlackList\n  ClientList: [Infuse, VidHub]\nWeb:\n  Danmaku: True\n")
+	restartRequired, err := config.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(restartRequired) != 0 {
+		t.Errorf("不应需要重启：%v", restartRequired)
+	}
+	if !slices.Equal(config.ClientFilter().ClientList, []string{"Infuse", "VidHub"}) || !config.Web().Danmaku || reloads != 1 {
+		t.Errorf("配置未更新：%v %v %d", config.ClientFilter().ClientList, config.Web().Danmaku, reloads)
+	}
+
+	writeConfig(9096, "Rules:\n  - Name: Bad\n    Match: \"(\"\n    Action: Block\n")
+	if _, err := config.Reload(); err == nil || !strings.Contains(err.Error(), "Rules[Bad].Match") {
+		t.Errorf("无效配置应返回错误，实际: %v", err)
+	}
+	if !config.Web().Danmaku || reloads != 1 {
+		t.Error("验证失败时不应修改配置")
+	}
+
+	hookError = errors.New("上游不可用")
+	writeConfig(9096, "Web:\n  Danmaku: False\n")
+	if _, err := config.Reload(); err == nil {
+		t.Error("重建组件失败时应返回错误")
+	}
+	if !config.Web().Danmaku || reloads != 3 {
+		t.Errorf("重建组件失败时应恢复原配置：%v %d", config.Web().Danmaku, reloads)
+	}
+
+	hookError = nil
+	writeConfig(9097, "")
+	restartRequired, err = config.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(restartRequired, []string{"Port"}) {
+		t.Errorf("修改端口应需要重启，实际: %v", restartRequired)
+	}
+}
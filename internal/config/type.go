@@ -1,6 +1,9 @@
 package config
 
-import "MediaWarp/constants"
+import (
+	"MediaWarp/constants"
+	"regexp"
+)
 
 // 程序版本信息
 type VersionInfo struct {
@@ -14,10 +17,13 @@ type VersionInfo struct {
 
 // 上游媒体服务器相关设置
 type MediaServerSetting struct {
-	Type   constants.MediaServerType // 媒体服务器类型
-	ADDR   string                    // 地址
-	AUTH   string                    // 认证授权KEY
-	Cookie string                    // Cookie
+	Name       string                    // 名称（配置多个上游时用于区分）
+	Type       constants.MediaServerType // 媒体服务器类型
+	ADDR       string                    // 地址
+	AUTH       string                    // 认证授权KEY
+	Cookie     string                    // Cookie
+	Hosts      []string                  // 按 Host 请求头路由至该上游
+	PathPrefix string                    // 按路径前缀路由至该上游（转发前去除前缀，仅支持 API 客户端，Web 客户端请使用 Hosts）
 }
 
 // 日志设置
@@ -44,6 +50,7 @@ type WebSetting struct {
 	FanartShow        bool   // 显示同人图（fanart图）
 	Danmaku           bool   // Web 弹幕
 	VideoTogether     bool   // VideoTogether
+	SubtitleOffset    bool   // 字幕时间轴调整
 }
 
 // 客户端User-Agent过滤设置
@@ -53,63 +60,178 @@ type ClientFilterSetting struct {
 	ClientList []string
 }
 
+// HTTPStrm 设置
+type HTTPStrmSetting struct {
+	Enable           bool
+	TransCode        bool     // 是否允许转码，False 时强制关闭转码
+	TransCodeClients []string // 允许转码的客户端（User-Agent 规范化后的类别或 User-Agent 关键字），为空时允许全部客户端
+	TransCodeCodecs  []string // 允许转码的视频编码（如 hevc），为空时允许全部编码
+	PrefixList       []string
+}
+
 // MediaSync 媒体服务器配置（合并 HTTPStrm 和 RcloneSync）
 type MediaSyncSetting []MediaSyncServerSetting
 
 // MediaSyncServerSetting 媒体同步服务器设置
 type MediaSyncServerSetting struct {
-	Name      string `yaml:"Name" json:"name"`
-	Remote    string `yaml:"Remote" json:"remote"`
-	LocalPath string `yaml:"LocalPath" json:"local_path"`
+	Name      string          `yaml:"Name" json:"name"`
+	Remote    string          `yaml:"Remote" json:"remote"`
+	LocalPath string          `yaml:"LocalPath" json:"local_path"`
+	Resolver  ResolverSetting `yaml:"Resolver" json:"resolver"` // Strm 链接解析器设置
+}
+
+// 链接解析器设置
+//
+// 解析器注册在 Scheme 下，Scheme 为空时使用 Remote 的名称（如 115: -> 115）
+type ResolverSetting struct {
+	Type       string `yaml:"Type" json:"type"`              // 解析器类型：rclone（默认）、direct、http、alist、webdav
+	Scheme     string `yaml:"Scheme" json:"scheme"`          // Strm 链接的协议名称
+	ADDR       string `yaml:"ADDR" json:"addr"`              // alist、webdav 服务器地址
+	Token      string `yaml:"Token" json:"token"`            // alist 令牌
+	Username   string `yaml:"Username" json:"username"`      // webdav 用户名
+	Password   string `yaml:"Password" json:"password"`      // webdav 密码
+	PathPrefix string `yaml:"PathPrefix" json:"path_prefix"` // 拼接在链接路径前的前缀
+}
+
+// 代理推流设置
+//
+// 匹配的客户端不再 302 重定向，由 MediaWarp 转发视频流
+type ProxyStreamSetting struct {
+	Enable     bool
+	ClientList []string // User-Agent 包含其中任意一项时使用代理推流
+	DeviceList []string // 设备 ID 为其中任意一项时使用代理推流
+}
+
+// AlistStrm 设置
+type AlistStrmSetting struct {
+	Enable bool
+	List   []AlistSetting
+}
+
+// Alist 服务器设置
+type AlistSetting struct {
+	ADDR       string   // Alist/OpenList 服务器地址
+	Token      string   // 令牌
+	PrefixList []string // Strm 文件路径前缀，匹配的 Strm 文件使用该 Alist 服务器
+	RawURL     bool     // 是否重定向至 raw_url，否则重定向至 Alist 的 /d 下载链接
 }
 
 // 字幕设置
 type SubtitleSetting struct {
-	Enable   bool
-	SRT2ASS  bool // SRT 字幕转 ASS 字幕
-	ASSStyle []string
-	SubSet   bool // ASS 字幕字体子集化
+	Enable         bool
+	ToUTF8         bool // 识别字幕编码（GBK、Big5、Shift_JIS、UTF-16 等）并转为 UTF-8
+	SRT2ASS        bool // 请求 .ass 字幕时将 SRT 字幕转为 ASS 字幕（使用 ASSStyle）
+	ASSStyle       []string
+	SubSet         bool                  // ASS 字幕字体子集化
+	FontDir        string                // 字体目录，为空时使用配置文件目录下的 fonts
+	ChineseConvert ChineseConvertSetting // 字幕繁简转换
+	Bilingual      bool                  // 在 PlaybackInfo 中添加合并中文、英文字幕的双语虚拟字幕流（仅 Emby）
+}
+
+// 字幕繁简转换设置
+type ChineseConvertSetting struct {
+	Mode  constants.ChineseConvertMode // 默认转换模式：None（默认）、S2T（简体转繁体）、T2S（繁体转简体）
+	Rules []ChineseConvertRule         // 按用户或客户端设置转换模式，按顺序匹配第一条
+}
+
+// 字幕繁简转换规则
+type ChineseConvertRule struct {
+	Users   []string                     // 用户名或用户 ID（仅 Emby 支持），为空时匹配所有用户
+	Clients []string                     // 客户端类别（User-Agent 规范化后的 Class）或 User-Agent 子串，为空时匹配所有客户端
+	Mode    constants.ChineseConvertMode // 转换模式
+}
+
+// 多版本媒体源设置
+type MediaSourceSetting struct {
+	FailingPolicy constants.SourcePolicy // 远程存储熔断时媒体源的处理方式：None（默认）、Sort（排至最后）、Hide（隐藏）
+}
+
+// 预加载设置
+//
+// 客户端开始播放时预加载之后的剧集和继续观看列表的下载链接（仅 Emby）
+type PreloadSetting struct {
+	Enable       bool
+	NextEpisodes int // 预加载之后的剧集数
+	ResumeItems  int // 预加载继续观看列表的媒体项数
+}
+
+// 网盘接口限流和熔断设置
+//
+// 每个远程存储（如 115、123）单独限流和熔断，播放、预加载和目录列表共用
+type RemoteLimitSetting struct {
+	Enable           bool
+	QPS              float64           // 默认每秒请求数
+	Burst            int               // 默认突发请求数
+	FailureThreshold int               // 连续失败多少次后熔断
+	Cooldown         int               // 熔断持续时间（秒），之后放行一个探测请求
+	Remotes          []RemoteLimitRule // 单独设置的远程存储
 }
 
-// Config 统一配置结构体（用于验证）
-type Config struct {
-	Server          *ServerConfig      `yaml:"server" json:"server"`
-	MediaServer     *MediaServerConfig `yaml:"media_server" json:"media_server"`
-	HTTPStrmConfigs []*HTTPStrmConfig  `yaml:"http_strm_configs" json:"http_strm_configs"`
-	Log             *LogConfig         `yaml:"log" json:"log"`
-	Features        *FeaturesConfig    `yaml:"features" json:"features"`
+// 远程存储限流规则
+type RemoteLimitRule struct {
+	Remote string  // 远程存储名称（MediaSync 中的 rclone 远程名称，如 115: 或 115）
+	QPS    float64 // 每秒请求数
+	Burst  int     // 突发请求数
 }
 
-// ServerConfig 服务器配置
-type ServerConfig struct {
-	Host string `yaml:"host" json:"host"`
-	Port int    `yaml:"port" json:"port"`
+// User-Agent 规范化设置
+//
+// 将客户端的原始 User-Agent 归类为客户端类别；规则指定 UserAgent 时同一类别共用下载链接缓存和签名
+type UserAgentSetting struct {
+	Enable bool
+	Rules  []UserAgentRule // 按顺序匹配，为空时使用内置规则
 }
 
-// MediaServerConfig 媒体服务器配置
-type MediaServerConfig struct {
-	Host string `yaml:"host" json:"host"`
-	Port int    `yaml:"port" json:"port"`
-	AUTH string `yaml:"auth" json:"auth"`
-	Type string `yaml:"type" json:"type"`
+// User-Agent 规范化规则
+type UserAgentRule struct {
+	Class     string // 客户端类别（如 Infuse）
+	Pattern   string // 匹配原始 User-Agent 的正则表达式
+	UserAgent string // 获取下载链接时使用的 User-Agent，为空时使用客户端的原始 User-Agent，缓存也按原始 User-Agent 区分
 }
 
-// AlistStrmConfig has been removed
+// 缓存设置
+type CacheSetting struct {
+	Persist      bool   // 是否将下载链接和媒体项信息缓存持久化到磁盘，重启后仍然有效
+	Path         string // 持久化缓存文件路径，默认为配置文件目录下的 cache.db
+	RefreshLinks bool   // 在正在观看的下载链接过期前后台刷新
+}
+
+// 响应改写规则
+//
+// Name 与内置改写器相同时禁用内置改写器或调整其优先级，否则添加按 Pattern 替换响应体的自定义改写器
+type RewriterRule struct {
+	Name        string // 改写器名称
+	Disable     bool   // 禁用
+	Priority    int    // 优先级，越小越先执行；为 0 时内置改写器使用默认优先级，自定义改写器在内置改写器之后执行
+	Route       string // 自定义改写器匹配请求路径的正则表达式
+	ContentType string // 自定义改写器匹配的响应 Content-Type 前缀，为空时不限制
+	Pattern     string // 自定义改写器匹配响应体的正则表达式
+	Replacement string // 替换内容，可使用 $1 引用分组
+}
 
-// HTTPStrmConfig HTTP Strm配置
-type HTTPStrmConfig struct {
-	PrefixPath string `yaml:"prefix_path" json:"prefix_path"`
+// 自定义路由规则
+//
+// 按配置顺序在内置路由之前匹配请求路径，Rewrite、Header 规则修改请求后继续匹配之后的规则
+type RouteRule struct {
+	Name         string                    // 规则名称
+	Match        string                    // 匹配请求路径的正则表达式
+	Methods      []string                  // 匹配的请求方法，为空时匹配全部
+	Action       constants.RouteRuleAction // 动作：Redirect、Rewrite、Header、Block、Proxy
+	Target       string                    // Redirect 的跳转地址、Rewrite 的新路径（可使用 $1 引用分组），Proxy 的上游地址
+	Status       int                       // Redirect（默认 302）、Block（默认 403）返回的状态码
+	SetHeaders   []HeaderSetting           // 设置的请求头
+	StripHeaders []string                  // 移除的请求头
+
+	regexp *regexp.Regexp // 验证时编译的 Match
 }
 
-// LogConfig 日志配置
-type LogConfig struct {
-	Level  string `yaml:"level" json:"level"`
-	Output string `yaml:"output" json:"output"`
-	File   string `yaml:"file" json:"file"`
+// Regexp 编译后的匹配表达式，未通过验证的规则返回 nil
+func (rule RouteRule) Regexp() *regexp.Regexp {
+	return rule.regexp
 }
 
-// FeaturesConfig 功能配置
-type FeaturesConfig struct {
-	EnableCache   bool `yaml:"enable_cache" json:"enable_cache"`
-	EnableMetrics bool `yaml:"enable_metrics" json:"enable_metrics"`
+// 请求头设置
+type HeaderSetting struct {
+	Name  string
+	Value string
 }
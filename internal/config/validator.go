@@ -1,7 +1,7 @@
 package config
 
 import (
-	"errors"
+	"MediaWarp/constants"
 	"fmt"
 	"net/url"
 	"os"
@@ -19,12 +19,25 @@ type ValidationError struct {
 }
 
 func (e ValidationError) Error() string {
-	return fmt.Sprintf("配置验证失败 [%s]: %s (当前值: %v)", e.Field, e.Message, e.Value)
+	return fmt.Sprintf("[%s] %s (当前值: %v)", e.Field, e.Message, e.Value)
+}
+
+// ValidationErrors 配置验证错误列表，一次报告全部错误
+type ValidationErrors []ValidationError
+
+func (errs ValidationErrors) Error() string {
+	lines := make([]string, 0, len(errs)+1)
+	lines = append(lines, fmt.Sprintf("配置验证失败，共 %d 处错误：", len(errs)))
+	for _, e := range errs {
+		lines = append(lines, "  - "+e.Error())
+	}
+	return strings.Join(lines, "\n")
 }
 
 // ConfigValidator 配置验证器
 type ConfigValidator struct {
-	errors []ValidationError
+	errors   []ValidationError
+	warnings []ValidationError // 不影响启动和热重载的问题，--check-config 时视为错误
 }
 
 // NewConfigValidator 创建新的配置验证器
@@ -43,6 +56,15 @@ func (cv *ConfigValidator) AddError(field string, value any, message string) {
 	})
 }
 
+// AddWarning 添加验证警告
+func (cv *ConfigValidator) AddWarning(field string, value any, message string) {
+	cv.warnings = append(cv.warnings, ValidationError{
+		Field:   field,
+		Value:   value,
+		Message: message,
+	})
+}
+
 // HasErrors 检查是否有错误
 func (cv *ConfigValidator) HasErrors() bool {
 	return len(cv.errors) > 0
@@ -53,17 +75,17 @@ func (cv *ConfigValidator) GetErrors() []ValidationError {
 	return cv.errors
 }
 
-// GetErrorsAsString 获取错误字符串
-func (cv *ConfigValidator) GetErrorsAsString() string {
+// Err 没有错误时返回 nil，否则返回包含全部错误的 ValidationErrors
+func (cv *ConfigValidator) Err() error {
 	if !cv.HasErrors() {
-		return ""
+		return nil
 	}
+	return ValidationErrors(cv.errors)
+}
 
-	var messages []string
-	for _, err := range cv.errors {
-		messages = append(messages, err.Error())
-	}
-	return strings.Join(messages, "\n")
+// GetWarnings 获取所有警告
+func (cv *ConfigValidator) GetWarnings() []ValidationError {
+	return cv.warnings
 }
 
 // ValidateRequired 验证必填字段
@@ -142,6 +164,18 @@ func (cv *ConfigValidator) ValidatePath(field string, value string, mustExist bo
 	}
 }
 
+// ValidatePathExists 验证路径是否存在
+//
+// 路径可能在启动后才挂载，不存在时只添加警告
+func (cv *ConfigValidator) ValidatePathExists(field string, value string) {
+	if value == "" {
+		return // 空值由 ValidateRequired 处理
+	}
+	if _, err := os.Stat(value); os.IsNotExist(err) {
+		cv.AddWarning(field, value, "路径不存在")
+	}
+}
+
 // ValidateRegex 验证正则表达式
 func (cv *ConfigValidator) ValidateRegex(field string, value string) {
 	if value == "" {
@@ -153,6 +187,87 @@ func (cv *ConfigValidator) ValidateRegex(field string, value string) {
 	}
 }
 
+// ValidateAddr 验证服务器地址
+//
+// 未带协议时按 http 处理，需要包含主机，端口可省略
+func (cv *ConfigValidator) ValidateAddr(field string, value string) {
+	if value == "" {
+		return // 空值由 ValidateRequired 处理
+	}
+
+	addr := value
+	if !strings.Contains(addr, "://") {
+		addr = "http://" + addr
+	}
+	u, err := url.Parse(addr)
+	if err != nil {
+		cv.AddError(field, value, "地址格式不正确: "+err.Error())
+		return
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		cv.AddError(field, value, "地址协议必须是 http 或 https")
+		return
+	}
+	if u.Hostname() == "" {
+		cv.AddError(field, value, "地址缺少主机名")
+		return
+	}
+	if port := u.Port(); port != "" {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			cv.AddError(field, value, "地址中的端口号必须在1-65535之间")
+		}
+	}
+}
+
+// ValidateUnique 验证名称不重复
+func (cv *ConfigValidator) ValidateUnique(field string, values []string) {
+	seen := make(map[string]bool, len(values))
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		if seen[value] {
+			cv.AddError(field, value, "名称重复")
+		}
+		seen[value] = true
+	}
+}
+
+// ValidateASSStyle 验证 ASS 字幕样式
+//
+// 第一行为 Format 行，之后为字段数与 Format 行相同的 Style 行，需要包含字幕使用的 Default 样式
+func (cv *ConfigValidator) ValidateASSStyle(field string, lines []string) {
+	if len(lines) == 0 {
+		return // 使用默认样式
+	}
+
+	format, ok := strings.CutPrefix(strings.TrimSpace(lines[0]), "Format:")
+	if !ok {
+		cv.AddError(field+"[0]", lines[0], "第一行必须是 Format: 开头的格式行")
+		return
+	}
+	fields := len(strings.Split(format, ","))
+	hasDefault := false
+	for i, line := range lines[1:] {
+		style, ok := strings.CutPrefix(strings.TrimSpace(line), "Style:")
+		if !ok {
+			cv.AddError(fmt.Sprintf("%s[%d]", field, i+1), line, "必须是 Style: 开头的样式行")
+			continue
+		}
+		values := strings.Split(style, ",")
+		if len(values) != fields {
+			cv.AddError(fmt.Sprintf("%s[%d]", field, i+1), line, fmt.Sprintf("字段数为 %d，与 Format 行的 %d 个字段不一致", len(values), fields))
+			continue
+		}
+		if strings.TrimSpace(values[0]) == "Default" {
+			hasDefault = true
+		}
+	}
+	if !hasDefault {
+		cv.AddError(field, lines, "缺少名为 Default 的样式")
+	}
+}
+
 // ValidateEnum 验证枚举值
 func (cv *ConfigValidator) ValidateEnum(field string, value string, validValues []string) {
 	if value == "" {
@@ -175,72 +290,67 @@ func (cv *ConfigValidator) ValidateRange(field string, value, min, max int) {
 	}
 }
 
-// ValidateConfig 验证完整配置
-func ValidateConfig(cfg *Config) error {
+// ValidateRouteRules 验证并编译自定义路由规则
+func ValidateRouteRules(rules []RouteRule) error {
 	validator := NewConfigValidator()
+	validator.validateRouteRules(rules)
+	return validator.Err()
+}
 
-	// 验证媒体服务器配置
-	if cfg.MediaServer != nil {
-		validator.ValidateRequired("MediaServer.Host", cfg.MediaServer.Host)
-		validator.ValidatePort("MediaServer.Port", cfg.MediaServer.Port)
-		validator.ValidateURL("MediaServer.Host", fmt.Sprintf("http://%s:%d", cfg.MediaServer.Host, cfg.MediaServer.Port))
-		validator.ValidateRequired("MediaServer.AUTH", cfg.MediaServer.AUTH)
-
-		// 验证服务器类型
-		if cfg.MediaServer.Type != "" {
-			validator.ValidateEnum("MediaServer.Type", cfg.MediaServer.Type, []string{"emby"})
-		}
-	}
-
-	// Alist support has been removed
-
-	// 验证HTTP Strm配置
-	if cfg.HTTPStrmConfigs != nil {
-		for i, httpConfig := range cfg.HTTPStrmConfigs {
-			fieldPrefix := fmt.Sprintf("HTTPStrmConfigs[%d]", i)
-
-			validator.ValidateRequired(fieldPrefix+".PrefixPath", httpConfig.PrefixPath)
-			validator.ValidatePath(fieldPrefix+".PrefixPath", httpConfig.PrefixPath, false)
-		}
+// 验证自定义路由规则并编译 Match，错误添加到验证器中
+func (cv *ConfigValidator) validateRouteRules(rules []RouteRule) {
+	actions := []string{
+		string(constants.RouteRuleRedirect),
+		string(constants.RouteRuleRewrite),
+		string(constants.RouteRuleHeader),
+		string(constants.RouteRuleBlock),
+		string(constants.RouteRuleProxy),
 	}
 
-	// 验证服务器配置
-	if cfg.Server != nil {
-		validator.ValidatePort("Server.Port", cfg.Server.Port)
-		validator.ValidateRange("Server.Port", cfg.Server.Port, 1024, 65535)
-
-		if cfg.Server.Host != "" {
-			validator.ValidateURL("Server.Host", fmt.Sprintf("http://%s:%d", cfg.Server.Host, cfg.Server.Port))
+	for i, rule := range rules {
+		fieldPrefix := fmt.Sprintf("Rules[%d]", i)
+		if rule.Name != "" {
+			fieldPrefix = fmt.Sprintf("Rules[%s]", rule.Name)
 		}
-	}
 
-	// 验证日志配置
-	if cfg.Log != nil {
-		if cfg.Log.Level != "" {
-			validator.ValidateEnum("Log.Level", cfg.Log.Level, []string{"debug", "info", "warn", "error", "fatal"})
+		cv.ValidateRequired(fieldPrefix+".Match", rule.Match)
+		rules[i].regexp = nil
+		if rule.Match != "" {
+			if compiled, err := regexp.Compile(rule.Match); err != nil {
+				cv.AddError(fieldPrefix+".Match", rule.Match, "正则表达式格式不正确: "+err.Error())
+			} else {
+				rules[i].regexp = compiled
+			}
 		}
-
-		if cfg.Log.Output != "" {
-			validator.ValidateEnum("Log.Output", cfg.Log.Output, []string{"stdout", "file", "both"})
+		cv.ValidateRequired(fieldPrefix+".Action", string(rule.Action))
+		cv.ValidateEnum(fieldPrefix+".Action", string(rule.Action), actions)
+		for j, header := range rule.SetHeaders {
+			cv.ValidateRequired(fmt.Sprintf("%s.SetHeaders[%d].Name", fieldPrefix, j), header.Name)
 		}
 
-		if cfg.Log.File != "" {
-			// 验证日志文件目录是否存在
-			dir := filepath.Dir(cfg.Log.File)
-			validator.ValidatePath("Log.File", dir, true)
+		switch rule.Action {
+		case constants.RouteRuleRedirect:
+			cv.ValidateRequired(fieldPrefix+".Target", rule.Target)
+			if rule.Status != 0 {
+				cv.ValidateRange(fieldPrefix+".Status", rule.Status, 300, 308)
+			}
+		case constants.RouteRuleRewrite:
+			cv.ValidateRequired(fieldPrefix+".Target", rule.Target)
+			if rule.Target != "" && !strings.HasPrefix(rule.Target, "/") {
+				cv.AddError(fieldPrefix+".Target", rule.Target, "重写后的路径必须以 / 开头")
+			}
+		case constants.RouteRuleBlock:
+			if rule.Status != 0 {
+				cv.ValidateRange(fieldPrefix+".Status", rule.Status, 400, 599)
+			}
+		case constants.RouteRuleProxy:
+			cv.ValidateRequired(fieldPrefix+".Target", rule.Target)
+			cv.ValidateURL(fieldPrefix+".Target", rule.Target)
+			if target, err := url.Parse(rule.Target); err == nil && rule.Target != "" && (target.Scheme == "" || target.Host == "") {
+				cv.AddError(fieldPrefix+".Target", rule.Target, "上游地址需要包含协议和主机，如 http://127.0.0.1:8096")
+			}
 		}
 	}
-
-	// 验证功能开关
-	if cfg.Features != nil {
-		// 这里可以添加功能相关的验证
-	}
-
-	if validator.HasErrors() {
-		return errors.New(validator.GetErrorsAsString())
-	}
-
-	return nil
 }
 
 // ValidateEnvironment 验证环境变量
@@ -258,40 +368,5 @@ func ValidateEnvironment() error {
 		}
 	}
 
-	if validator.HasErrors() {
-		return errors.New(validator.GetErrorsAsString())
-	}
-
-	return nil
-}
-
-// SanitizeConfig 清理和标准化配置
-func SanitizeConfig(cfg *Config) {
-	// 清理字符串字段的空白字符
-	if cfg.MediaServer != nil {
-		cfg.MediaServer.Host = strings.TrimSpace(cfg.MediaServer.Host)
-		cfg.MediaServer.AUTH = strings.TrimSpace(cfg.MediaServer.AUTH)
-		cfg.MediaServer.Type = strings.ToLower(strings.TrimSpace(cfg.MediaServer.Type))
-	}
-
-	// 标准化路径
-	if cfg.HTTPStrmConfigs != nil {
-		for i := range cfg.HTTPStrmConfigs {
-			cfg.HTTPStrmConfigs[i].PrefixPath = filepath.Clean(cfg.HTTPStrmConfigs[i].PrefixPath)
-		}
-	}
-
-	// 设置默认值
-	if cfg.Server != nil && cfg.Server.Port == 0 {
-		cfg.Server.Port = 8080
-	}
-
-	if cfg.Log != nil {
-		if cfg.Log.Level == "" {
-			cfg.Log.Level = "info"
-		}
-		if cfg.Log.Output == "" {
-			cfg.Log.Output = "stdout"
-		}
-	}
+	return validator.Err()
 }
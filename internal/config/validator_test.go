@@ -0,0 +1,121 @@
+package config_test
+
+import (
+	"MediaWarp/internal/config"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateConfig(t *testing.T) {
+	dir := t.TempDir()
+	localPath := filepath.Join(dir, "media")
+	if err := os.Mkdir(localPath, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for caseName, testCase := range map[string]struct {
+		Content string
+		Want    []string // 期望报告的字段，为空时应验证通过
+	}{
+		"有效配置": {
+			Content: "Port: 9096\n" +
+				"MediaServer:\n  Type: Emby\n  ADDR: 127.0.0.1:8096\n" +
+				"MediaSync:\n  - Name: \"115\"\n    Remote: \"115:\"\n    LocalPath: " + localPath + "\n" +
+				"ClientFilter:\n  Enable: True\n  Mode: WhiteList\n" +
+				"Subtitle:\n  ASSStyle:\n" +
+				"    - \"Format: Name, Fontname, Fontsize\"\n" +
+				"    - \"Style: Default,楷体,20\"\n",
+		},
+		"多处错误": {
+			Content: "Port: 70000\n" +
+				"MediaServers:\n" +
+				"  - Name: a\n    Type: Emby\n    ADDR: ftp://127.0.0.1\n" +
+				"  - Name: a\n    Type: Kodi\n    ADDR: http://127.0.0.1:99999\n" +
+				"MediaSync:\n  - Name: \"115\"\n    LocalPath: " + filepath.Join(dir, "missing") + "\n" +
+				"ClientFilter:\n  Enable: True\n  Mode: GreyList\n",
+			Want: []string{
+				"[Port]",
+				"[MediaServers[a].ADDR] 地址协议",
+				"[MediaServers[a].Type]",
+				"[MediaServers[a].ADDR] 地址中的端口号",
+				"[MediaServers.Name] 名称重复",
+				"[MediaSync[115].Remote]",
+				"[MediaSync[115].LocalPath] 路径不存在",
+				"[ClientFilter.Mode]",
+			},
+		},
+		"缺少媒体服务器地址": {
+			Content: "Port: 9096\nMediaServer:\n  Type: Emby\n",
+			Want:    []string{"[MediaServer.ADDR]"},
+		},
+		"ASS 样式": {
+			Content: "Port: 9096\nMediaServer:\n  Type: Emby\n  ADDR: 127.0.0.1:8096\n" +
+				"Subtitle:\n  ASSStyle:\n" +
+				"    - \"Format: Name, Fontname, Fontsize\"\n" +
+				"    - \"Style: Title,楷体\"\n" +
+				"    - \"Dialogue: 0\"\n",
+			Want: []string{"[Subtitle.ASSStyle[1]] 字段数", "[Subtitle.ASSStyle[2]]", "[Subtitle.ASSStyle] 缺少名为 Default 的样式"},
+		},
+	} {
+		t.Run(caseName, func(t *testing.T) {
+			path := filepath.Join(dir, "config.yaml")
+			if err := os.WriteFile(path, []byte(testCase.Content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			err := config.ValidateConfig(path)
+			if len(testCase.Want) == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			var validationErrors config.ValidationErrors
+			if !errors.As(err, &validationErrors) {
+				t.Fatalf("应返回 ValidationErrors，实际: %v", err)
+			}
+			if len(validationErrors) != len(testCase.Want) {
+				t.Errorf("错误数量错误。期望: %d, 实际: %d\n%v", len(testCase.Want), len(validationErrors), err)
+			}
+			for _, want := range testCase.Want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("验证报告缺少 %s\n%v", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestLocalPathWarning(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	missing := filepath.Join(dir, "missing")
+	content := "Port: 9096\nMediaServer:\n  Type: Emby\n  ADDR: 127.0.0.1:8096\n" +
+		"MediaSync:\n  - Name: \"115\"\n    Remote: \"115:\"\n    LocalPath: " + missing + "\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := config.Init(path); err != nil { // 本地路径可能在启动后才挂载
+		t.Fatalf("本地路径不存在时不应阻止启动：%v", err)
+	}
+	if warnings := config.Warnings(); len(warnings) != 1 || warnings[0].Field != "MediaSync[115].LocalPath" {
+		t.Errorf("应报告本地路径不存在的警告：%v", warnings)
+	}
+	if err := config.ValidateConfig(path); err == nil || !strings.Contains(err.Error(), "[MediaSync[115].LocalPath] 路径不存在") {
+		t.Errorf("验证配置文件时本地路径不存在应报告错误，实际: %v", err)
+	}
+
+	if err := os.Mkdir(missing, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := config.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if warnings := config.Warnings(); len(warnings) != 0 {
+		t.Errorf("热重载后警告未更新：%v", warnings)
+	}
+}
@@ -0,0 +1,81 @@
+package font
+
+import (
+	"strings"
+)
+
+const uuLineLength = 80 // ASS 内嵌字体每行的字符数
+
+// 内嵌字体
+type EmbeddedFont struct {
+	Name string // 内嵌字体的文件名（如 楷体_0.ttf），客户端按字体内部名称匹配
+	Data []byte
+}
+
+// 按 ASS 规范进行 UU 编码
+//
+// 每 3 字节编码为 4 个字符（每 6 位加 33），剩余 1、2 字节分别编码为 2、3 个字符，每行 80 个字符
+func UUEncode(data []byte) string {
+	var (
+		builder strings.Builder
+		line    int
+	)
+	builder.Grow(len(data)*4/3 + len(data)/60 + 4)
+	write := func(chars ...byte) {
+		for _, char := range chars {
+			if line == uuLineLength {
+				builder.WriteByte('\n')
+				line = 0
+			}
+			builder.WriteByte(char)
+			line++
+		}
+	}
+	for i := 0; i < len(data); i += 3 {
+		var group [3]byte
+		n := copy(group[:], data[i:])
+		chars := [4]byte{
+			group[0]>>2 + 33,
+			(group[0]&0x03)<<4 | group[1]>>4 + 33,
+			(group[1]&0x0F)<<2 | group[2]>>6 + 33,
+			group[2]&0x3F + 33,
+		}
+		write(chars[:n+1]...)
+	}
+	return builder.String()
+}
+
+// 是否已包含内嵌字体
+func HasEmbeddedFonts(assText string) bool {
+	return strings.Contains(assText, "\n[Fonts]")
+}
+
+// 将字体内嵌到 ASS 字幕的 [Fonts] 部分
+//
+// [Fonts] 部分插入在 [Events] 之前，未找到 [Events] 时追加到末尾
+func EmbedFonts(assText string, fonts []EmbeddedFont) string {
+	if len(fonts) == 0 {
+		return assText
+	}
+	newLine := "\n"
+	if strings.Contains(assText, "\r\n") {
+		newLine = "\r\n"
+	}
+
+	var section strings.Builder
+	section.WriteString("[Fonts]" + newLine)
+	for _, font := range fonts {
+		section.WriteString("fontname: " + font.Name + newLine)
+		section.WriteString(strings.ReplaceAll(UUEncode(font.Data), "\n", newLine))
+		section.WriteString(newLine)
+	}
+	section.WriteString(newLine)
+
+	if index := strings.Index(assText, "[Events]"); index != -1 {
+		return assText[:index] + section.String() + assText[index:]
+	}
+	if !strings.HasSuffix(assText, newLine) {
+		assText += newLine
+	}
+	return assText + newLine + section.String()
+}
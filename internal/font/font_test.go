@@ -0,0 +1,170 @@
+package font_test
+
+import (
+	"MediaWarp/internal/font"
+	"MediaWarp/internal/logging"
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+func be16(values ...int) []byte {
+	data := make([]byte, 2*len(values))
+	for i, value := range values {
+		binary.BigEndian.PutUint16(data[2*i:], uint16(value))
+	}
+	return data
+}
+
+// 构造测试字体：A、B 为简单字形，C 为引用 A 的复合字形
+func testFont(family string) []byte {
+	simple := func(x int) []byte {
+		glyph := be16(1, 0, 0, x, 100, 2, 0) // 轮廓数、边界、endPtsOfContours、指令长度
+		glyph = append(glyph, 1, 1, 1)       // 3 个在曲线上的点
+		glyph = append(glyph, be16(0, x, -x)...)
+		return append(glyph, be16(0, 0, 100)...)
+	}
+	glyphs := [][]byte{nil, simple(50), simple(80), be16(-1, 0, 0, 50, 100, 0x0003, 1, 10, 0)}
+	var glyf []byte
+	loca := []int{0}
+	for _, glyph := range glyphs {
+		glyf = append(glyf, glyph...)
+		if len(glyf)%2 != 0 {
+			glyf = append(glyf, 0)
+		}
+		loca = append(loca, len(glyf)/2)
+	}
+
+	head := make([]byte, 54)
+	binary.BigEndian.PutUint32(head, 0x00010000)
+	binary.BigEndian.PutUint32(head[12:], 0x5F0F3CF5)
+	binary.BigEndian.PutUint16(head[18:], 1000)
+	hhea := make([]byte, 36)
+	binary.BigEndian.PutUint32(hhea, 0x00010000)
+	binary.BigEndian.PutUint16(hhea[34:], 2) // 后两个字形只有 lsb
+	maxp := be16(0, 0x5000, len(glyphs))
+	os2 := make([]byte, 78)
+	binary.BigEndian.PutUint16(os2[4:], 400)
+	cmap := append(be16(0, 1, 3, 1, 0, 12), be16(4, 32, 0, 4, 4, 1, 0, 'C', 0xFFFF, 0, 'A', 0xFFFF, 1-'A', 1, 0, 0)...)
+	nameUnits := utf16.Encode([]rune(family))
+	name := append(be16(0, 1, 18, 3, 1, 0x0804, 1, 2*len(nameUnits), 0), be16(func() []int {
+		values := make([]int, len(nameUnits))
+		for i, unit := range nameUnits {
+			values[i] = int(unit)
+		}
+		return values
+	}()...)...)
+	post := make([]byte, 32)
+	binary.BigEndian.PutUint32(post, 0x00030000)
+
+	tables := map[string][]byte{
+		"OS/2": os2, "cmap": cmap, "glyf": glyf, "head": head, "hhea": hhea,
+		"hmtx": be16(500, 0, 600, 0, 0, 0), "loca": be16(loca...), "maxp": maxp, "name": name, "post": post,
+	}
+	tags := make([]string, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	data := be16(1, 0, len(tags), 0, 0, 0)
+	offset := 12 + 16*len(tags)
+	var body []byte
+	for _, tag := range tags {
+		table := tables[tag]
+		data = append(data, tag...)
+		data = append(data, 0, 0, 0, 0)
+		data = binary.BigEndian.AppendUint32(data, uint32(offset+len(body)))
+		data = binary.BigEndian.AppendUint32(data, uint32(len(table)))
+		body = append(body, table...)
+		for len(body)%4 != 0 {
+			body = append(body, 0)
+		}
+	}
+	return append(data, body...)
+}
+
+// 获取字体表
+func table(data []byte, tag string) []byte {
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	for i := 0; i < numTables; i++ {
+		record := data[12+16*i:]
+		if string(record[:4]) == tag {
+			offset, length := binary.BigEndian.Uint32(record[8:]), binary.BigEndian.Uint32(record[12:])
+			return data[offset : offset+length]
+		}
+	}
+	return nil
+}
+
+func TestSubset(t *testing.T) {
+	logging.Init()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "test.ttf"), testFont("测试字体"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dir, "broken.ttf"), []byte("not a font"), 0644)
+
+	library := font.NewLibrary(dir)
+	if err := library.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := library.Match("不存在的字体", 400, false); ok {
+		t.Error("不应匹配不存在的字体")
+	}
+	face, ok := library.Match("测试字体", 700, false)
+	if !ok || !face.Glyf {
+		t.Fatalf("未找到测试字体：%+v", face)
+	}
+
+	subset, err := face.Subset([]rune{'C', 'Z'})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if numGlyphs := binary.BigEndian.Uint16(table(subset, "maxp")[4:]); numGlyphs != 3 { // .notdef、C 和 C 引用的 A
+		t.Errorf("子集字形数量错误：%d", numGlyphs)
+	}
+	glyf, loca := table(subset, "glyf"), table(subset, "loca")
+	composite := glyf[binary.BigEndian.Uint32(loca[4:]):binary.BigEndian.Uint32(loca[8:])]
+	if component := binary.BigEndian.Uint16(composite[12:]); component != 2 {
+		t.Errorf("复合字形引用的字形序号未更新：%d", component)
+	}
+	if !bytes.Equal(table(subset, "name"), table(testFont("测试字体"), "name")) {
+		t.Error("子集字体应保留字体名称")
+	}
+
+	subsetDir := t.TempDir() // 子集字体可以重新解析
+	os.WriteFile(filepath.Join(subsetDir, "subset.ttf"), subset, 0644)
+	library = font.NewLibrary(subsetDir)
+	if err := library.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := library.Match("测试字体", 400, false); !ok {
+		t.Error("无法解析子集字体")
+	}
+}
+
+func TestEmbedFonts(t *testing.T) {
+	if encoded := font.UUEncode([]byte("abc")); encoded != "97*D" {
+		t.Errorf("UU 编码错误：%s", encoded)
+	}
+	if encoded := font.UUEncode([]byte("ab")); encoded != "97)" {
+		t.Errorf("UU 编码错误：%s", encoded)
+	}
+	if lines := strings.Split(font.UUEncode(make([]byte, 90)), "\n"); len(lines) != 2 || len(lines[0]) != 80 || len(lines[1]) != 40 {
+		t.Errorf("UU 编码换行错误：%d", len(lines))
+	}
+
+	ass := "[Script Info]\n\n[V4+ Styles]\nFormat: Name, Fontname\nStyle: Default,测试字体\n\n[Events]\nFormat: Style, Text\nDialogue: Default,ABC\n"
+	embedded := font.EmbedFonts(ass, []font.EmbeddedFont{{Name: "测试字体_0.ttf", Data: []byte("abc")}})
+	if !strings.Contains(embedded, "[Fonts]\nfontname: 测试字体_0.ttf\n97*D\n\n[Events]") {
+		t.Errorf("内嵌字体位置错误：%s", embedded)
+	}
+	if !font.HasEmbeddedFonts(embedded) || font.HasEmbeddedFonts(ass) {
+		t.Error("内嵌字体判断错误")
+	}
+}
@@ -0,0 +1,130 @@
+package font
+
+import (
+	"MediaWarp/internal/logging"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// 支持的字体文件扩展名
+var fontExts = map[string]bool{".ttf": true, ".otf": true, ".ttc": true, ".otc": true}
+
+// 字体库
+//
+// 扫描字体目录中的字体文件，按名称查找字体
+type Library struct {
+	dir   string
+	mutex sync.RWMutex
+	faces map[string][]Face // 小写字体名称 -> 字体
+}
+
+func NewLibrary(dir string) *Library {
+	return &Library{dir: dir, faces: make(map[string][]Face)}
+}
+
+// 扫描字体目录
+//
+// 只读取字体文件的表目录和 name、OS/2 表，无法解析的字体文件跳过
+func (l *Library) Load() error {
+	faces := make(map[string][]Face)
+	count := 0
+	err := filepath.WalkDir(l.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !fontExts[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+		fileFaces, err := readFaces(path)
+		if err != nil {
+			logging.Debugf("跳过字体文件 %s：%v", path, err)
+			return nil
+		}
+		for _, face := range fileFaces {
+			for _, name := range face.Names {
+				key := strings.ToLower(name)
+				faces[key] = append(faces[key], face)
+			}
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	l.mutex.Lock()
+	l.faces = faces
+	l.mutex.Unlock()
+	logging.Infof("字体目录 %s 加载完成，共 %d 个字体", l.dir, count)
+	return nil
+}
+
+// 读取字体文件中的全部字体信息
+func readFaces(path string) ([]Face, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	offsets, err := faceOffsets(file)
+	if err != nil {
+		return nil, err
+	}
+	faces := make([]Face, 0, len(offsets))
+	for index, offset := range offsets {
+		font, err := parseSFNT(file, offset)
+		if err != nil {
+			return nil, err
+		}
+		face, err := readFace(font)
+		if err != nil {
+			return nil, err
+		}
+		face.Path = path
+		face.Index = index
+		faces = append(faces, face)
+	}
+	return faces, nil
+}
+
+// 按名称、字重和斜体查找最接近的字体
+//
+// 斜体不一致的字体优先级低于字重相差较大的字体
+func (l *Library) Match(name string, weight uint16, italic bool) (Face, bool) {
+	l.mutex.RLock()
+	candidates := l.faces[strings.ToLower(strings.TrimSpace(name))]
+	l.mutex.RUnlock()
+
+	var (
+		best      Face
+		bestScore = -1
+	)
+	for _, face := range candidates {
+		score := int(face.Weight) - int(weight)
+		if score < 0 {
+			score = -score
+		}
+		if face.Italic != italic {
+			score += 1000
+		}
+		if bestScore == -1 || score < bestScore {
+			best, bestScore = face, score
+		}
+	}
+	return best, bestScore != -1
+}
+
+// 对字体进行子集化
+func (face Face) Subset(runes []rune) ([]byte, error) {
+	file, err := os.Open(face.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Subset(file, face.Index, runes)
+}
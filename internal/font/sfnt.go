@@ -0,0 +1,192 @@
+package font
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+)
+
+var ErrUnsupportedFont = errors.New("不支持的字体格式")
+
+// 字体表记录
+type tableRecord struct {
+	offset uint32
+	length uint32
+}
+
+// SFNT 字体（TrueType、OpenType）
+type sfnt struct {
+	r      io.ReaderAt
+	tables map[string]tableRecord
+}
+
+// 获取字体文件中每个字体的偏移量
+//
+// TTC/OTC 字体集合包含多个字体，其余字体文件只包含一个字体
+func faceOffsets(r io.ReaderAt) ([]uint32, error) {
+	header := make([]byte, 12)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, fmt.Errorf("读取字体文件头失败：%w", err)
+	}
+	if string(header[:4]) != "ttcf" {
+		return []uint32{0}, nil
+	}
+	numFonts := binary.BigEndian.Uint32(header[8:])
+	if numFonts == 0 || numFonts > 1024 {
+		return nil, fmt.Errorf("字体集合中的字体数量无效：%d", numFonts)
+	}
+	offsets := make([]byte, 4*numFonts)
+	if _, err := r.ReadAt(offsets, 12); err != nil {
+		return nil, fmt.Errorf("读取字体集合偏移量失败：%w", err)
+	}
+	result := make([]uint32, numFonts)
+	for i := range result {
+		result[i] = binary.BigEndian.Uint32(offsets[4*i:])
+	}
+	return result, nil
+}
+
+// 解析字体的表目录
+func parseSFNT(r io.ReaderAt, offset uint32) (*sfnt, error) {
+	header := make([]byte, 12)
+	if _, err := r.ReadAt(header, int64(offset)); err != nil {
+		return nil, fmt.Errorf("读取字体表目录失败：%w", err)
+	}
+	switch version := binary.BigEndian.Uint32(header); version {
+	case 0x00010000, 0x4F54544F, 0x74727565: // TrueType、OTTO、true
+	default:
+		return nil, fmt.Errorf("%w：0x%08X", ErrUnsupportedFont, version)
+	}
+
+	numTables := int(binary.BigEndian.Uint16(header[4:]))
+	records := make([]byte, 16*numTables)
+	if _, err := r.ReadAt(records, int64(offset)+12); err != nil {
+		return nil, fmt.Errorf("读取字体表记录失败：%w", err)
+	}
+	font := &sfnt{r: r, tables: make(map[string]tableRecord, numTables)}
+	for i := 0; i < numTables; i++ {
+		record := records[16*i:]
+		font.tables[string(record[:4])] = tableRecord{
+			offset: binary.BigEndian.Uint32(record[8:]),
+			length: binary.BigEndian.Uint32(record[12:]),
+		}
+	}
+	return font, nil
+}
+
+// 读取字体表，表不存在时返回 nil
+func (f *sfnt) table(tag string) ([]byte, error) {
+	record, ok := f.tables[tag]
+	if !ok {
+		return nil, nil
+	}
+	data := make([]byte, record.length)
+	if _, err := f.r.ReadAt(data, int64(record.offset)); err != nil {
+		return nil, fmt.Errorf("读取字体表 %s 失败：%w", tag, err)
+	}
+	return data, nil
+}
+
+// 是否为 TrueType 轮廓（glyf）字体，CFF 轮廓字体不支持子集化
+func (f *sfnt) isTrueType() bool {
+	_, ok := f.tables["glyf"]
+	return ok
+}
+
+// 字体信息
+type Face struct {
+	Path   string   // 字体文件路径
+	Index  int      // 在字体集合中的序号
+	Names  []string // 字体族名、全名和 PostScript 名（包括各语言的名称）
+	Weight uint16   // 字重
+	Italic bool     // 是否为斜体
+	Glyf   bool     // 是否为 TrueType 轮廓字体（支持子集化）
+}
+
+// 读取字体信息
+func readFace(f *sfnt) (Face, error) {
+	face := Face{Weight: 400, Glyf: f.isTrueType()}
+
+	name, err := f.table("name")
+	if err != nil {
+		return face, err
+	}
+	face.Names = parseNames(name)
+	if len(face.Names) == 0 {
+		return face, fmt.Errorf("%w：缺少字体名称", ErrUnsupportedFont)
+	}
+
+	os2, err := f.table("OS/2")
+	if err != nil {
+		return face, err
+	}
+	if len(os2) >= 64 {
+		face.Weight = binary.BigEndian.Uint16(os2[4:])
+		face.Italic = binary.BigEndian.Uint16(os2[62:])&1 != 0
+	} else if head, err := f.table("head"); err == nil && len(head) >= 46 {
+		macStyle := binary.BigEndian.Uint16(head[44:])
+		if macStyle&1 != 0 {
+			face.Weight = 700
+		}
+		face.Italic = macStyle&2 != 0
+	}
+	return face, nil
+}
+
+// 解析 name 表中的字体名称
+//
+// 包括字体族名（1）、全名（4）、PostScript 名（6）和排版字体族名（16）
+func parseNames(data []byte) []string {
+	if len(data) < 6 {
+		return nil
+	}
+	count := int(binary.BigEndian.Uint16(data[2:]))
+	storage := int(binary.BigEndian.Uint16(data[4:]))
+
+	seen := make(map[string]bool)
+	var names []string
+	for i := 0; i < count; i++ {
+		record := 6 + 12*i
+		if record+12 > len(data) {
+			break
+		}
+		platformID := binary.BigEndian.Uint16(data[record:])
+		encodingID := binary.BigEndian.Uint16(data[record+2:])
+		nameID := binary.BigEndian.Uint16(data[record+6:])
+		length := int(binary.BigEndian.Uint16(data[record+8:]))
+		offset := storage + int(binary.BigEndian.Uint16(data[record+10:]))
+		if nameID != 1 && nameID != 4 && nameID != 6 && nameID != 16 {
+			continue
+		}
+		if offset+length > len(data) {
+			continue
+		}
+
+		var name string
+		raw := data[offset : offset+length]
+		switch platformID {
+		case 0, 3: // Unicode、Windows 使用 UTF-16BE
+			units := make([]uint16, len(raw)/2)
+			for j := range units {
+				units[j] = binary.BigEndian.Uint16(raw[2*j:])
+			}
+			name = string(utf16.Decode(units))
+		case 1: // Macintosh，只支持 Roman 编码
+			if encodingID != 0 {
+				continue
+			}
+			name = string(raw)
+		default:
+			continue
+		}
+		name = strings.TrimSpace(name)
+		if key := strings.ToLower(name); name != "" && !seen[key] {
+			seen[key] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,491 @@
+package font
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// 子集字体中保留的表（其余表如 GSUB、GPOS、kern 与字形序号相关，直接丢弃）
+var keptTables = []string{"OS/2", "cvt ", "fpgm", "gasp", "name", "prep"}
+
+// 复合字形标志位
+const (
+	argsAreWords   = 0x0001
+	weHaveAScale   = 0x0008
+	moreComponents = 0x0020
+	weHaveXYScale  = 0x0040
+	weHaveTwoByTwo = 0x0080
+)
+
+// 字体子集化
+//
+// 只保留 runes 使用到的字形（以及复合字形引用的字形），返回新的 TrueType 字体
+// 仅支持 TrueType 轮廓（glyf）字体
+func Subset(r io.ReaderAt, index int, runes []rune) ([]byte, error) {
+	offsets, err := faceOffsets(r)
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= len(offsets) {
+		return nil, fmt.Errorf("字体序号 %d 超出范围：%d", index, len(offsets))
+	}
+	font, err := parseSFNT(r, offsets[index])
+	if err != nil {
+		return nil, err
+	}
+	if !font.isTrueType() {
+		return nil, fmt.Errorf("%w：CFF 轮廓字体不支持子集化", ErrUnsupportedFont)
+	}
+
+	tables := make(map[string][]byte)
+	for _, tag := range []string{"head", "hhea", "hmtx", "maxp", "loca", "glyf", "cmap", "post"} {
+		data, err := font.table(tag)
+		if err != nil {
+			return nil, err
+		}
+		if data == nil && tag != "post" {
+			return nil, fmt.Errorf("%w：缺少 %s 表", ErrUnsupportedFont, tag)
+		}
+		tables[tag] = data
+	}
+	head, hhea, maxp := tables["head"], tables["hhea"], tables["maxp"]
+	if len(head) < 54 || len(hhea) < 36 || len(maxp) < 6 {
+		return nil, fmt.Errorf("%w：字体表长度无效", ErrUnsupportedFont)
+	}
+	numGlyphs := int(binary.BigEndian.Uint16(maxp[4:]))
+	loca, err := parseLoca(tables["loca"], numGlyphs, int16(binary.BigEndian.Uint16(head[50:])))
+	if err != nil {
+		return nil, err
+	}
+	glyf := tables["glyf"]
+	glyph := func(gid int) []byte {
+		start, end := loca[gid], loca[gid+1]
+		if start >= end || int(end) > len(glyf) {
+			return nil
+		}
+		return glyf[start:end]
+	}
+
+	lookup, err := parseCmap(tables["cmap"])
+	if err != nil {
+		return nil, err
+	}
+
+	// 计算需要保留的字形，新字形序号按字符顺序分配，使连续字符对应连续字形
+	sortedRunes := append([]rune(nil), runes...)
+	sort.Slice(sortedRunes, func(i, j int) bool { return sortedRunes[i] < sortedRunes[j] })
+	newGID := map[int]int{0: 0} // 原字形序号 -> 新字形序号，.notdef 必须为 0
+	order := []int{0}
+	add := func(gid int) {
+		if _, ok := newGID[gid]; !ok && gid < numGlyphs {
+			newGID[gid] = len(order)
+			order = append(order, gid)
+		}
+	}
+	mapping := make(map[rune]int, len(sortedRunes))
+	for _, char := range sortedRunes {
+		if gid := lookup(char); gid > 0 && gid < numGlyphs {
+			add(gid)
+			mapping[char] = gid
+		}
+	}
+	for i := 0; i < len(order); i++ { // 加入复合字形引用的字形（order 在循环中增长）
+		for _, component := range compositeComponents(glyph(order[i])) {
+			add(component)
+		}
+	}
+
+	// glyf、loca
+	var newGlyf bytes.Buffer
+	newLoca := make([]byte, 4*(len(order)+1))
+	for i, gid := range order {
+		binary.BigEndian.PutUint32(newLoca[4*i:], uint32(newGlyf.Len()))
+		data := append([]byte(nil), glyph(gid)...)
+		remapComposite(data, newGID)
+		newGlyf.Write(data)
+		for newGlyf.Len()%4 != 0 {
+			newGlyf.WriteByte(0)
+		}
+	}
+	binary.BigEndian.PutUint32(newLoca[4*len(order):], uint32(newGlyf.Len()))
+
+	// hmtx、hhea（全部使用完整的水平度量）
+	hmtx := tables["hmtx"]
+	numberOfHMetrics := int(binary.BigEndian.Uint16(hhea[34:]))
+	if numberOfHMetrics == 0 || len(hmtx) < 4*numberOfHMetrics {
+		return nil, fmt.Errorf("%w：hmtx 表长度无效", ErrUnsupportedFont)
+	}
+	newHmtx := make([]byte, 4*len(order))
+	for i, gid := range order {
+		metric := gid
+		if metric >= numberOfHMetrics {
+			metric = numberOfHMetrics - 1
+		}
+		copy(newHmtx[4*i:], hmtx[4*metric:4*metric+2]) // advanceWidth
+		if gid < numberOfHMetrics {
+			copy(newHmtx[4*i+2:], hmtx[4*gid+2:4*gid+4])
+		} else if lsb := 4*numberOfHMetrics + 2*(gid-numberOfHMetrics); lsb+2 <= len(hmtx) {
+			copy(newHmtx[4*i+2:], hmtx[lsb:lsb+2])
+		}
+	}
+	newHhea := append([]byte(nil), hhea...)
+	binary.BigEndian.PutUint16(newHhea[34:], uint16(len(order)))
+
+	newMaxp := append([]byte(nil), maxp...)
+	binary.BigEndian.PutUint16(newMaxp[4:], uint16(len(order)))
+
+	newHead := append([]byte(nil), head...)
+	binary.BigEndian.PutUint32(newHead[8:], 0)  // checkSumAdjustment 在写入字体时计算
+	binary.BigEndian.PutUint16(newHead[50:], 1) // 使用长格式 loca
+
+	// post 使用 3.0 版本，不保存字形名称
+	newPost := make([]byte, 32)
+	binary.BigEndian.PutUint32(newPost, 0x00030000)
+	if post := tables["post"]; len(post) >= 32 {
+		copy(newPost[4:], post[4:32])
+	}
+
+	cmap := make(map[rune]int, len(mapping))
+	for char, gid := range mapping {
+		cmap[char] = newGID[gid]
+	}
+
+	output := map[string][]byte{
+		"cmap": buildCmap(cmap),
+		"glyf": newGlyf.Bytes(),
+		"head": newHead,
+		"hhea": newHhea,
+		"hmtx": newHmtx,
+		"loca": newLoca,
+		"maxp": newMaxp,
+		"post": newPost,
+	}
+	for _, tag := range keptTables {
+		data, err := font.table(tag)
+		if err != nil {
+			return nil, err
+		}
+		if data != nil {
+			output[tag] = data
+		}
+	}
+	return writeSFNT(output), nil
+}
+
+// 解析 loca 表
+func parseLoca(data []byte, numGlyphs int, format int16) ([]uint32, error) {
+	loca := make([]uint32, numGlyphs+1)
+	switch format {
+	case 0:
+		if len(data) < 2*(numGlyphs+1) {
+			return nil, fmt.Errorf("%w：loca 表长度无效", ErrUnsupportedFont)
+		}
+		for i := range loca {
+			loca[i] = uint32(binary.BigEndian.Uint16(data[2*i:])) * 2
+		}
+	case 1:
+		if len(data) < 4*(numGlyphs+1) {
+			return nil, fmt.Errorf("%w：loca 表长度无效", ErrUnsupportedFont)
+		}
+		for i := range loca {
+			loca[i] = binary.BigEndian.Uint32(data[4*i:])
+		}
+	default:
+		return nil, fmt.Errorf("%w：loca 格式 %d", ErrUnsupportedFont, format)
+	}
+	return loca, nil
+}
+
+// 遍历复合字形的组件，返回组件字形序号在字形数据中的偏移量
+func compositeOffsets(glyph []byte) []int {
+	if len(glyph) < 10 || int16(binary.BigEndian.Uint16(glyph)) >= 0 { // 简单字形
+		return nil
+	}
+	var offsets []int
+	for offset := 10; offset+4 <= len(glyph) && len(offsets) < 1024; {
+		flags := binary.BigEndian.Uint16(glyph[offset:])
+		offsets = append(offsets, offset+2)
+		offset += 4
+		if flags&argsAreWords != 0 {
+			offset += 4
+		} else {
+			offset += 2
+		}
+		switch {
+		case flags&weHaveAScale != 0:
+			offset += 2
+		case flags&weHaveXYScale != 0:
+			offset += 4
+		case flags&weHaveTwoByTwo != 0:
+			offset += 8
+		}
+		if flags&moreComponents == 0 {
+			break
+		}
+	}
+	return offsets
+}
+
+// 获取复合字形引用的字形序号
+func compositeComponents(glyph []byte) []int {
+	offsets := compositeOffsets(glyph)
+	components := make([]int, len(offsets))
+	for i, offset := range offsets {
+		components[i] = int(binary.BigEndian.Uint16(glyph[offset:]))
+	}
+	return components
+}
+
+// 将复合字形引用的字形序号替换为新的字形序号
+func remapComposite(glyph []byte, newGID map[int]int) {
+	for _, offset := range compositeOffsets(glyph) {
+		binary.BigEndian.PutUint16(glyph[offset:], uint16(newGID[int(binary.BigEndian.Uint16(glyph[offset:]))]))
+	}
+}
+
+// 解析 cmap 表，返回字符到字形序号的查找函数
+//
+// 优先使用完整 Unicode 的格式 12 子表，其次使用 BMP 的格式 4 子表
+func parseCmap(data []byte) (func(rune) int, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("%w：cmap 表长度无效", ErrUnsupportedFont)
+	}
+	var format4, format12 []byte
+	numTables := int(binary.BigEndian.Uint16(data[2:]))
+	for i := 0; i < numTables; i++ {
+		record := 4 + 8*i
+		if record+8 > len(data) {
+			break
+		}
+		platformID := binary.BigEndian.Uint16(data[record:])
+		encodingID := binary.BigEndian.Uint16(data[record+2:])
+		offset := int(binary.BigEndian.Uint32(data[record+4:]))
+		if offset+4 > len(data) || !(platformID == 0 || platformID == 3 && (encodingID == 1 || encodingID == 10)) {
+			continue
+		}
+		subtable := data[offset:]
+		switch binary.BigEndian.Uint16(subtable) {
+		case 4:
+			if format4 == nil {
+				format4 = subtable
+			}
+		case 12:
+			if format12 == nil {
+				format12 = subtable
+			}
+		}
+	}
+
+	switch {
+	case len(format12) >= 16:
+		numGroups := int(binary.BigEndian.Uint32(format12[12:]))
+		if 16+12*numGroups > len(format12) {
+			return nil, fmt.Errorf("%w：cmap 格式 12 子表长度无效", ErrUnsupportedFont)
+		}
+		return func(char rune) int {
+			low, high := 0, numGroups-1
+			for low <= high {
+				middle := (low + high) / 2
+				group := format12[16+12*middle:]
+				start, end := rune(binary.BigEndian.Uint32(group)), rune(binary.BigEndian.Uint32(group[4:]))
+				if char < start {
+					high = middle - 1
+				} else if char > end {
+					low = middle + 1
+				} else {
+					return int(binary.BigEndian.Uint32(group[8:])) + int(char-start)
+				}
+			}
+			return 0
+		}, nil
+	case len(format4) >= 14:
+		segCount := int(binary.BigEndian.Uint16(format4[6:])) / 2
+		if 16+8*segCount > len(format4) {
+			return nil, fmt.Errorf("%w：cmap 格式 4 子表长度无效", ErrUnsupportedFont)
+		}
+		endCodes := 14
+		startCodes := endCodes + 2*segCount + 2
+		idDeltas := startCodes + 2*segCount
+		idRangeOffsets := idDeltas + 2*segCount
+		return func(char rune) int {
+			if char > 0xFFFF {
+				return 0
+			}
+			for i := 0; i < segCount; i++ {
+				if rune(binary.BigEndian.Uint16(format4[endCodes+2*i:])) < char {
+					continue
+				}
+				start := rune(binary.BigEndian.Uint16(format4[startCodes+2*i:]))
+				if char < start {
+					return 0
+				}
+				delta := binary.BigEndian.Uint16(format4[idDeltas+2*i:])
+				rangeOffset := int(binary.BigEndian.Uint16(format4[idRangeOffsets+2*i:]))
+				if rangeOffset == 0 {
+					return int(uint16(char) + delta)
+				}
+				index := idRangeOffsets + 2*i + rangeOffset + 2*int(char-start)
+				if index+2 > len(format4) {
+					return 0
+				}
+				gid := binary.BigEndian.Uint16(format4[index:])
+				if gid == 0 {
+					return 0
+				}
+				return int(gid + delta)
+			}
+			return 0
+		}, nil
+	}
+	return nil, fmt.Errorf("%w：未找到 Unicode cmap 子表", ErrUnsupportedFont)
+}
+
+// cmap 分段（连续字符对应连续字形）
+type cmapSegment struct {
+	start, end rune
+	gid        int
+}
+
+// 生成 cmap 表，包含 BMP 的格式 4 子表和完整 Unicode 的格式 12 子表
+func buildCmap(mapping map[rune]int) []byte {
+	chars := make([]rune, 0, len(mapping))
+	for char := range mapping {
+		chars = append(chars, char)
+	}
+	sort.Slice(chars, func(i, j int) bool { return chars[i] < chars[j] })
+	var segments []cmapSegment
+	for _, char := range chars {
+		gid := mapping[char]
+		if n := len(segments); n > 0 && segments[n-1].end+1 == char && segments[n-1].gid+int(char-segments[n-1].start) == gid && (char <= 0xFFFF) == (segments[n-1].start <= 0xFFFF) {
+			segments[n-1].end = char
+			continue
+		}
+		segments = append(segments, cmapSegment{start: char, end: char, gid: gid})
+	}
+
+	// 格式 4
+	var bmp []cmapSegment
+	for _, segment := range segments {
+		if segment.end < 0xFFFF {
+			bmp = append(bmp, segment)
+		}
+	}
+	bmp = append(bmp, cmapSegment{start: 0xFFFF, end: 0xFFFF, gid: 0})
+	segCount := len(bmp)
+	searchRange, entrySelector := 2, 0
+	for searchRange*2 <= 2*segCount {
+		searchRange *= 2
+		entrySelector++
+	}
+	format4 := make([]byte, 16+8*segCount)
+	binary.BigEndian.PutUint16(format4, 4)
+	binary.BigEndian.PutUint16(format4[2:], uint16(len(format4)))
+	binary.BigEndian.PutUint16(format4[6:], uint16(2*segCount))
+	binary.BigEndian.PutUint16(format4[8:], uint16(searchRange))
+	binary.BigEndian.PutUint16(format4[10:], uint16(entrySelector))
+	binary.BigEndian.PutUint16(format4[12:], uint16(2*segCount-searchRange))
+	for i, segment := range bmp {
+		delta := uint16(segment.gid - int(segment.start))
+		if segment.start == 0xFFFF {
+			delta = 1
+		}
+		binary.BigEndian.PutUint16(format4[14+2*i:], uint16(segment.end))
+		binary.BigEndian.PutUint16(format4[16+2*segCount+2*i:], uint16(segment.start))
+		binary.BigEndian.PutUint16(format4[16+4*segCount+2*i:], delta)
+	}
+
+	// 格式 12
+	format12 := make([]byte, 16+12*len(segments))
+	binary.BigEndian.PutUint16(format12, 12)
+	binary.BigEndian.PutUint32(format12[4:], uint32(len(format12)))
+	binary.BigEndian.PutUint32(format12[12:], uint32(len(segments)))
+	for i, segment := range segments {
+		group := format12[16+12*i:]
+		binary.BigEndian.PutUint32(group, uint32(segment.start))
+		binary.BigEndian.PutUint32(group[4:], uint32(segment.end))
+		binary.BigEndian.PutUint32(group[8:], uint32(segment.gid))
+	}
+
+	// 编码记录：Unicode BMP（0,3）、Windows BMP（3,1）、Windows 完整 Unicode（3,10）
+	const headerSize = 4 + 8*3
+	cmap := make([]byte, headerSize, headerSize+len(format4)+len(format12))
+	binary.BigEndian.PutUint16(cmap[2:], 3)
+	records := []struct {
+		platformID, encodingID uint16
+		offset                 int
+	}{
+		{0, 3, headerSize},
+		{3, 1, headerSize},
+		{3, 10, headerSize + len(format4)},
+	}
+	for i, record := range records {
+		binary.BigEndian.PutUint16(cmap[4+8*i:], record.platformID)
+		binary.BigEndian.PutUint16(cmap[6+8*i:], record.encodingID)
+		binary.BigEndian.PutUint32(cmap[8+8*i:], uint32(record.offset))
+	}
+	cmap = append(cmap, format4...)
+	return append(cmap, format12...)
+}
+
+// 计算字体表校验和
+func checksum(data []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(data); i += 4 {
+		var word [4]byte
+		copy(word[:], data[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+	return sum
+}
+
+// 写入 TrueType 字体
+func writeSFNT(tables map[string][]byte) []byte {
+	tags := make([]string, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	numTables := len(tags)
+	searchRange, entrySelector := 1, 0
+	for searchRange*2 <= numTables {
+		searchRange *= 2
+		entrySelector++
+	}
+	searchRange *= 16
+
+	header := make([]byte, 12+16*numTables)
+	binary.BigEndian.PutUint32(header, 0x00010000)
+	binary.BigEndian.PutUint16(header[4:], uint16(numTables))
+	binary.BigEndian.PutUint16(header[6:], uint16(searchRange))
+	binary.BigEndian.PutUint16(header[8:], uint16(entrySelector))
+	binary.BigEndian.PutUint16(header[10:], uint16(16*numTables-searchRange))
+
+	var body bytes.Buffer
+	headOffset := -1
+	for i, tag := range tags {
+		data := tables[tag]
+		offset := len(header) + body.Len()
+		if tag == "head" {
+			headOffset = offset
+		}
+		record := header[12+16*i:]
+		copy(record, tag)
+		binary.BigEndian.PutUint32(record[4:], checksum(data))
+		binary.BigEndian.PutUint32(record[8:], uint32(offset))
+		binary.BigEndian.PutUint32(record[12:], uint32(len(data)))
+		body.Write(data)
+		for body.Len()%4 != 0 {
+			body.WriteByte(0)
+		}
+	}
+
+	font := append(header, body.Bytes()...)
+	if headOffset >= 0 {
+		binary.BigEndian.PutUint32(font[headOffset+8:], 0xB1B0AFBA-checksum(font))
+	}
+	return font
+}
@@ -0,0 +1,82 @@
+package handler_test
+
+import (
+	"MediaWarp/constants"
+	"MediaWarp/internal/config"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestEmbyAlistStrmRedirect(t *testing.T) {
+	var fsGetCount atomic.Int32
+	alistStub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/d/") { // 第一次获取的签名已过期
+			if r.URL.Query().Get("sign") == "abc" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Write([]byte("movie"))
+			return
+		}
+		if r.URL.Path != "/api/fs/get" || r.Header.Get("Authorization") != "alist-token" {
+			w.Write([]byte(`{"code":401,"message":"token is invalidated"}`))
+			return
+		}
+		sign := "abc"
+		if fsGetCount.Add(1) > 1 {
+			sign = "def"
+		}
+		w.Write([]byte(`{"code":200,"message":"success","data":{"name":"a.mkv","sign":"` + sign + `","raw_url":"https://cdn.example.com/a.mkv"}}`))
+	}))
+	defer alistStub.Close()
+
+	mediaWarp, client := startMediaWarp(t, constants.EMBY, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/Items" {
+			w.Write([]byte(`{"Items":[{"Id":"9001","Path":"/media/alist/Movie/a.strm","MediaSources":[{"Id":"9001","Protocol":"File","Path":"/115/Movie/a.mkv"}]}],"TotalRecordCount":1}`))
+			return
+		}
+		w.Write([]byte("upstream"))
+	}, func(s *config.Snapshot) {
+		s.AlistStrm = config.AlistStrmSetting{
+			Enable: true,
+			List:   []config.AlistSetting{{ADDR: alistStub.URL, Token: "alist-token", PrefixList: []string{"/media/alist/"}}},
+		}
+		s.ProxyStream = config.ProxyStreamSetting{Enable: true, ClientList: []string{"OldTV"}}
+	})
+
+	for range 2 {
+		resp, err := client.Get(mediaWarp.URL + "/videos/9001/stream?mediasourceid=9001")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusFound {
+			t.Fatalf("状态码错误。期望: %d, 实际: %d", http.StatusFound, resp.StatusCode)
+		}
+		if location := resp.Header.Get("Location"); location != alistStub.URL+"/d/115/Movie/a.mkv?sign=abc" {
+			t.Errorf("重定向地址错误：%s", location)
+		}
+	}
+	if count := fsGetCount.Load(); count != 1 {
+		t.Errorf("AlistLink 缓存未生效，/api/fs/get 请求次数：%d", count)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, mediaWarp.URL+"/videos/9001/stream?mediasourceid=9001", nil)
+	req.Header.Set("User-Agent", "OldTV/1.0")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != "movie" {
+		t.Errorf("代理推流时链接过期后未刷新，状态码：%d，内容：%s", resp.StatusCode, body)
+	}
+	if count := fsGetCount.Load(); count != 2 {
+		t.Errorf("链接过期后应重新请求 /api/fs/get，请求次数：%d", count)
+	}
+}
@@ -0,0 +1,268 @@
+package handler
+
+import (
+	"MediaWarp/constants"
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/logging"
+	"MediaWarp/internal/service/emby"
+	"MediaWarp/internal/subtitle"
+	"MediaWarp/utils"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	bilingualIndexBase = 100000             // 双语虚拟字幕流序号 = 基数 + 中文字幕流序号 × 1000 + 英文字幕流序号
+	bilingualTitle     = "中英双语 (MediaWarp)" // 双语虚拟字幕流的标题
+)
+
+var embyAuthTokenRegexp = regexp.MustCompile(`(?i)Token="?([^",]+)"?`)
+
+// 识别字幕流语言
+//
+// 根据语言代码和标题判断，返回 zh、en 或空字符串；已是双语的字幕流返回空字符串
+func subtitleLanguage(stream emby.MediaStream) string {
+	var language, title string
+	if stream.Language != nil {
+		language = strings.ToLower(*stream.Language)
+	}
+	for _, value := range []*string{stream.Title, stream.DisplayTitle} {
+		if value != nil {
+			title += " " + strings.ToLower(*value)
+		}
+	}
+	for _, keyword := range []string{"双语", "雙語", "中英", "bilingual"} {
+		if strings.Contains(title, keyword) {
+			return ""
+		}
+	}
+
+	switch {
+	case language == "chi" || language == "zho" || language == "chs" || language == "cht" || language == "zh" || strings.HasPrefix(language, "zh-"):
+		return "zh"
+	case language == "eng" || language == "en" || strings.HasPrefix(language, "en-"):
+		return "en"
+	}
+	for _, keyword := range []string{"中文", "简体", "繁体", "簡體", "繁體", "chinese"} {
+		if strings.Contains(title, keyword) {
+			return "zh"
+		}
+	}
+	for _, keyword := range []string{"英文", "english"} {
+		if strings.Contains(title, keyword) {
+			return "en"
+		}
+	}
+	return ""
+}
+
+// 获取请求中的 Emby 认证信息，以 xxx=xxx 的字符串形式返回
+func embyTokenPair(req *http.Request) string {
+	if pair, err := utils.ResolveEmbyAPIKVPairs(req.URL.String()); err == nil && pair != "" {
+		return pair
+	}
+	if token := req.Header.Get("X-Emby-Token"); token != "" {
+		return "api_key=" + token
+	}
+	for _, header := range []string{"X-Emby-Authorization", "Authorization"} { // Jellyfin 使用 Authorization 请求头
+		if matches := embyAuthTokenRegexp.FindStringSubmatch(req.Header.Get(header)); len(matches) == 2 {
+			return "api_key=" + matches[1]
+		}
+	}
+	return ""
+}
+
+// 获取请求中的 Emby 认证令牌
+func embyToken(req *http.Request) string {
+	_, token, _ := strings.Cut(embyTokenPair(req), "=")
+	return token
+}
+
+// 为媒体源添加双语虚拟字幕流
+//
+// 媒体源同时有中文和英文文本字幕时，添加合并两者的 ASS 字幕流，各取第一个
+func addBilingualStream(source *emby.MediaSourceInfo, req *http.Request) {
+	if source.ItemID == nil || source.ID == nil {
+		return
+	}
+	var (
+		chinese, english int64 = -1, -1
+		maxIndex         int64 = -1
+	)
+	for _, stream := range source.MediaStreams {
+		if stream.Index == nil {
+			continue
+		}
+		maxIndex = max(maxIndex, *stream.Index)
+		if stream.Type == nil || *stream.Type != emby.Subtitle || stream.IsTextSubtitleStream == nil || !*stream.IsTextSubtitleStream {
+			continue
+		}
+		switch subtitleLanguage(stream) {
+		case "zh":
+			if chinese < 0 {
+				chinese = *stream.Index
+			}
+		case "en":
+			if english < 0 {
+				english = *stream.Index
+			}
+		}
+	}
+	if chinese < 0 || english < 0 || chinese >= 1000 || english >= 1000 || maxIndex >= bilingualIndexBase {
+		return
+	}
+
+	var (
+		index          = bilingualIndexBase + chinese*1000 + english
+		codec          = "ass"
+		language       = "chi"
+		title          = bilingualTitle
+		deliveryMethod = emby.External
+		deliveryURL    = fmt.Sprintf("/Videos/%s/%s/Subtitles/%d/0/Stream.ass", *source.ItemID, *source.ID, index)
+		streamType     = emby.Subtitle
+		enabled        = true
+		disabled       = false
+	)
+	if pair := embyTokenPair(req); pair != "" {
+		deliveryURL += "?" + pair
+	}
+	source.MediaStreams = append(source.MediaStreams, emby.MediaStream{
+		Codec:                  &codec,
+		DeliveryMethod:         &deliveryMethod,
+		DeliveryURL:            &deliveryURL,
+		DisplayTitle:           &title,
+		Index:                  &index,
+		IsDefault:              &disabled,
+		IsExternal:             &enabled,
+		IsExternalURL:          &disabled,
+		IsTextSubtitleStream:   &enabled,
+		Language:               &language,
+		SupportsExternalStream: &enabled,
+		Title:                  &title,
+		Type:                   &streamType,
+	})
+	logging.Debugf("媒体源 %s 已添加双语字幕流 %d（中文 %d，英文 %d）", *source.ID, index, chinese, english)
+}
+
+// 双语虚拟字幕流处理器
+//
+// /Videos/:itemId/:mediaSourceId/Subtitles/:index/Stream.ass
+// 检查中文、英文字幕流是否存在，使用客户端的认证令牌从上游获取字幕，合并为双语 ASS 字幕后按 processSubtitles 处理
+func (embyServerHandler *EmbyServerHandler) BilingualSubtitlesHandler(ctx *gin.Context) {
+	logging.Debug("======= BilingualSubtitlesHandler ======= ")
+	matches := constants.EmbyRegexp.Router.BilingualSubtitles.FindStringSubmatch(ctx.Request.URL.Path)
+	index, err := strconv.ParseInt(matches[4], 10, 64)
+	if err != nil || index < bilingualIndexBase {
+		embyServerHandler.ReverseProxy(ctx.Writer, ctx.Request)
+		return
+	}
+	var (
+		itemID        = matches[2]
+		mediaSourceID = matches[3]
+		chinese       = (index - bilingualIndexBase) / 1000
+		english       = (index - bilingualIndexBase) % 1000
+		tracks        [2][]byte
+	)
+	if status, err := embyServerHandler.checkBilingualStreams(itemID, mediaSourceID, chinese, english); err != nil {
+		logging.Warningf("媒体项 %s 无法生成双语字幕：%v", itemID, err)
+		ctx.String(status, err.Error())
+		return
+	}
+	token := embyToken(ctx.Request)
+	for i, trackIndex := range []int64{chinese, english} {
+		content, status, err := embyServerHandler.fetchSubtitle(ctx.Request.Context(), itemID, mediaSourceID, trackIndex, token)
+		if err != nil {
+			logging.Warningf("获取字幕流 %s/%d 失败：%v", itemID, trackIndex, err)
+			ctx.String(status, err.Error())
+			return
+		}
+		if config.Subtitle().ToUTF8 {
+			content, _ = subtitleToUTF8(content)
+		}
+		tracks[i] = content
+	}
+
+	merged, err := subtitle.Bilingual(tracks[0], tracks[1], config.Subtitle().ASSStyle)
+	if err != nil {
+		logging.Warning("合并双语字幕失败：", err)
+		ctx.String(http.StatusBadGateway, err.Error())
+		return
+	}
+	logging.Infof("已合并媒体项 %s 的中文字幕 %d 和英文字幕 %d", itemID, chinese, english)
+
+	merged = processSubtitles(merged, ctx.Request.URL.Path, embyServerHandler.chineseConvertMode(ctx.Request))
+	ctx.Data(http.StatusOK, subtitleContentType(path.Ext(ctx.Request.URL.Path)), merged)
+}
+
+// 检查媒体源中是否存在双语字幕使用的中文和英文字幕流
+//
+// 返回错误和应返回给客户端的状态码
+func (embyServerHandler *EmbyServerHandler) checkBilingualStreams(itemID string, mediaSourceID string, chinese int64, english int64) (int, error) {
+	item, err := embyServerHandler.queryItem(itemID)
+	if err != nil {
+		return http.StatusBadGateway, err
+	}
+	for _, baseItem := range item.Items {
+		for _, source := range baseItem.MediaSources {
+			if source.ID == nil || !strings.EqualFold(*source.ID, mediaSourceID) {
+				continue
+			}
+			languages := make(map[int64]string, len(source.MediaStreams))
+			for _, stream := range source.MediaStreams {
+				if stream.Index != nil && stream.Type != nil && *stream.Type == emby.Subtitle {
+					languages[*stream.Index] = subtitleLanguage(stream)
+				}
+			}
+			if languages[chinese] != "zh" {
+				return http.StatusNotFound, fmt.Errorf("媒体源 %s 缺少中文字幕流 %d", mediaSourceID, chinese)
+			}
+			if languages[english] != "en" {
+				return http.StatusNotFound, fmt.Errorf("媒体源 %s 缺少英文字幕流 %d", mediaSourceID, english)
+			}
+			return http.StatusOK, nil
+		}
+	}
+	return http.StatusNotFound, fmt.Errorf("未找到媒体源 %s", mediaSourceID)
+}
+
+// 使用客户端的认证令牌从上游获取 SRT 格式的字幕流
+//
+// 返回字幕内容和出错时应返回给客户端的状态码
+func (embyServerHandler *EmbyServerHandler) fetchSubtitle(ctx context.Context, itemID string, mediaSourceID string, index int64, token string) ([]byte, int, error) {
+	resp, err := embyServerHandler.server.VideosServiceGetSubtitle(ctx, itemID, mediaSourceID, index, token)
+	if err != nil {
+		return nil, http.StatusBadGateway, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, fmt.Errorf("上游返回状态码：%d", resp.StatusCode)
+	}
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, http.StatusBadGateway, err
+	}
+	return content, http.StatusOK, nil
+}
+
+// 字幕格式对应的 Content-Type
+func subtitleContentType(ext string) string {
+	switch subtitle.FormatFromExt(ext) {
+	case subtitle.FormatSRT:
+		return "application/x-subrip; charset=utf-8"
+	case subtitle.FormatVTT:
+		return "text/vtt; charset=utf-8"
+	case subtitle.FormatASS, subtitle.FormatSSA:
+		return "text/x-ssa; charset=utf-8"
+	case subtitle.FormatTTML:
+		return "application/ttml+xml; charset=utf-8"
+	}
+	return "text/plain; charset=utf-8"
+}
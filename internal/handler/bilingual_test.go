@@ -0,0 +1,89 @@
+package handler_test
+
+import (
+	"MediaWarp/constants"
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/service/emby"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestBilingualSubtitles(t *testing.T) {
+	source := `{"Id":"mediasource_400","ItemId":"400","Path":"/media/400.mkv","SupportsDirectPlay":true,"SupportsDirectStream":true,"MediaStreams":[` +
+		`{"Index":0,"Type":"Video","Codec":"h264"},` +
+		`{"Index":2,"Type":"Subtitle","Codec":"subrip","Language":"eng","IsTextSubtitleStream":true},` +
+		`{"Index":3,"Type":"Subtitle","Codec":"ass","Language":"chi","Title":"简体","IsTextSubtitleStream":true}]}`
+	mediaWarp, client := startMediaWarp(t, constants.EMBY, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/PlaybackInfo"):
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"MediaSources":[` + source + `]}`))
+		case r.URL.Path == "/Items":
+			w.Write([]byte(`{"Items":[{"Id":"400","Path":"/media/400.mkv","MediaSources":[` + source + `]}]}`))
+		case r.Header.Get("X-Emby-Token") != "token":
+			w.WriteHeader(http.StatusUnauthorized)
+		case strings.HasSuffix(r.URL.Path, "/Subtitles/2/Stream.srt"):
+			w.Write([]byte("1\n00:00:01,000 --> 00:00:03,000\nHello\n\n2\n00:00:03,200 --> 00:00:05,000\nHow are you?\n\n3\n00:00:09,000 --> 00:00:10,000\nBye\n"))
+		case strings.HasSuffix(r.URL.Path, "/Subtitles/3/Stream.srt"):
+			w.Write([]byte("1\n00:00:01,100 --> 00:00:05,000\n你好，\n最近好吗？\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}, func(s *config.Snapshot) {
+		s.Subtitle = config.SubtitleSetting{Enable: true, Bilingual: true}
+	})
+
+	resp, err := client.Get(mediaWarp.URL + "/emby/Items/400/PlaybackInfo?X-Emby-Token=token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var playbackInfo emby.PlaybackInfoResponse
+	err = json.NewDecoder(resp.Body).Decode(&playbackInfo)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	streams := playbackInfo.MediaSources[0].MediaStreams
+	bilingual := streams[len(streams)-1]
+	if bilingual.Index == nil || *bilingual.Index != 103002 || bilingual.DeliveryURL == nil {
+		t.Fatalf("未添加双语字幕流：%+v", bilingual)
+	}
+	// 查询参数的键已被 QueryCaseInsensitive 中间件转为小写
+	if want := "/Videos/400/mediasource_400/Subtitles/103002/0/Stream.ass?x-emby-token=token"; *bilingual.DeliveryURL != want {
+		t.Errorf("双语字幕流链接错误。期望: %s, 实际: %s", want, *bilingual.DeliveryURL)
+	}
+
+	resp, err = client.Get(mediaWarp.URL + *bilingual.DeliveryURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	for _, want := range []string{
+		"[V4+ Styles]",
+		"Dialogue: 0,0:00:01.10,0:00:05.00,Default,,0,0,0,,你好， 最近好吗？\\NHello How are you?\n",
+		"Dialogue: 0,0:00:09.00,0:00:10.00,Default,,0,0,0,,Bye\n",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("双语字幕缺少：%q\n实际：\n%s", want, body)
+		}
+	}
+
+	for target, status := range map[string]int{
+		"/Videos/400/mediasource_400/Subtitles/103002/0/Stream.ass":               http.StatusUnauthorized, // 未认证
+		"/Videos/400/mediasource_400/Subtitles/103004/0/Stream.ass?api_key=token": http.StatusNotFound,     // 英文字幕流不存在
+		"/Videos/400/mediasource_400/Subtitles/102003/0/Stream.ass?api_key=token": http.StatusNotFound,     // 字幕流语言不匹配
+	} {
+		resp, err = client.Get(mediaWarp.URL + target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != status {
+			t.Errorf("%s 状态码错误。期望: %d, 实际: %d", target, status, resp.StatusCode)
+		}
+	}
+}
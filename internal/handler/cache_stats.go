@@ -132,9 +132,8 @@ func (h *CacheStatsHandler) getTotalCacheSize() int {
 func (h *CacheStatsHandler) ClearCache(ctx *gin.Context) {
 	logging.Debug("======= ClearCache =======")
 
-	// 重新创建缓存实例来清空缓存
-	cache.GlobalPlaybackCache.Close()
-	cache.GlobalPlaybackCache = cache.NewPlaybackInfoCache(5 * time.Minute)
+	// 原地清空缓存（同时清空持久化存储），各处理器持有的缓存实例保持有效
+	cache.GlobalPlaybackCache.Clear()
 
 	ctx.JSON(http.StatusOK, gin.H{
 		"message":   "Cache cleared successfully",
@@ -188,7 +187,7 @@ func (h *CacheStatsHandler) GetCacheHealth(ctx *gin.Context) {
 	if hitRates.StrmTypeHitRate < 85 {
 		issues = append(issues, "Strm type hit rate below 85%")
 	}
-	if hitRates.AlistLinkHitRate < 60 {
+	if stats.AlistLinkHits+stats.AlistLinkMisses > 0 && hitRates.AlistLinkHitRate < 60 { // 仅在有 AlistStrm 请求时检查
 		issues = append(issues, "Alist link hit rate below 60%")
 	}
 
@@ -216,6 +215,7 @@ func RegisterCacheStatsRoutes(router *gin.Engine) {
 		cacheGroup.GET("/stats", handler.GetCacheStats)
 		cacheGroup.GET("/health", handler.GetCacheHealth)
 		cacheGroup.GET("/warmup/stats", handler.GetWarmupStats)
+		cacheGroup.GET("/expiry", handler.GetExpiryStats)
 		cacheGroup.POST("/clear", handler.ClearCache)
 		cacheGroup.POST("/warmup", handler.WarmUpCache)
 	}
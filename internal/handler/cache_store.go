@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"MediaWarp/internal/cache"
+	"MediaWarp/internal/logging"
+	"time"
+)
+
+const (
+	redirectURLBucket    = "redirect_url"   // 下载链接缓存在持久化存储中的 bucket 名称
+	cacheCompactInterval = 30 * time.Minute // 持久化缓存过期记录清理周期
+)
+
+var cacheStore *cache.BoltStore
+
+// 初始化持久化缓存
+//
+// 打开持久化缓存文件，加载未过期的下载链接和媒体项信息，并定期清理过期记录
+func InitCacheStore(path string) error {
+	if cacheStore != nil { // 重复初始化时沿用已打开的存储
+		return nil
+	}
+	store, err := cache.OpenBoltStore(path)
+	if err != nil {
+		return err
+	}
+	if removed, err := store.Compact(); err != nil {
+		logging.Warning("清理持久化缓存过期记录失败：", err)
+	} else if removed > 0 {
+		logging.Debug("已清理持久化缓存过期记录：", removed)
+	}
+
+	redirectLoaded, err := redirectURLCache.Persist(store, redirectURLBucket)
+	if err != nil {
+		store.Close()
+		return err
+	}
+	playbackLoaded, err := cache.GlobalPlaybackCache.Persist(store)
+	if err != nil {
+		store.Close()
+		return err
+	}
+	store.StartCompaction(cacheCompactInterval, func(err error) {
+		logging.Warning("清理持久化缓存过期记录失败：", err)
+	})
+	cacheStore = store
+	logging.Infof("持久化缓存已加载：%s，下载链接 %d 条，媒体项信息 %d 条", path, redirectLoaded, playbackLoaded)
+	return nil
+}
+
+// 关闭持久化缓存
+func CloseCacheStore() {
+	if cacheStore == nil {
+		return
+	}
+	if err := cacheStore.Close(); err != nil {
+		logging.Warning("关闭持久化缓存失败：", err)
+	}
+	cacheStore = nil
+}
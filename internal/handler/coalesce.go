@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"MediaWarp/internal/logging"
+	"MediaWarp/internal/metrics"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// 请求合并组
+//
+// 相同键的并发请求只有一个会真正执行，其余请求等待并共享其结果
+type requestGroup struct {
+	group     singleflight.Group
+	calls     *metrics.CounterMetric // 实际执行次数
+	coalesced *metrics.CounterMetric // 被合并（未执行）的请求次数
+}
+
+func newRequestGroup(name string) *requestGroup {
+	return &requestGroup{
+		calls:     metrics.GlobalCollector.RegisterCounter(name+"_calls_total", nil),
+		coalesced: metrics.GlobalCollector.RegisterCounter(name+"_coalesced_total", nil),
+	}
+}
+
+// 执行请求，相同键的请求正在执行时等待其结果
+func (g *requestGroup) Do(key string, fn func() (any, error)) (any, error) {
+	executed := false // fn 在调用方的协程中同步执行，只有执行者会修改自己的 executed
+	value, err, _ := g.group.Do(key, func() (any, error) {
+		executed = true
+		g.calls.Inc()
+		return fn()
+	})
+	if !executed {
+		g.coalesced.Inc()
+		logging.Debug("合并重复请求：", key)
+	}
+	return value, err
+}
+
+var (
+	linkGroup = newRequestGroup("link_resolve") // 下载链接解析，键为 path|UA
+	itemGroup = newRequestGroup("item_query")   // 媒体项信息查询，键为服务器类型和缓存键
+)
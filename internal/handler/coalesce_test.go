@@ -0,0 +1,117 @@
+package handler_test
+
+import (
+	"MediaWarp/constants"
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/metrics"
+	"MediaWarp/internal/resolver"
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// 解析较慢的解析器，记录解析次数
+type slowResolver struct {
+	count atomic.Int32
+}
+
+func (r *slowResolver) Name() string {
+	return "slow"
+}
+
+func (r *slowResolver) Resolve(ctx context.Context, link string, userAgent string) (string, error) {
+	r.count.Add(1)
+	time.Sleep(200 * time.Millisecond)
+	return "https://cdn.example.com/movie.mkv", nil
+}
+
+func TestCoalesceResolve(t *testing.T) {
+	slowResolver := &slowResolver{}
+	resolver.Register("slow", slowResolver)
+
+	localPath := t.TempDir()
+	strmPath := filepath.Join(localPath, "Movie.strm")
+	if err := os.WriteFile(strmPath, []byte("slow://movie.mkv"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mediaWarp, client := startMediaWarp(t, constants.PLEX, plexItemStub("3", strmPath), func(s *config.Snapshot) {
+		s.MediaSync = config.MediaSyncSetting{{Name: "slow", Remote: "slow:", LocalPath: localPath}}
+	})
+	client.Get(mediaWarp.URL + "/video/:/transcode/universal/decision?path=/library/metadata/3")
+
+	coalescedMetric, ok := metrics.GlobalCollector.GetMetric("link_resolve_coalesced_total")
+	if !ok {
+		t.Fatal("未注册请求合并指标")
+	}
+	before := coalescedMetric.Value().(int64)
+
+	const concurrency = 5
+	var wg sync.WaitGroup
+	for range concurrency { // 模拟 Infuse 同时发起多个串流请求
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest(http.MethodGet, mediaWarp.URL+"/library/parts/3/1/file.mkv", nil)
+			req.Header.Set("User-Agent", "Infuse/7.0")
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+			if location := resp.Header.Get("Location"); location != "https://cdn.example.com/movie.mkv" {
+				t.Errorf("重定向链接错误：%s", location)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if count := slowResolver.count.Load(); count != 1 {
+		t.Errorf("解析次数错误：%d", count)
+	}
+	if coalesced := coalescedMetric.Value().(int64) - before; coalesced != concurrency-1 {
+		t.Errorf("合并请求数错误：%d", coalesced)
+	}
+
+	// Emby：同一媒体项的播放信息、详情页预加载和串流请求只查询一次媒体项信息
+	var itemQueries atomic.Int32
+	itemStub := embyItemStub("91", strmPath, "slow://movie.mkv")
+	mediaWarp, client = startMediaWarp(t, constants.EMBY, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/Items" {
+			itemQueries.Add(1)
+			time.Sleep(200 * time.Millisecond)
+		}
+		itemStub(w, r)
+	}, func(s *config.Snapshot) {
+		s.MediaSync = config.MediaSyncSetting{{Name: "slow", Remote: "slow:", LocalPath: localPath}}
+	})
+	paths := []string{"/emby/Items/91/PlaybackInfo", "/emby/Users/user/Items/91"}
+	for range concurrency {
+		paths = append(paths, "/emby/videos/91/stream?MediaSourceId=91&Static=true")
+	}
+	for _, path := range paths {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(mediaWarp.URL + path)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+			if strings.Contains(path, "/stream") && resp.Header.Get("Location") != "https://cdn.example.com/movie.mkv" {
+				t.Errorf("%s 重定向链接错误：%d %s", path, resp.StatusCode, resp.Header.Get("Location"))
+			}
+		}()
+	}
+	wg.Wait()
+	if count := itemQueries.Load(); count != 1 {
+		t.Errorf("媒体项信息查询次数错误：%d", count)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"MediaWarp/internal/config"
 	"MediaWarp/internal/logging"
 	"MediaWarp/internal/process"
+	"MediaWarp/utils"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -876,19 +877,13 @@ func taskPageAuth() gin.HandlerFunc {
 		}
 
 		logging.Info("Task页面认证检查",
-			"apiKey", apiKey,
-			"expected", config.MediaServer.AUTH,
-			"path", c.Request.URL.Path,
-			"query", c.Request.URL.RawQuery,
-			"header_X-API-Key", c.GetHeader("X-API-Key"),
-			"query_apiKey", c.Query("apiKey"),
-			"query_apikey", c.Query("apikey"))
+			"apiKey", utils.MaskSecret(apiKey),
+			"path", c.Request.URL.Path)
 
 		// 验证API密钥
-		if apiKey == "" || apiKey != config.MediaServer.AUTH {
+		if apiKey == "" || apiKey != config.MediaServer().AUTH {
 			logging.Warning("Task页面认证失败",
-				"apiKey", apiKey,
-				"expected", config.MediaServer.AUTH,
+				"apiKey", utils.MaskSecret(apiKey),
 				"reason", func() string {
 					if apiKey == "" {
 						return "API Key为空"
@@ -901,7 +896,7 @@ func taskPageAuth() gin.HandlerFunc {
 			return
 		}
 
-		logging.Info("Task页面认证成功", "apiKey", apiKey)
+		logging.Info("Task页面认证成功", "apiKey", utils.MaskSecret(apiKey))
 		c.Next()
 	}
 }
@@ -918,17 +913,17 @@ func TaskCronRouter(router *gin.Engine) {
 	})
 
 	// 任务CRUD操作
-	router.POST("/task", apiKeyAuth(), addTask)            // 创建任务
-	router.GET("/tasks", apiKeyAuth(), listTasks)          // 获取任务列表
-	router.GET("/task/:name", apiKeyAuth(), getTaskDetail) // 获取单个任务详情
-	router.PUT("/task/:name", apiKeyAuth(), updateTask)    // 更新任务
-	router.DELETE("/task/:name", apiKeyAuth(), deleteTask) // 删除任务
+	router.POST("/task", APIKeyAuth(), addTask)            // 创建任务
+	router.GET("/tasks", APIKeyAuth(), listTasks)          // 获取任务列表
+	router.GET("/task/:name", APIKeyAuth(), getTaskDetail) // 获取单个任务详情
+	router.PUT("/task/:name", APIKeyAuth(), updateTask)    // 更新任务
+	router.DELETE("/task/:name", APIKeyAuth(), deleteTask) // 删除任务
 
 	// 专门的自定义同步任务端点
-	router.POST("/task/custom-sync", apiKeyAuth(), addCustomSyncTask) // 创建自定义同步任务
+	router.POST("/task/custom-sync", APIKeyAuth(), addCustomSyncTask) // 创建自定义同步任务
 
 	// 其他端点
-	router.GET("/task/functions", apiKeyAuth(), getTaskFunctions)          // 获取可用函数列表
-	router.GET("/task/manager/status", apiKeyAuth(), getTaskManagerStatus) // 获取任务管理器状态
+	router.GET("/task/functions", APIKeyAuth(), getTaskFunctions)          // 获取可用函数列表
+	router.GET("/task/manager/status", APIKeyAuth(), getTaskManagerStatus) // 获取任务管理器状态
 	router.GET("/task", taskPageAuth(), TaskCronHandler)                   // 任务管理页面
 }
@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"MediaWarp/constants"
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/logging"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 编译后的自定义路由规则
+type customRule struct {
+	config.RouteRule
+	regexp *regexp.Regexp
+	proxy  *httputil.ReverseProxy // Proxy 规则的反向代理
+}
+
+// 创建配置中的自定义路由规则
+//
+// 规则已在加载配置时验证，使用配置中编译好的匹配表达式
+func compileCustomRules(rules []config.RouteRule) ([]*customRule, error) {
+	compiled := make([]*customRule, 0, len(rules))
+	for i, setting := range rules {
+		rule := &customRule{RouteRule: setting, regexp: setting.Regexp()}
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule-%d", i+1)
+		}
+		if rule.regexp == nil {
+			return nil, fmt.Errorf("自定义路由规则 %s 的匹配表达式 %q 无效", rule.Name, rule.Match)
+		}
+		if rule.Action == constants.RouteRuleProxy {
+			target, err := url.Parse(rule.Target)
+			if err != nil {
+				return nil, fmt.Errorf("自定义路由规则 %s 的上游地址无效：%v", rule.Name, err)
+			}
+			rule.proxy = httputil.NewSingleHostReverseProxy(target)
+			director := rule.proxy.Director
+			rule.proxy.Director = func(req *http.Request) {
+				director(req)
+				req.Host = target.Host
+			}
+		}
+		compiled = append(compiled, rule)
+	}
+	return compiled, nil
+}
+
+// 获取自定义路由规则
+//
+// 按配置顺序排列，处理器未结束请求（ctx.IsAborted 为 false）时应继续匹配之后的规则和内置路由
+func GetCustomRouteRules() []RegexpRouteRule {
+	if current := state.Load(); current != nil {
+		return current.customRouteRules
+	}
+	return nil
+}
+
+// 判断规则是否匹配请求
+func (rule *customRule) match(req *http.Request) bool {
+	if len(rule.Methods) > 0 && !slices.ContainsFunc(rule.Methods, func(method string) bool {
+		return strings.EqualFold(method, req.Method)
+	}) {
+		return false
+	}
+	return rule.regexp.MatchString(req.URL.Path)
+}
+
+// 是否结束请求
+func (rule *customRule) terminal() bool {
+	return rule.Action != constants.RouteRuleRewrite && rule.Action != constants.RouteRuleHeader
+}
+
+// 按请求路径展开 Target 中的分组引用
+func (rule *customRule) expand(requestPath string) string {
+	submatches := rule.regexp.FindStringSubmatchIndex(requestPath)
+	if submatches == nil {
+		return rule.Target
+	}
+	return string(rule.regexp.ExpandString(nil, rule.Target, requestPath, submatches))
+}
+
+// 规则返回的状态码
+func (rule *customRule) status(defaultStatus int) int {
+	if rule.Status != 0 {
+		return rule.Status
+	}
+	return defaultStatus
+}
+
+// 执行自定义路由规则
+func (rule *customRule) handle(ctx *gin.Context) {
+	req := ctx.Request
+	if !rule.match(req) {
+		return
+	}
+	for _, name := range rule.StripHeaders {
+		req.Header.Del(name)
+	}
+	for _, header := range rule.SetHeaders {
+		req.Header.Set(header.Name, header.Value)
+	}
+
+	switch rule.Action {
+	case constants.RouteRuleRedirect:
+		location := rule.expand(req.URL.Path)
+		if req.URL.RawQuery != "" && !strings.Contains(location, "?") {
+			location += "?" + req.URL.RawQuery
+		}
+		logging.Debugf("自定义路由规则 %s：%s 跳转至 %s", rule.Name, req.URL.Path, location)
+		ctx.Redirect(rule.status(http.StatusFound), location)
+		ctx.Abort()
+	case constants.RouteRuleRewrite:
+		rewritten := rule.expand(req.URL.Path)
+		logging.Debugf("自定义路由规则 %s：%s 重写为 %s", rule.Name, req.URL.Path, rewritten)
+		req.URL.Path = rewritten
+		req.URL.RawPath = ""
+	case constants.RouteRuleBlock:
+		logging.Infof("自定义路由规则 %s 拒绝请求：%s %s", rule.Name, req.Method, req.URL.Path)
+		ctx.AbortWithStatus(rule.status(http.StatusForbidden))
+	case constants.RouteRuleProxy:
+		logging.Debugf("自定义路由规则 %s：%s 转发至 %s", rule.Name, req.URL.Path, rule.Target)
+		rule.proxy.ServeHTTP(ctx.Writer, req)
+		ctx.Abort()
+	}
+}
+
+// 命中的自定义路由规则
+type RouteRuleHit struct {
+	Name   string
+	Action constants.RouteRuleAction
+	Target string `json:",omitempty"` // 展开后的跳转地址、重写后的路径或 Proxy 的上游地址
+}
+
+// 路由试运行结果
+type RouteMatch struct {
+	Method   string
+	URL      string
+	Path     string         // 自定义路由规则处理后交给上游的请求路径
+	Rules    []RouteRuleHit // 依次命中的自定义路由规则
+	Upstream string         `json:",omitempty"` // 处理请求的上游媒体服务器，请求被自定义路由规则结束时为空
+	Builtin  string         `json:",omitempty"` // 命中的内置路由正则，为空时直接反向代理至上游
+}
+
+// 路由试运行
+//
+// 按 RegexpRouterHandler 的顺序匹配自定义路由规则、选择上游和匹配内置路由，不执行任何动作
+func MatchRoute(method string, rawURL string, host string) (*RouteMatch, error) {
+	req, err := http.NewRequest(method, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if host != "" {
+		req.Host = host
+	}
+
+	var rules []*customRule
+	if current := state.Load(); current != nil {
+		rules = current.customRules
+	}
+	result := &RouteMatch{Method: req.Method, URL: rawURL, Rules: []RouteRuleHit{}}
+	for _, rule := range rules {
+		if !rule.match(req) {
+			continue
+		}
+		hit := RouteRuleHit{Name: rule.Name, Action: rule.Action}
+		switch rule.Action {
+		case constants.RouteRuleRedirect, constants.RouteRuleRewrite:
+			hit.Target = rule.expand(req.URL.Path)
+		case constants.RouteRuleProxy:
+			hit.Target = rule.Target
+		}
+		result.Rules = append(result.Rules, hit)
+		if rule.Action == constants.RouteRuleRewrite {
+			req.URL.Path = hit.Target
+		}
+		if rule.terminal() {
+			result.Path = req.URL.Path
+			return result, nil
+		}
+	}
+
+	u, err := matchUpstream(req)
+	if err != nil {
+		return nil, err
+	}
+	result.Path = req.URL.Path
+	if u == nil {
+		return result, nil
+	}
+	result.Upstream = u.setting.Name
+	for _, rule := range u.handler.GetRegexpRouteRules() {
+		if rule.Regexp.MatchString(req.URL.Path) {
+			result.Builtin = rule.Regexp.String()
+			break
+		}
+	}
+	return result, nil
+}
+
+// 路由试运行处理器
+//
+// GET /MediaWarp/rules/match?url=/emby/Items/1/PlaybackInfo&method=GET&host=emby.example.com
+func RouteMatchHandler(ctx *gin.Context) {
+	rawURL := ctx.Query("url")
+	if rawURL == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Missing url"})
+		return
+	}
+	result, err := MatchRoute(ctx.DefaultQuery("method", http.MethodGet), rawURL, ctx.Query("host"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,106 @@
+package handler_test
+
+import (
+	"MediaWarp/constants"
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/handler"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestCustomRouteRules(t *testing.T) {
+	otherStub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("other " + r.URL.Path))
+	}))
+	defer otherStub.Close()
+
+	embyStub := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("emby " + r.Method + " " + r.URL.Path + " proto=" + r.Header.Get("X-Forwarded-Proto") + " referer=" + r.Header.Get("Referer")))
+	}
+	mediaWarp, client := startMediaWarp(t, constants.EMBY, embyStub, func(s *config.Snapshot) {
+		s.MediaServers[0].Name = "emby"
+		s.MediaServers[0].AUTH = "test-key"
+		s.Rules = []config.RouteRule{
+			{Name: "OldWeb", Match: `^/web/old/(.*)$`, Action: constants.RouteRuleRedirect, Target: "/web/$1", Status: http.StatusMovedPermanently},
+			{Name: "NoDelete", Match: `(?i)^(/emby)?/Items/\d+$`, Methods: []string{"delete"}, Action: constants.RouteRuleBlock},
+			{Name: "Headers", Match: `(?i)^/legacy/`, Action: constants.RouteRuleHeader, StripHeaders: []string{"Referer"}, SetHeaders: []config.HeaderSetting{{Name: "X-Forwarded-Proto", Value: "https"}}},
+			{Name: "Legacy", Match: `(?i)^/legacy/(.*)$`, Action: constants.RouteRuleRewrite, Target: "/emby/$1"},
+			{Name: "Other", Match: `^/other/`, Action: constants.RouteRuleProxy, Target: otherStub.URL},
+		}
+	})
+
+	for caseName, testCase := range map[string]struct {
+		Method string
+		Path   string
+		Status int
+		Want   string
+	}{
+		"跳转":       {http.MethodGet, "/web/old/index.html?a=1", http.StatusMovedPermanently, "/web/index.html?a=1"},
+		"拒绝":       {http.MethodDelete, "/emby/Items/100", http.StatusForbidden, ""},
+		"方法不匹配":    {http.MethodGet, "/emby/Items/100", http.StatusOK, "emby GET /emby/Items/100"},
+		"重写并修改请求头": {http.MethodGet, "/legacy/System/Info", http.StatusOK, "emby GET /emby/System/Info proto=https referer="},
+		"转发至其他上游":  {http.MethodGet, "/other/ping", http.StatusOK, "other /other/ping"},
+		"未匹配":      {http.MethodGet, "/emby/System/Info", http.StatusOK, "emby GET /emby/System/Info proto= referer=https://example.com"},
+	} {
+		t.Run(caseName, func(t *testing.T) {
+			req, _ := http.NewRequest(testCase.Method, mediaWarp.URL+testCase.Path, nil)
+			req.Header.Set("Referer", "https://example.com")
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != testCase.Status {
+				t.Fatalf("状态码错误。期望: %d, 实际: %d", testCase.Status, resp.StatusCode)
+			}
+			got := string(body)
+			if location := resp.Header.Get("Location"); location != "" {
+				got = location
+			}
+			if !strings.HasPrefix(got, testCase.Want) {
+				t.Errorf("响应错误。期望: %s, 实际: %s", testCase.Want, got)
+			}
+		})
+	}
+
+	result, err := handler.MatchRoute(http.MethodGet, "/legacy/Items/1/PlaybackInfo?api_key=abc", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Rules) != 2 || result.Rules[1].Name != "Legacy" || result.Path != "/emby/Items/1/PlaybackInfo" || result.Upstream != "emby" {
+		t.Errorf("试运行结果错误：%+v", result)
+	}
+	if result.Builtin != constants.EmbyRegexp.Router.ModifyPlaybackInfo.String() {
+		t.Errorf("试运行应命中 PlaybackInfo 内置路由，实际: %s", result.Builtin)
+	}
+
+	matchURL := mediaWarp.URL + "/MediaWarp/rules/match?url=" + url.QueryEscape("/legacy/Items/1/PlaybackInfo")
+	for key, status := range map[string]int{"": http.StatusUnauthorized, "test-key": http.StatusOK} {
+		req, _ := http.NewRequest(http.MethodGet, matchURL, nil)
+		req.Header.Set("X-API-Key", key)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != status {
+			t.Errorf("API Key 为 %q 时试运行接口状态码错误。期望: %d, 实际: %d", key, status, resp.StatusCode)
+		}
+	}
+
+	rules := []config.RouteRule{{Name: "Bad", Match: `(`, Action: constants.RouteRuleBlock}}
+	if err := config.ValidateRouteRules(rules); err == nil || !strings.Contains(err.Error(), "Rules[Bad].Match") {
+		t.Errorf("无效的正则表达式应返回错误，实际: %v", err)
+	}
+	config.Update(func(s *config.Snapshot) {
+		s.Rules = rules
+	})
+	if err := handler.Init(); err == nil {
+		t.Error("未通过验证的规则应返回错误")
+	}
+}
@@ -4,15 +4,13 @@ import (
 	"MediaWarp/constants"
 	"MediaWarp/internal/cache"
 	"MediaWarp/internal/config"
+	"MediaWarp/internal/limiter"
 	"MediaWarp/internal/logging"
-	"MediaWarp/internal/rclone"
 	"MediaWarp/internal/service/emby"
 	"MediaWarp/utils"
 	"bytes"
-	"context"
 	"encoding/json"
 	"fmt"
-	"math/rand"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
@@ -72,6 +70,9 @@ type EmbyServerHandler struct {
 	routerRules []RegexpRouteRule        // 正则路由规则
 	proxy       *httputil.ReverseProxy   // 反向代理
 	cache       *cache.PlaybackInfoCache // 播放信息缓存
+	sessions    *sessionUserCache        // 设备对应的会话用户
+
+	cacheNamespace string // 媒体项缓存命名空间（多个上游时避免 ItemId 冲突）
 }
 
 // 初始化
@@ -79,6 +80,7 @@ func NewEmbyServerHandler(addr string, apiKey string) (*EmbyServerHandler, error
 	var embyServerHandler = EmbyServerHandler{}
 	embyServerHandler.server = emby.New(addr, apiKey)
 	embyServerHandler.cache = cache.GlobalPlaybackCache // 使用全局缓存实例
+	embyServerHandler.sessions = &sessionUserCache{entries: make(map[string]sessionUsers)}
 	target, err := url.Parse(embyServerHandler.server.GetEndpoint())
 	if err != nil {
 		return nil, err
@@ -91,20 +93,6 @@ func NewEmbyServerHandler(addr string, apiKey string) (*EmbyServerHandler, error
 				Regexp:  constants.EmbyRegexp.Router.VideosHandler,
 				Handler: embyServerHandler.VideosHandler,
 			},
-			{
-				Regexp: constants.EmbyRegexp.Router.ModifyPlaybackInfo,
-				Handler: responseModifyCreater(
-					&httputil.ReverseProxy{Director: embyServerHandler.proxy.Director},
-					embyServerHandler.ModifyPlaybackInfo,
-				),
-			},
-			{
-				Regexp: constants.EmbyRegexp.Router.ModifyBaseHtmlPlayer,
-				Handler: responseModifyCreater(
-					&httputil.ReverseProxy{Director: embyServerHandler.proxy.Director},
-					embyServerHandler.ModifyBaseHtmlPlayer,
-				),
-			},
 			{
 				Regexp:  constants.EmbyRegexp.Router.StreamStrmHandler,
 				Handler: embyServerHandler.VideosHandler,
@@ -113,36 +101,85 @@ func NewEmbyServerHandler(addr string, apiKey string) (*EmbyServerHandler, error
 				Regexp:  regexp.MustCompile(`(?i)^(/emby)?/Users/[^/]+/Items/\d+$`),
 				Handler: embyServerHandler.ItemDetailHandler,
 			},
+			{
+				Regexp:  constants.EmbyRegexp.Router.SessionsPlaying,
+				Handler: embyServerHandler.SessionsPlayingHandler,
+			},
+			{
+				Regexp:  constants.EmbyRegexp.Router.StrmEndpoint,
+				Handler: embyServerHandler.StrmHandler,
+			},
 		}
-
-		if config.Web.Enable {
-			if config.Web.Index || config.Web.Head != "" || config.Web.ExternalPlayerUrl || config.Web.VideoTogether {
-				embyServerHandler.routerRules = append(embyServerHandler.routerRules,
-					RegexpRouteRule{
-						Regexp: constants.EmbyRegexp.Router.ModifyIndex,
-						Handler: responseModifyCreater(
-							&httputil.ReverseProxy{Director: embyServerHandler.proxy.Director},
-							embyServerHandler.ModifyIndex,
-						),
-					},
-				)
-			}
-		}
-		if config.Subtitle.Enable && config.Subtitle.SRT2ASS {
+		if config.Subtitle().Enable && config.Subtitle().Bilingual { // 双语虚拟字幕流不转发至上游，需在字幕改写器之前匹配
 			embyServerHandler.routerRules = append(embyServerHandler.routerRules,
 				RegexpRouteRule{
-					Regexp: constants.EmbyRegexp.Router.ModifySubtitles,
-					Handler: responseModifyCreater(
-						&httputil.ReverseProxy{Director: embyServerHandler.proxy.Director},
-						embyServerHandler.ModifySubtitles,
-					),
+					Regexp:  constants.EmbyRegexp.Router.BilingualSubtitles,
+					Handler: embyServerHandler.BilingualSubtitlesHandler,
 				},
 			)
 		}
+
+		rewriters, err := newRewriterChain(embyServerHandler.rewriters())
+		if err != nil {
+			return nil, err
+		}
+		embyServerHandler.routerRules = append(embyServerHandler.routerRules, rewriters.routeRules(embyServerHandler.proxy.Director)...)
 	}
 	return &embyServerHandler, nil
 }
 
+// 内置响应改写器
+func (embyServerHandler *EmbyServerHandler) rewriters() []ResponseRewriter {
+	return []ResponseRewriter{
+		{
+			Name:        "MediaSourcePolicy",
+			Regexp:      constants.EmbyRegexp.Router.ModifyPlaybackInfo,
+			ContentType: "application/json",
+			Priority:    100,
+			Enable:      true,
+			Rewrite:     embyServerHandler.SortPlaybackInfo,
+		},
+		{
+			Name:        "StrmPlaybackInfo",
+			Regexp:      constants.EmbyRegexp.Router.ModifyPlaybackInfo,
+			ContentType: "application/json",
+			Priority:    200,
+			Enable:      true,
+			Rewrite:     embyServerHandler.ModifyPlaybackInfo,
+		},
+		{
+			Name:        "BilingualSubtitles",
+			Regexp:      constants.EmbyRegexp.Router.ModifyPlaybackInfo,
+			ContentType: "application/json",
+			Priority:    300,
+			Enable:      config.Subtitle().Enable && config.Subtitle().Bilingual,
+			Rewrite:     embyServerHandler.AddBilingualSubtitles,
+		},
+		{
+			Name:     "BaseHtmlPlayer",
+			Regexp:   constants.EmbyRegexp.Router.ModifyBaseHtmlPlayer,
+			Priority: 100,
+			Enable:   true,
+			Rewrite:  embyServerHandler.ModifyBaseHtmlPlayer,
+		},
+		{
+			Name:        "WebIndex",
+			Regexp:      constants.EmbyRegexp.Router.ModifyIndex,
+			ContentType: "text/html",
+			Priority:    100,
+			Enable:      config.Web().Enable && (config.Web().Index || config.Web().Head != "" || config.Web().ExternalPlayerUrl || config.Web().VideoTogether || config.Web().SubtitleOffset),
+			Rewrite:     embyServerHandler.ModifyIndex,
+		},
+		{
+			Name:     "Subtitles",
+			Regexp:   constants.EmbyRegexp.Router.ModifySubtitles,
+			Priority: 100,
+			Enable:   config.Subtitle().Enable,
+			Rewrite:  embyServerHandler.ModifySubtitles,
+		},
+	}
+}
+
 // 转发请求至上游服务器
 func (embyServerHandler *EmbyServerHandler) ReverseProxy(rw http.ResponseWriter, req *http.Request) {
 	embyServerHandler.proxy.ServeHTTP(rw, req)
@@ -153,27 +190,52 @@ func (embyServerHandler *EmbyServerHandler) GetRegexpRouteRules() []RegexpRouteR
 	return embyServerHandler.routerRules
 }
 
+// 获取媒体项信息
+//
+// 优先从缓存中获取，未命中时请求上游并缓存结果，相同媒体项的并发请求只会请求一次上游
+func (embyServerHandler *EmbyServerHandler) queryItem(mediaSourceID string) (*emby.EmbyResponse, error) {
+	cacheKey := namespacedKey(embyServerHandler.cacheNamespace, mediaSourceID)
+	if cachedItem, found := embyServerHandler.cache.GetItemInfo(cacheKey); found {
+		logging.Info("媒体项信息缓存命中：", mediaSourceID)
+		return cachedItem.EmbyItem, nil
+	}
+
+	logging.Info("媒体项信息缓存未命中，从上游获取：", mediaSourceID)
+	value, err := itemGroup.Do("emby|"+cacheKey, func() (any, error) {
+		result, err := embyServerHandler.server.ItemsServiceQueryItem(mediaSourceID, 1, "Path,MediaSources")
+		if err != nil {
+			return nil, err
+		}
+		// 缓存结果（30分钟TTL）
+		embyServerHandler.cache.SetItemInfo(cacheKey, result, 30*time.Minute)
+		return result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*emby.EmbyResponse), nil
+}
+
 // 修改播放信息请求
 //
 // /Items/:itemId/PlaybackInfo
 // 强制将 HTTPStrm 设置为支持直链播放和转码、AlistStrm 设置为支持直链播放并且禁止转码
-func (embyServerHandler *EmbyServerHandler) ModifyPlaybackInfo(rw *http.Response) error {
+// HTTPStrm.TransCode 允许的客户端和视频编码保留上游的转码设置（仅限 Strm 内容为 HTTP 链接的媒体源，
+// rclone 链接需将 Strm 内容设置为 MediaWarp 的 Strm 下载链接接口，见 StrmHandler）
+func (embyServerHandler *EmbyServerHandler) ModifyPlaybackInfo(rw *http.Response, body []byte) ([]byte, error) {
 	logging.Debug("=======  ModifyPlaybackInfo ======= ")
 
-	defer rw.Body.Close()
-	body, err := readBody(rw)
-	if err != nil {
-		logging.Warning("读取 Body 出错：", err)
-		return err
-	}
-
 	var playbackInfoResponse emby.PlaybackInfoResponse
-	if err = json.Unmarshal(body, &playbackInfoResponse); err != nil {
-		logging.Warning("解析 emby.PlaybackInfoResponse Json 错误：", err)
-		return err
+	err := json.Unmarshal(body, &playbackInfoResponse)
+	if err != nil {
+		return nil, fmt.Errorf("解析 emby.PlaybackInfoResponse Json 错误：%w", err)
 	}
 
+	userAgent := rw.Request.Header.Get("User-Agent")
 	for index, mediasource := range playbackInfoResponse.MediaSources {
+		if mediasource.ID == nil {
+			continue
+		}
 		mediaSourceID := strings.Replace(*mediasource.ID, "mediasource_", "", 1)
 		logging.Debug("处理媒体源：" + mediaSourceID)
 
@@ -181,20 +243,14 @@ func (embyServerHandler *EmbyServerHandler) ModifyPlaybackInfo(rw *http.Response
 		var itemResponse *emby.EmbyResponse
 		var item emby.BaseItemDto
 
-		if cachedItem, found := embyServerHandler.cache.GetItemInfo(mediaSourceID); found {
-			logging.Info("媒体项信息缓存命中：", mediaSourceID)
-			itemResponse = cachedItem.EmbyItem
-		} else {
-			logging.Info("媒体项信息缓存未命中，从上游获取：", mediaSourceID)
-			// 直接调用API（请求去重功能已移除）
-			result, err := embyServerHandler.server.ItemsServiceQueryItem(mediaSourceID, 1, "Path,MediaSources")
-			if err != nil {
-				logging.Warning("请求 ItemsServiceQueryItem 失败：", err)
-				continue
-			}
-			itemResponse = result
-			// 缓存结果（30分钟TTL）
-			embyServerHandler.cache.SetItemInfo(mediaSourceID, itemResponse, 30*time.Minute)
+		itemResponse, err = embyServerHandler.queryItem(mediaSourceID)
+		if err != nil {
+			logging.Warning("请求 ItemsServiceQueryItem 失败：", err)
+			continue
+		}
+		if len(itemResponse.Items) == 0 || itemResponse.Items[0].Path == nil {
+			logging.Warning("未找到媒体源对应的媒体项：", mediaSourceID)
+			continue
 		}
 
 		item = itemResponse.Items[0]
@@ -215,8 +271,16 @@ func (embyServerHandler *EmbyServerHandler) ModifyPlaybackInfo(rw *http.Response
 			embyServerHandler.cache.SetStrmType(*item.Path, strmFileType, strmOption, 1*time.Hour)
 		}
 		switch strmFileType {
-		case constants.HTTPStrm: // HTTPStrm 设置支持直链播放并且强制关闭转码
-			// 默认强制关闭转码
+		case constants.HTTPStrm, constants.AlistStrm: // HTTPStrm、AlistStrm 设置支持直链播放，除允许转码的客户端和编码外强制关闭转码
+			source := &playbackInfoResponse.MediaSources[index]
+			// 上游转码时读取的是其数据库中的 Strm 文件内容，只有内容为上游可以访问的 HTTP 链接（包括 MediaWarp 的 Strm 下载链接接口）时才能转码
+			if strmFileType == constants.HTTPStrm && source.TranscodingURL != nil && source.Path != nil && !isRclonePath(*source.Path) &&
+				allowTranscode(userAgent, videoCodec(source.MediaStreams)) {
+				logging.Info("允许转码：", mediaSourceID, "，转码链接：", *source.TranscodingURL)
+				continue
+			}
+
+			// 强制关闭转码
 			*playbackInfoResponse.MediaSources[index].SupportsDirectPlay = true
 			*playbackInfoResponse.MediaSources[index].SupportsDirectStream = true
 			playbackInfoResponse.MediaSources[index].TranscodingURL = nil
@@ -240,14 +304,36 @@ func (embyServerHandler *EmbyServerHandler) ModifyPlaybackInfo(rw *http.Response
 		}
 	}
 
-	body, err = json.Marshal(playbackInfoResponse)
-	if err != nil {
-		logging.Warning("序列化 emby.PlaybackInfoResponse Json 错误：", err)
-		return err
-	}
-
 	rw.Header.Set("Content-Type", "application/json") // 更新 Content-Type 头
-	return updateBody(rw, body)
+	return json.Marshal(playbackInfoResponse)
+}
+
+// 按 MediaSource.FailingPolicy 排序或隐藏远程存储熔断的媒体源
+//
+// /Items/:itemId/PlaybackInfo
+func (embyServerHandler *EmbyServerHandler) SortPlaybackInfo(rw *http.Response, body []byte) ([]byte, error) {
+	if policy := config.MediaSource().FailingPolicy; policy != constants.SourcePolicySort && policy != constants.SourcePolicyHide {
+		return body, nil
+	}
+	return rewriteJSON(body, func(playbackInfoResponse *emby.PlaybackInfoResponse) {
+		playbackInfoResponse.MediaSources = applySourcePolicy(playbackInfoResponse.MediaSources, func(source emby.MediaSourceInfo) string {
+			if source.Path == nil {
+				return ""
+			}
+			return *source.Path
+		})
+	})
+}
+
+// 为媒体源添加双语虚拟字幕流
+//
+// /Items/:itemId/PlaybackInfo
+func (embyServerHandler *EmbyServerHandler) AddBilingualSubtitles(rw *http.Response, body []byte) ([]byte, error) {
+	return rewriteJSON(body, func(playbackInfoResponse *emby.PlaybackInfoResponse) {
+		for index := range playbackInfoResponse.MediaSources {
+			addBilingualStream(&playbackInfoResponse.MediaSources[index], rw.Request)
+		}
+	})
 }
 
 // 视频流处理器
@@ -273,6 +359,10 @@ func (embyServerHandler *EmbyServerHandler) VideosHandler(ctx *gin.Context) {
 		return
 	}
 
+	if matches := constants.EmbyRegexp.Others.VideoItemIDReg.FindStringSubmatch(orginalPath); len(matches) == 2 { // 规划预加载之后的剧集
+		go embyServerHandler.planPreload(matches[1], getDeviceID(ctx.Request), ctx.Request.Header.Get("User-Agent"))
+	}
+
 	// EmbyServer <= 4.8 ====> mediaSourceID = 343121
 	// EmbyServer >= 4.9 ====> mediaSourceID = mediasource_31
 	mediaSourceID := ctx.Query("mediasourceid")
@@ -283,19 +373,11 @@ func (embyServerHandler *EmbyServerHandler) VideosHandler(ctx *gin.Context) {
 	var item emby.BaseItemDto
 	var err error
 
-	if cachedItem, found := embyServerHandler.cache.GetItemInfo(cleanMediaSourceID); found {
-		logging.Info("VideosHandler - 媒体项信息缓存命中：", cleanMediaSourceID)
-		itemResponse = cachedItem.EmbyItem
-	} else {
-		logging.Info("VideosHandler - 媒体项信息缓存未命中，从上游获取：", cleanMediaSourceID)
-		itemResponse, err = embyServerHandler.server.ItemsServiceQueryItem(cleanMediaSourceID, 1, "Path,MediaSources")
-		if err != nil {
-			logging.Warning("请求 ItemsServiceQueryItem 失败：", err)
-			embyServerHandler.ReverseProxy(ctx.Writer, ctx.Request)
-			return
-		}
-		// 缓存结果（30分钟TTL）
-		embyServerHandler.cache.SetItemInfo(cleanMediaSourceID, itemResponse, 30*time.Minute)
+	itemResponse, err = embyServerHandler.queryItem(cleanMediaSourceID)
+	if err != nil {
+		logging.Warning("请求 ItemsServiceQueryItem 失败：", err)
+		embyServerHandler.ReverseProxy(ctx.Writer, ctx.Request)
+		return
 	}
 
 	item = itemResponse.Items[0]
@@ -335,75 +417,38 @@ func (embyServerHandler *EmbyServerHandler) VideosHandler(ctx *gin.Context) {
 					path := *mediasource.Path
 					logging.Debug("HTTPStrm 路径:", path)
 
-					// 检查是否是 rclone 格式 (如 "115://xxx")
-					if strings.Contains(path, "://") && !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
-						logging.Info("检测到 rclone 格式路径，需要获取真实下载链接:", path)
-
-						// 使用 rclone 获取真实下载链接
-						userAgent := ctx.Request.Header.Get("User-Agent")
-						logging.Info("🔍 获取下载链接 - User-Agent:", userAgent)
-						logging.Info("🔍 播放User-Agent详细信息:")
-						logging.Info("🔍 User-Agent长度:", len(userAgent))
-						logging.Info("🔍 User-Agent内容:", fmt.Sprintf("'%s'", userAgent))
-						logging.Info("🔍 获取下载链接 - 完整请求头:", ctx.Request.Header)
-						cacheKey := path + "|" + userAgent
-						logging.Info("🔑 缓存键:", cacheKey)
-
-						var redirectURL string
-						// 尝试从缓存获取URL
-						if cachedItem, exists := redirectURLCache.Get(cacheKey); exists {
-							cachedURL := cachedItem.URL
-							if strings.HasSuffix(cachedURL, "#PRELOADED") {
-								redirectURL = strings.TrimSuffix(cachedURL, "#PRELOADED")
-								logging.Info("🚀 从预加载缓存获取重定向URL：", redirectURL)
-							} else {
-								redirectURL = cachedURL
-								logging.Info("✅ 从普通缓存获取重定向URL：", redirectURL)
-							}
-						} else {
-							logging.Info("❌ 缓存未命中，需要调用 rclone")
-							// 使用内部 rclone 调用获取下载链接
-							logging.Info("使用内部 rclone 调用获取下载链接:", path)
-							var err error
-							redirectURL, err = rclone.GetDownloadURL(path, userAgent)
-							if err != nil {
-								logging.Warning("内部 rclone 调用失败：", err)
-								embyServerHandler.ReverseProxy(ctx.Writer, ctx.Request)
-								return
-							}
-
-							// 🔍 详细分析下载链接
-							logging.Info("🔗 获取到的下载链接:", redirectURL)
-							if strings.Contains(userAgent, "VidHub") {
-								logging.Info("🎯 VidHub 客户端请求")
-								logging.Info("🔍 链接长度:", len(redirectURL))
-								logging.Info("🔍 链接域名:", extractDomain(redirectURL))
-								logging.Info("🔍 链接参数数量:", countURLParams(redirectURL))
-							}
-
-							// 缓存结果（检查是否覆盖预加载缓存）
-							expireTime := time.Now().Add(defaultCacheTime)
-							if existingItem, exists := redirectURLCache.Get(cacheKey); exists && strings.HasSuffix(existingItem.URL, "#PRELOADED") {
-								logging.Info("⚠️ 跳过缓存设置，保留预加载缓存")
-							} else {
-								redirectURLCache.Set(cacheKey, redirectURL, expireTime)
-								logging.Info("缓存重定向URL，过期时间：", expireTime)
-							}
-						}
-
-						logging.Info("HTTPStrm rclone 重定向至：", redirectURL)
-						ctx.Redirect(http.StatusFound, redirectURL)
-					} else {
-						// 直接的 HTTP URL
-						logging.Info("HTTPStrm 直接重定向至：", path)
-						ctx.Redirect(http.StatusFound, path)
+					// 通过链接对应的解析器获取真实下载链接（如 "115://xxx"），HTTP 链接直接重定向
+					userAgent := ctx.Request.Header.Get("User-Agent")
+					redirectURL, err := getRedirectURL(path, userAgent)
+					if err != nil {
+						logging.Warning("获取下载链接失败：", err)
+						embyServerHandler.ReverseProxy(ctx.Writer, ctx.Request)
+						return
 					}
+
+					logging.Info("HTTPStrm 重定向至：", redirectURL)
+					serveStream(ctx, redirectURL, func() (string, error) { return refreshRedirectURL(path, userAgent) })
 				} else {
 					logging.Warning("HTTPStrm 媒体源路径为空")
 					embyServerHandler.ReverseProxy(ctx.Writer, ctx.Request)
 				}
 				return
 
+			case constants.AlistStrm: // AlistStrm 的媒体源路径为 Alist 上的路径
+				alistAddr, _ := opt.(string)
+				alistPath := *mediasource.Path
+				redirectURL, err := getAlistRedirectURL(embyServerHandler.cache, alistAddr, alistPath)
+				if err != nil {
+					logging.Warning("获取 AlistStrm 下载链接失败：", err)
+					embyServerHandler.ReverseProxy(ctx.Writer, ctx.Request)
+					return
+				}
+				logging.Info("AlistStrm 重定向至：", redirectURL)
+				serveStream(ctx, redirectURL, func() (string, error) {
+					return refreshAlistRedirectURL(embyServerHandler.cache, alistAddr, alistPath)
+				})
+				return
+
 			case constants.UnknownStrm:
 				embyServerHandler.ReverseProxy(ctx.Writer, ctx.Request)
 				return
@@ -414,102 +459,126 @@ func (embyServerHandler *EmbyServerHandler) VideosHandler(ctx *gin.Context) {
 
 // 修改字幕
 //
-// 将 SRT 字幕转 ASS
-func (embyServerHandler *EmbyServerHandler) ModifySubtitles(rw *http.Response) error {
-	defer rw.Body.Close()
-	subtitile, err := readBody(rw) // 读取字幕文件
-	if err != nil {
-		logging.Warning("读取原始字幕 Body 出错：", err)
-		return err
+// 将 SRT 字幕转 ASS，按请求的用户和客户端进行繁简转换
+func (embyServerHandler *EmbyServerHandler) ModifySubtitles(rw *http.Response, body []byte) ([]byte, error) {
+	return modifySubtitles(rw, body, embyServerHandler.chineseConvertMode(rw.Request)), nil
+}
+
+// 获取请求的用户和客户端对应的繁简转换模式
+//
+// 会话用户按设备缓存 sessionUserCacheTime，避免每个字幕请求都获取会话列表
+func (embyServerHandler *EmbyServerHandler) chineseConvertMode(req *http.Request) constants.ChineseConvertMode {
+	return chineseConvertMode(req, func() []string {
+		deviceID := getDeviceID(req)
+		if users, ok := embyServerHandler.sessions.get(deviceID); ok {
+			return users
+		}
+		session, ok := embyServerHandler.getSession(deviceID)
+		if !ok {
+			return nil
+		}
+		users := []string{*session.UserID}
+		if session.UserName != nil {
+			users = append(users, *session.UserName)
+		}
+		embyServerHandler.sessions.set(deviceID, users)
+		return users
+	})
+}
+
+const sessionUserCacheTime = time.Minute // 会话用户缓存时间
+
+// 会话用户缓存
+type sessionUserCache struct {
+	mutex   sync.Mutex
+	entries map[string]sessionUsers // 设备 ID -> 会话用户
+}
+
+type sessionUsers struct {
+	users    []string  // 用户 ID 和名称
+	expireAt time.Time // 过期时间
+}
+
+// 获取设备的会话用户
+func (c *sessionUserCache) get(deviceID string) ([]string, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	entry, ok := c.entries[deviceID]
+	if !ok || time.Now().After(entry.expireAt) {
+		return nil, false
 	}
+	return entry.users, true
+}
 
-	if utils.IsSRT(subtitile) { // 判断是否为 SRT 格式
-		logging.Info("字幕文件为 SRT 格式")
-		if config.Subtitle.SRT2ASS {
-			logging.Info("已将 SRT 字幕已转为 ASS 格式")
-			assSubtitle := utils.SRT2ASS(subtitile, config.Subtitle.ASSStyle)
-			return updateBody(rw, assSubtitle)
+// 缓存设备的会话用户，同时清理过期的缓存
+func (c *sessionUserCache) set(deviceID string, users []string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	now := time.Now()
+	for key, entry := range c.entries {
+		if now.After(entry.expireAt) {
+			delete(c.entries, key)
 		}
 	}
-	return nil
+	c.entries[deviceID] = sessionUsers{users: users, expireAt: now.Add(sessionUserCacheTime)}
 }
 
 // 修改 basehtmlplayer.js
 //
 // 用于修改播放器 JS，实现跨域播放 Strm 文件（302 重定向）
-func (embyServerHandler *EmbyServerHandler) ModifyBaseHtmlPlayer(rw *http.Response) error {
-	defer rw.Body.Close()
-	body, err := readBody(rw)
-	if err != nil {
-		return err
-	}
-
-	body = bytes.ReplaceAll(body, []byte(`mediaSource.IsRemote&&"DirectPlay"===playMethod?null:"anonymous"`), []byte("null")) // 修改响应体
-	return updateBody(rw, body)
-
+func (embyServerHandler *EmbyServerHandler) ModifyBaseHtmlPlayer(rw *http.Response, body []byte) ([]byte, error) {
+	return bytes.ReplaceAll(body, []byte(`mediaSource.IsRemote&&"DirectPlay"===playMethod?null:"anonymous"`), []byte("null")), nil // 修改响应体
 }
 
 // 修改首页函数
-func (embyServerHandler *EmbyServerHandler) ModifyIndex(rw *http.Response) error {
+func (embyServerHandler *EmbyServerHandler) ModifyIndex(rw *http.Response, body []byte) ([]byte, error) {
 	logging.Info("ModifyIndex 开始处理")
 	var (
 		htmlFilePath string = path.Join(config.CostomDir(), "index.html")
-		htmlContent  []byte
+		htmlContent  []byte = body
 		addHEAD      []byte
 		err          error
 	)
 
-	defer rw.Body.Close()  // 无论哪种情况，最终都要确保原 Body 被关闭，避免内存泄漏
-	if !config.Web.Index { // 从上游获取响应体
-		logging.Info("ModifyIndex 从上游获取响应体")
-		if htmlContent, err = readBody(rw); err != nil {
-			logging.Error("ModifyIndex readBody 失败：", err)
-			return err
-		}
-		logging.Info("ModifyIndex readBody 成功，内容长度：", len(htmlContent))
-	} else { // 从本地文件读取index.html
+	if config.Web().Index { // 从本地文件读取index.html
 		logging.Info("ModifyIndex 从本地文件读取 index.html")
 		if htmlContent, err = os.ReadFile(htmlFilePath); err != nil {
-			logging.Warning("读取文件内容出错，错误信息：", err)
-			return err
+			return nil, fmt.Errorf("读取文件内容出错：%w", err)
 		}
 	}
 
-	if config.Web.Head != "" { // 用户自定义HEAD
-		addHEAD = append(addHEAD, []byte(config.Web.Head+"\n")...)
+	if config.Web().Head != "" { // 用户自定义HEAD
+		addHEAD = append(addHEAD, []byte(config.Web().Head+"\n")...)
 	}
-	if config.Web.ExternalPlayerUrl { // 外部播放器
+	if config.Web().ExternalPlayerUrl { // 外部播放器
 		addHEAD = append(addHEAD, []byte(`<script src="/MediaWarp/static/embyExternalUrl/embyWebAddExternalUrl/embyLaunchPotplayer.js"></script>`+"\n")...)
 	}
-	if config.Web.Crx { // crx 美化
+	if config.Web().Crx { // crx 美化
 		addHEAD = append(addHEAD, []byte(`<link rel="stylesheet" id="theme-css" href="/MediaWarp/static/emby-crx/static/css/style.css" type="text/css" media="all" />
     <script src="/MediaWarp/static/emby-crx/static/js/common-utils.js"></script>
     <script src="/MediaWarp/static/emby-crx/static/js/jquery-3.6.0.min.js"></script>
     <script src="/MediaWarp/static/emby-crx/static/js/md5.min.js"></script>
     <script src="/MediaWarp/static/emby-crx/content/main.js"></script>`+"\n")...)
 	}
-	if config.Web.ActorPlus { // 过滤没有头像的演员和制作人员
+	if config.Web().ActorPlus { // 过滤没有头像的演员和制作人员
 		addHEAD = append(addHEAD, []byte(`<script src="/MediaWarp/static/emby-web-mod/actorPlus/actorPlus.js"></script>`+"\n")...)
 	}
-	if config.Web.FanartShow { // 显示同人图（fanart图）
+	if config.Web().FanartShow { // 显示同人图（fanart图）
 		addHEAD = append(addHEAD, []byte(`<script src="/MediaWarp/static/emby-web-mod/fanart_show/fanart_show.js"></script>`+"\n")...)
 	}
-	if config.Web.Danmaku { // 弹幕
+	if config.Web().Danmaku { // 弹幕
 		addHEAD = append(addHEAD, []byte(`<script src="/MediaWarp/static/dd-danmaku/ede.js" defer></script>`+"\n")...)
 	}
-	if config.Web.VideoTogether { // VideoTogether
+	if config.Web().SubtitleOffset && config.Subtitle().Enable { // 字幕时间轴调整
+		addHEAD = append(addHEAD, []byte(`<script src="/MediaWarp/static/subtitle-offset/subtitleOffset.js" defer></script>`+"\n")...)
+	}
+	if config.Web().VideoTogether { // VideoTogether
 		addHEAD = append(addHEAD, []byte(`<script src="https://2gether.video/release/extension.website.user.js"></script>`+"\n")...)
 	}
 	logging.Info("ModifyIndex 开始替换 HTML 内容")
 	htmlContent = bytes.Replace(htmlContent, []byte("</head>"), append(addHEAD, []byte("</head>")...), 1) // 将添加HEAD
-	logging.Info("ModifyIndex HTML 替换完成，开始 updateBody")
-	err = updateBody(rw, htmlContent)
-	if err != nil {
-		logging.Error("ModifyIndex updateBody 失败：", err)
-		return err
-	}
 	logging.Info("ModifyIndex 处理完成")
-	return nil
+	return htmlContent, nil
 }
 
 // ItemDetailHandler 处理详情页请求并预加载下载链接
@@ -521,10 +590,6 @@ func (embyServerHandler *EmbyServerHandler) ItemDetailHandler(ctx *gin.Context)
 
 	// 异步预加载下载链接（使用预加载管理器）
 	go func() {
-		// 添加超时控制，防止 goroutine 泄漏
-		timeoutCtx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
-		defer cancel()
-
 		// 从URL提取itemId
 		path := ctx.Request.URL.Path
 		parts := strings.Split(path, "/")
@@ -543,7 +608,7 @@ func (embyServerHandler *EmbyServerHandler) ItemDetailHandler(ctx *gin.Context)
 		logging.Info("🚀 详情页预加载开始，ItemId:", itemId)
 
 		// 获取媒体项信息
-		itemResponse, err := embyServerHandler.server.ItemsServiceQueryItem(itemId, 1, "Path,MediaSources")
+		itemResponse, err := embyServerHandler.queryItem(itemId)
 		if err != nil {
 			logging.Warning("预加载获取媒体项信息失败：", err)
 			return
@@ -558,23 +623,6 @@ func (embyServerHandler *EmbyServerHandler) ItemDetailHandler(ctx *gin.Context)
 			return
 		}
 
-		// 获取媒体源路径
-		if len(item.MediaSources) == 0 {
-			return
-		}
-
-		mediaSource := item.MediaSources[0]
-		if mediaSource.Path == nil {
-			return
-		}
-
-		mediaSourcePath := *mediaSource.Path
-		if !strings.HasPrefix(mediaSourcePath, "115://") && !strings.HasPrefix(mediaSourcePath, "123://") {
-			return
-		}
-
-		logging.Info("🎯 发现可预加载的视频:", mediaSourcePath)
-
 		// 获取当前请求的真实User-Agent
 		userAgent := ctx.Request.Header.Get("User-Agent")
 		if userAgent == "" {
@@ -582,61 +630,10 @@ func (embyServerHandler *EmbyServerHandler) ItemDetailHandler(ctx *gin.Context)
 			return
 		}
 
-		logging.Info("🔍 预加载User-Agent详细信息:")
-		logging.Info("🔍 User-Agent长度:", len(userAgent))
-		logging.Info("🔍 User-Agent内容:", fmt.Sprintf("'%s'", userAgent))
-
-		cacheKey := mediaSourcePath + "|" + userAgent
-
-		// 检查是否已缓存
-		if _, exists := redirectURLCache.Get(cacheKey); exists {
-			logging.Info("✅ 预加载跳过，已缓存:", userAgent)
-			return
-		}
-
-		// 使用预加载管理器检查是否可以预加载
-		if !preloadManager.CanPreload(cacheKey) {
-			logging.Info("⏸️ 预加载跳过，管理器拒绝:", cacheKey)
-			return
-		}
-
-		// 尝试开始预加载（获取并发控制信号量）
-		if !preloadManager.StartPreload(cacheKey) {
-			logging.Info("⏸️ 预加载跳过，并发限制:", cacheKey)
-			return
-		}
-
-		// 确保在函数结束时释放资源
-		defer func() {
-			if r := recover(); r != nil {
-				logging.Error("预加载panic:", r)
-				preloadManager.FinishPreload(cacheKey, fmt.Errorf("panic: %v", r))
-			}
-		}()
-
-		// 添加随机延迟，避免瞬间大量请求
-		delay := time.Duration(randSource.Intn(500)+100) * time.Millisecond
-		time.Sleep(delay)
-		logging.Info("🕐 预加载延迟:", delay, "cacheKey:", cacheKey)
-
-		// 预加载下载链接（使用超时上下文）
-		logging.Info("🔄 预加载下载链接，User-Agent:", userAgent)
-		redirectURL, err := rclone.GlobalClient.GetDownloadURL(timeoutCtx, mediaSourcePath, userAgent)
-
-		// 完成预加载（释放信号量和更新错误状态）
-		preloadManager.FinishPreload(cacheKey, err)
-
-		if err != nil {
-			logging.Warning("预加载失败:", err)
-			return
+		// 预加载全部媒体源（多版本媒体项的每个版本单独缓存）
+		if preloaded := preloadMediaSources(item.MediaSources, userAgent); preloaded > 0 {
+			logging.Info("🎉 详情页预加载完成，ItemId:", itemId, "，预加载媒体源：", preloaded)
 		}
-
-		// 缓存结果（标记为预加载）
-		expireTime := time.Now().Add(defaultCacheTime)
-		redirectURLCache.Set(cacheKey, redirectURL+"#PRELOADED", expireTime)
-		logging.Info("✅ 预加载完成并缓存:", userAgent)
-
-		logging.Info("🎉 详情页预加载完成，ItemId:", itemId)
 	}()
 }
 
@@ -663,9 +660,6 @@ type PreloadManager struct {
 	semaphore    chan struct{}   // 并发控制信号量
 	processing   map[string]bool // 正在处理的请求映射
 	processingMu sync.RWMutex    // 保护processing映射的锁
-	lastError    time.Time       // 最后一次错误时间
-	errorCount   int             // 连续错误计数
-	errorMu      sync.RWMutex    // 保护错误状态的锁
 }
 
 // NewPreloadManager 创建新的预加载管理器
@@ -677,8 +671,9 @@ func NewPreloadManager(maxConcurrent int) *PreloadManager {
 }
 
 // CanPreload 检查是否可以进行预加载
-func (pm *PreloadManager) CanPreload(cacheKey string) bool {
-	// 统一锁顺序：先检查 processing，后检查 error，避免死锁
+//
+// 远程存储熔断或没有可用的限流令牌时跳过预加载，把配额留给播放请求
+func (pm *PreloadManager) CanPreload(cacheKey string, remote string) bool {
 	pm.processingMu.RLock()
 	processing := pm.processing[cacheKey]
 	pm.processingMu.RUnlock()
@@ -687,12 +682,8 @@ func (pm *PreloadManager) CanPreload(cacheKey string) bool {
 		return false
 	}
 
-	pm.errorMu.RLock()
-	defer pm.errorMu.RUnlock()
-
-	// 如果最近有错误且错误次数过多，暂停预加载
-	if pm.errorCount >= 3 && time.Since(pm.lastError) < 5*time.Minute {
-		logging.Warning("预加载暂停中，错误次数过多:", pm.errorCount)
+	if !limiter.Get(remote).Available() {
+		logging.Warning("预加载暂停中，远程存储熔断或限流：", remote)
 		return false
 	}
 
@@ -724,29 +715,20 @@ func (pm *PreloadManager) FinishPreload(cacheKey string, err error) {
 	delete(pm.processing, cacheKey)
 	pm.processingMu.Unlock()
 
-	// 更新错误状态
-	pm.errorMu.Lock()
-	if err != nil {
-		pm.lastError = time.Now()
-		pm.errorCount++
-		logging.Warning("预加载失败，错误计数:", pm.errorCount, "错误:", err)
-	} else {
-		pm.errorCount = 0 // 成功时重置错误计数
+	if err != nil { // 失败次数由远程存储的熔断器统计
+		logging.Warning("预加载失败:", cacheKey, "错误:", err)
 	}
-	pm.errorMu.Unlock()
 }
 
 // 全局安全缓存实例和预加载管理器
 var (
 	redirectURLCache *cache.SafeCache
-	defaultCacheTime = 2 * time.Hour // 默认缓存时间2小时，可通过配置修改
+	defaultCacheTime = 2 * time.Hour // 默认（最长）缓存时间2小时，链接带有过期时间时以过期时间为准
 	preloadManager   *PreloadManager
-	randSource       *rand.Rand // 本地随机数生成器
 )
 
 // 初始化缓存和预加载管理器
 func init() {
-	redirectURLCache = cache.NewSafeCache(5 * time.Minute)       // 每5分钟清理一次过期项
-	preloadManager = NewPreloadManager(2)                        // 最多2个并发预加载
-	randSource = rand.New(rand.NewSource(time.Now().UnixNano())) // 初始化本地随机数生成器
+	redirectURLCache = cache.NewSafeCache(5 * time.Minute) // 每5分钟清理一次过期项
+	preloadManager = NewPreloadManager(2)                  // 最多2个并发预加载
 }
@@ -0,0 +1,18 @@
+package handler
+
+import "time"
+
+// 供外部测试包使用的内部实现
+
+const LinkExpiryMargin = linkExpiryMargin
+
+// 刷新即将过期的正在观看的链接
+func RefreshDueLinks() {
+	expiryTracker.refreshDue()
+}
+
+// 下载链接缓存的过期时间
+func CachedLinkExpiry(path string, userAgent string) (time.Time, bool) {
+	item, ok := redirectURLCache.Get(redirectCacheKey(path, userAgent))
+	return item.ExpireTime, ok
+}
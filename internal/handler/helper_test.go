@@ -0,0 +1,86 @@
+package handler_test
+
+import (
+	"MediaWarp/constants"
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/handler"
+	"MediaWarp/internal/logging"
+	"MediaWarp/internal/router"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试请求默认使用的 User-Agent（Go 默认的 User-Agent 会被防爬虫中间件拦截）
+const testUserAgent = "Infuse/7.7"
+
+// 为未设置 User-Agent 的请求设置默认值
+type userAgentTransport struct{}
+
+func (userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", testUserAgent)
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// 启动上游媒体服务器桩和 MediaWarp 测试服务
+//
+// 请求经 router.InitRouter 的路由和中间件（以及 main.go 中注册的缓存统计和 User-Agent 路由）处理，
+// 未匹配的请求由 router.RegexpRouterHandler 选择上游；
+// update 用于修改其余配置，测试结束后恢复配置并关闭服务。返回的客户端不跟随重定向
+func startMediaWarp(t *testing.T, serverType constants.MediaServerType, upstream http.HandlerFunc, update func(*config.Snapshot)) (*httptest.Server, *http.Client) {
+	t.Helper()
+	logging.Init()
+	gin.SetMode(gin.TestMode)
+
+	upstreamStub := httptest.NewServer(upstream)
+	t.Cleanup(upstreamStub.Close)
+	t.Cleanup(config.Update(func(s *config.Snapshot) {
+		s.MediaServers = []config.MediaServerSetting{{Name: string(serverType), Type: serverType, ADDR: upstreamStub.URL}}
+		if update != nil {
+			update(s)
+		}
+		s.MediaServer = s.MediaServers[0]
+	}))
+	if err := handler.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	engine := router.InitRouter()
+	handler.RegisterCacheStatsRoutes(engine)
+	handler.RegisterUserAgentRoutes(engine)
+	mediaWarp := httptest.NewServer(engine)
+	t.Cleanup(mediaWarp.Close)
+	client := &http.Client{
+		Transport:     userAgentTransport{},
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	return mediaWarp, client
+}
+
+// Plex 上游桩，任意请求均返回同一个媒体条目，条目、媒体和分段的 ID 均为 id
+func plexItemStub(id string, file string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"MediaContainer":{"size":1,"Metadata":[{"ratingKey":"` + id + `","Media":[{"id":` + id + `,"Part":[{"id":` + id + `,"file":` + jsonString(file) + `}]}]}]}}`))
+	}
+}
+
+// Emby 上游桩，媒体项信息和播放信息均返回同一个媒体项，媒体项和媒体源的 ID 均为 id
+//
+// itemPath 为 Strm 文件路径，sourcePath 为 Strm 内容
+func embyItemStub(id string, itemPath string, sourcePath string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/PlaybackInfo") {
+			w.Write([]byte(`{"MediaSources":[{"Id":"` + id + `","ItemId":"` + id + `","Path":` + jsonString(sourcePath) +
+				`,"Protocol":"Http","SupportsDirectPlay":false,"SupportsDirectStream":false,"TranscodingUrl":"/videos/` + id + `/master.m3u8"}]}`))
+			return
+		}
+		w.Write([]byte(`{"Items":[{"Id":"` + id + `","Path":` + jsonString(itemPath) + `,"MediaSources":[{"Id":"` + id + `","Path":` + jsonString(sourcePath) + `,"Protocol":"Http"}]}],"TotalRecordCount":1}`))
+	}
+}
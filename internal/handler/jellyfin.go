@@ -0,0 +1,339 @@
+package handler
+
+import (
+	"MediaWarp/constants"
+	"MediaWarp/internal/cache"
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/logging"
+	"MediaWarp/internal/service/jellyfin"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Jellyfin服务器处理器
+type JellyfinServerHandler struct {
+	server      *jellyfin.JellyfinServer // Jellyfin 服务器
+	routerRules []RegexpRouteRule        // 正则路由规则
+	proxy       *httputil.ReverseProxy   // 反向代理
+	cache       *cache.PlaybackInfoCache // 播放信息缓存
+
+	cacheNamespace string // 媒体项缓存命名空间（多个上游时避免 ItemId 冲突）
+}
+
+// 初始化
+func NewJellyfinServerHandler(addr string, apiKey string) (*JellyfinServerHandler, error) {
+	var jellyfinServerHandler = JellyfinServerHandler{}
+	jellyfinServerHandler.server = jellyfin.New(addr, apiKey)
+	jellyfinServerHandler.cache = cache.GlobalPlaybackCache // 使用全局缓存实例
+	target, err := url.Parse(jellyfinServerHandler.server.GetEndpoint())
+	if err != nil {
+		return nil, err
+	}
+	jellyfinServerHandler.proxy = httputil.NewSingleHostReverseProxy(target)
+
+	{ // 初始化路由规则
+		jellyfinServerHandler.routerRules = []RegexpRouteRule{
+			{
+				Regexp:  constants.JellyfinRegexp.Router.VideosHandler,
+				Handler: jellyfinServerHandler.VideosHandler,
+			},
+		}
+
+		rewriters, err := newRewriterChain(jellyfinServerHandler.rewriters())
+		if err != nil {
+			return nil, err
+		}
+		jellyfinServerHandler.routerRules = append(jellyfinServerHandler.routerRules, rewriters.routeRules(jellyfinServerHandler.proxy.Director)...)
+	}
+	return &jellyfinServerHandler, nil
+}
+
+// 内置响应改写器
+func (jellyfinServerHandler *JellyfinServerHandler) rewriters() []ResponseRewriter {
+	return []ResponseRewriter{
+		{
+			Name:        "MediaSourcePolicy",
+			Regexp:      constants.JellyfinRegexp.Router.ModifyPlaybackInfo,
+			ContentType: "application/json",
+			Priority:    100,
+			Enable:      true,
+			Rewrite:     jellyfinServerHandler.SortPlaybackInfo,
+		},
+		{
+			Name:        "StrmPlaybackInfo",
+			Regexp:      constants.JellyfinRegexp.Router.ModifyPlaybackInfo,
+			ContentType: "application/json",
+			Priority:    200,
+			Enable:      true,
+			Rewrite:     jellyfinServerHandler.ModifyPlaybackInfo,
+		},
+		{
+			Name:        "WebIndex",
+			Regexp:      constants.JellyfinRegexp.Router.ModifyIndex,
+			ContentType: "text/html",
+			Priority:    100,
+			Enable:      config.Web().Enable && (config.Web().Index || config.Web().Head != "" || config.Web().Danmaku || config.Web().VideoTogether || config.Web().SubtitleOffset),
+			Rewrite:     jellyfinServerHandler.ModifyIndex,
+		},
+		{
+			Name:     "Subtitles",
+			Regexp:   constants.JellyfinRegexp.Router.ModifySubtitles,
+			Priority: 100,
+			Enable:   config.Subtitle().Enable,
+			Rewrite:  jellyfinServerHandler.ModifySubtitles,
+		},
+	}
+}
+
+// 转发请求至上游服务器
+func (jellyfinServerHandler *JellyfinServerHandler) ReverseProxy(rw http.ResponseWriter, req *http.Request) {
+	jellyfinServerHandler.proxy.ServeHTTP(rw, req)
+}
+
+// 正则路由表
+func (jellyfinServerHandler *JellyfinServerHandler) GetRegexpRouteRules() []RegexpRouteRule {
+	return jellyfinServerHandler.routerRules
+}
+
+// 获取媒体项信息
+//
+// 优先从缓存中获取，未命中时请求上游并缓存结果，相同媒体项的并发请求只会请求一次上游
+func (jellyfinServerHandler *JellyfinServerHandler) queryItem(mediaSourceID string) (*jellyfin.BaseItemDto, error) {
+	var itemResponse *jellyfin.Response
+	cacheKey := namespacedKey(jellyfinServerHandler.cacheNamespace, mediaSourceID)
+	if cachedItem, found := jellyfinServerHandler.cache.GetJellyfinItemInfo(cacheKey); found {
+		logging.Info("Jellyfin 媒体项信息缓存命中：", mediaSourceID)
+		itemResponse = cachedItem.JellyfinItem
+	} else {
+		logging.Info("Jellyfin 媒体项信息缓存未命中，从上游获取：", mediaSourceID)
+		value, err := itemGroup.Do("jellyfin|"+cacheKey, func() (any, error) {
+			result, err := jellyfinServerHandler.server.ItemsServiceQueryItem(mediaSourceID, 1, "Path,MediaSources")
+			if err != nil {
+				return nil, err
+			}
+			// 缓存结果（30分钟TTL）
+			jellyfinServerHandler.cache.SetJellyfinItemInfo(cacheKey, result, 30*time.Minute)
+			return result, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		itemResponse = value.(*jellyfin.Response)
+	}
+
+	if len(itemResponse.Items) == 0 || itemResponse.Items[0].Path == nil {
+		return nil, fmt.Errorf("未找到媒体项：%s", mediaSourceID)
+	}
+	return &itemResponse.Items[0], nil
+}
+
+// 获取 Strm 文件类型
+func (jellyfinServerHandler *JellyfinServerHandler) getStrmType(filePath string) (constants.StrmFileType, any) {
+	if cachedStrm, found := jellyfinServerHandler.cache.GetStrmType(filePath); found {
+		logging.Info("Strm类型缓存命中：", filePath)
+		return cachedStrm.Type, cachedStrm.Option
+	}
+	logging.Info("Strm类型缓存未命中，重新识别：", filePath)
+	strmFileType, opt, _ := recgonizeStrmFileType(filePath)
+	// 缓存结果（1小时TTL）
+	jellyfinServerHandler.cache.SetStrmType(filePath, strmFileType, opt, 1*time.Hour)
+	return strmFileType, opt
+}
+
+// 修改播放信息请求
+//
+// /Items/:itemId/PlaybackInfo
+// 强制将 HTTPStrm 设置为支持直链播放并且禁止转码
+func (jellyfinServerHandler *JellyfinServerHandler) ModifyPlaybackInfo(rw *http.Response, body []byte) ([]byte, error) {
+	logging.Debug("=======  Jellyfin ModifyPlaybackInfo ======= ")
+
+	var playbackInfoResponse jellyfin.PlaybackInfoResponse
+	if err := json.Unmarshal(body, &playbackInfoResponse); err != nil {
+		return nil, fmt.Errorf("解析 jellyfin.PlaybackInfoResponse Json 错误：%w", err)
+	}
+
+	for index, mediasource := range playbackInfoResponse.MediaSources {
+		if mediasource.ID == nil {
+			continue
+		}
+		item, err := jellyfinServerHandler.queryItem(*mediasource.ID)
+		if err != nil {
+			logging.Warning("请求 ItemsServiceQueryItem 失败：", err)
+			continue
+		}
+		if !strings.HasSuffix(strings.ToLower(*item.Path), ".strm") {
+			continue
+		}
+
+		strmFileType, _ := jellyfinServerHandler.getStrmType(*item.Path)
+		switch strmFileType {
+		case constants.HTTPStrm, constants.AlistStrm: // HTTPStrm、AlistStrm 设置支持直链播放并且强制关闭转码
+			source := &playbackInfoResponse.MediaSources[index]
+			directPlay, directStream := true, true
+			source.SupportsDirectPlay = &directPlay
+			source.SupportsDirectStream = &directStream
+			source.TranscodingURL = nil
+			source.TranscodingSubProtocol = nil
+			source.TranscodingContainer = nil
+			if source.Path != nil && (isRclonePath(*source.Path) || strmFileType == constants.AlistStrm) {
+				// rclone 格式路径和 Alist 路径客户端无法直接访问，需要让客户端通过 /Videos/:itemId/stream 请求 MediaWarp
+				protocol, isRemote := jellyfin.File, false
+				source.Protocol = &protocol
+				source.IsRemote = &isRemote
+			}
+			if mediasource.Name != nil {
+				logging.Info(*mediasource.Name, " 强制禁止转码")
+			}
+		}
+	}
+	rw.Header.Set("Content-Type", "application/json") // 更新 Content-Type 头
+	return json.Marshal(playbackInfoResponse)
+}
+
+// 按 MediaSource.FailingPolicy 排序或隐藏远程存储熔断的媒体源
+//
+// /Items/:itemId/PlaybackInfo
+func (jellyfinServerHandler *JellyfinServerHandler) SortPlaybackInfo(rw *http.Response, body []byte) ([]byte, error) {
+	if policy := config.MediaSource().FailingPolicy; policy != constants.SourcePolicySort && policy != constants.SourcePolicyHide {
+		return body, nil
+	}
+	return rewriteJSON(body, func(playbackInfoResponse *jellyfin.PlaybackInfoResponse) {
+		playbackInfoResponse.MediaSources = applySourcePolicy(playbackInfoResponse.MediaSources, func(source jellyfin.MediaSourceInfo) string {
+			if source.Path == nil {
+				return ""
+			}
+			return *source.Path
+		})
+	})
+}
+
+// 视频流处理器
+//
+// 支持播放本地视频、重定向 HttpStrm
+func (jellyfinServerHandler *JellyfinServerHandler) VideosHandler(ctx *gin.Context) {
+	logging.Debug("======= Jellyfin VideosHandler ======= ")
+
+	if ctx.Request.Method == http.MethodHead { // 不额外处理 HEAD 请求
+		jellyfinServerHandler.ReverseProxy(ctx.Writer, ctx.Request)
+		logging.Debug("VideosHandler 不处理 HEAD 请求，转发至上游服务器")
+		return
+	}
+
+	mediaSourceID := ctx.Query("mediasourceid")
+	if mediaSourceID == "" { // 未指定媒体源时使用路径中的 ItemId
+		parts := strings.Split(ctx.Request.URL.Path, "/")
+		if len(parts) > 2 {
+			mediaSourceID = parts[2]
+		}
+	}
+
+	item, err := jellyfinServerHandler.queryItem(mediaSourceID)
+	if err != nil {
+		logging.Warning("请求 ItemsServiceQueryItem 失败：", err)
+		jellyfinServerHandler.ReverseProxy(ctx.Writer, ctx.Request)
+		return
+	}
+
+	if !strings.HasSuffix(strings.ToLower(*item.Path), ".strm") { // 不是 Strm 文件
+		logging.Debug("播放本地视频：" + *item.Path + "，不进行处理")
+		jellyfinServerHandler.ReverseProxy(ctx.Writer, ctx.Request)
+		return
+	}
+
+	strmFileType, opt := jellyfinServerHandler.getStrmType(*item.Path)
+	logging.Debug("请求 strmFileType:", strmFileType)
+	for _, mediasource := range item.MediaSources {
+		if mediasource.ID == nil || !strings.EqualFold(*mediasource.ID, mediaSourceID) {
+			continue
+		}
+		switch strmFileType {
+		case constants.HTTPStrm:
+			if mediasource.Path == nil {
+				logging.Warning("HTTPStrm 媒体源路径为空")
+				jellyfinServerHandler.ReverseProxy(ctx.Writer, ctx.Request)
+				return
+			}
+			path := *mediasource.Path
+			userAgent := ctx.Request.Header.Get("User-Agent")
+			redirectURL, err := getRedirectURL(path, userAgent)
+			if err != nil {
+				logging.Warning("获取下载链接失败：", err)
+				jellyfinServerHandler.ReverseProxy(ctx.Writer, ctx.Request)
+				return
+			}
+			logging.Info("HTTPStrm 重定向至：", redirectURL)
+			serveStream(ctx, redirectURL, func() (string, error) { return refreshRedirectURL(path, userAgent) })
+			return
+		case constants.AlistStrm: // AlistStrm 的媒体源路径为 Alist 上的路径
+			if mediasource.Path == nil {
+				logging.Warning("AlistStrm 媒体源路径为空")
+				jellyfinServerHandler.ReverseProxy(ctx.Writer, ctx.Request)
+				return
+			}
+			alistAddr, _ := opt.(string)
+			alistPath := *mediasource.Path
+			redirectURL, err := getAlistRedirectURL(jellyfinServerHandler.cache, alistAddr, alistPath)
+			if err != nil {
+				logging.Warning("获取 AlistStrm 下载链接失败：", err)
+				jellyfinServerHandler.ReverseProxy(ctx.Writer, ctx.Request)
+				return
+			}
+			logging.Info("AlistStrm 重定向至：", redirectURL)
+			serveStream(ctx, redirectURL, func() (string, error) {
+				return refreshAlistRedirectURL(jellyfinServerHandler.cache, alistAddr, alistPath)
+			})
+			return
+		}
+	}
+	jellyfinServerHandler.ReverseProxy(ctx.Writer, ctx.Request)
+}
+
+// 修改字幕
+//
+// 将 SRT 字幕转 ASS，按请求的客户端进行繁简转换
+func (jellyfinServerHandler *JellyfinServerHandler) ModifySubtitles(rw *http.Response, body []byte) ([]byte, error) {
+	return modifySubtitles(rw, body, chineseConvertMode(rw.Request, nil)), nil
+}
+
+// 修改首页函数
+func (jellyfinServerHandler *JellyfinServerHandler) ModifyIndex(rw *http.Response, body []byte) ([]byte, error) {
+	var (
+		htmlFilePath string = path.Join(config.CostomDir(), "index.html")
+		htmlContent  []byte = body
+		addHEAD      []byte
+		err          error
+	)
+
+	if config.Web().Index { // 从本地文件读取index.html
+		if htmlContent, err = os.ReadFile(htmlFilePath); err != nil {
+			return nil, fmt.Errorf("读取文件内容出错：%w", err)
+		}
+	}
+
+	if config.Web().Head != "" { // 用户自定义HEAD
+		addHEAD = append(addHEAD, []byte(config.Web().Head+"\n")...)
+	}
+	if config.Web().Danmaku { // 弹幕
+		addHEAD = append(addHEAD, []byte(`<script src="/MediaWarp/static/jellyfin-danmaku/ede.js" defer></script>`+"\n")...)
+	}
+	if config.Web().SubtitleOffset && config.Subtitle().Enable { // 字幕时间轴调整
+		addHEAD = append(addHEAD, []byte(`<script src="/MediaWarp/static/subtitle-offset/subtitleOffset.js" defer></script>`+"\n")...)
+	}
+	if config.Web().VideoTogether { // VideoTogether
+		addHEAD = append(addHEAD, []byte(`<script src="https://2gether.video/release/extension.website.user.js"></script>`+"\n")...)
+	}
+	htmlContent = bytes.Replace(htmlContent, []byte("</head>"), append(addHEAD, []byte("</head>")...), 1) // 将添加HEAD
+	return htmlContent, nil
+}
+
+var _ MediaServerHandler = (*JellyfinServerHandler)(nil) // 确保 JellyfinServerHandler 实现 MediaServerHandler 接口
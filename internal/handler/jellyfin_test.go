@@ -0,0 +1,84 @@
+package handler_test
+
+import (
+	"MediaWarp/constants"
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/resolver"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestJellyfinServerHandler(t *testing.T) {
+	resolver.Register("record", &recordResolver{})
+
+	const itemID = "6b1e3a0c9d2f4e5a8b7c6d5e4f3a2b1c"
+	localPath := t.TempDir()
+	strmPath := filepath.Join(localPath, "Movie.strm")
+	if err := os.WriteFile(strmPath, []byte("record://jellyfin.mkv"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	source := `{"Id":"` + itemID + `","Path":"record://jellyfin.mkv","Protocol":"Http","IsRemote":true,"SupportsDirectPlay":false,"SupportsDirectStream":false,` +
+		`"TranscodingUrl":"/videos/` + itemID + `/master.m3u8"}`
+	mediaWarp, client := startMediaWarp(t, constants.JELLYFIN, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/PlaybackInfo"):
+			w.Write([]byte(`{"MediaSources":[` + source + `]}`))
+		case r.URL.Path == "/Items":
+			if r.Header.Get("Authorization") != `MediaBrowser Token="jellyfin-key"` {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"Items":[{"Id":"` + itemID + `","Path":` + jsonString(strmPath) + `,"MediaSources":[` + source + `]}],"TotalRecordCount":1}`))
+		default:
+			w.Write([]byte("jellyfin " + r.URL.Path))
+		}
+	}, func(s *config.Snapshot) {
+		s.MediaServers[0].AUTH = "jellyfin-key"
+		s.MediaSync = config.MediaSyncSetting{{Name: "record", Remote: "record:", LocalPath: localPath}}
+	})
+
+	resp, err := client.Get(mediaWarp.URL + "/Items/" + itemID + "/PlaybackInfo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var playbackInfo struct {
+		MediaSources []struct {
+			Protocol           string
+			IsRemote           bool
+			SupportsDirectPlay bool
+			TranscodingUrl     *string
+		}
+	}
+	err = json.NewDecoder(resp.Body).Decode(&playbackInfo)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(playbackInfo.MediaSources) != 1 {
+		t.Fatalf("媒体源数量错误：%d", len(playbackInfo.MediaSources))
+	}
+	if source := playbackInfo.MediaSources[0]; source.TranscodingUrl != nil || !source.SupportsDirectPlay || source.Protocol != "File" || source.IsRemote {
+		t.Errorf("rclone 链接的媒体源应强制直链播放并通过 MediaWarp 串流：%+v", source)
+	}
+
+	for caseName, path := range map[string]string{
+		"指定媒体源":     "/videos/" + itemID + "/stream?MediaSourceId=" + itemID + "&Static=true",
+		"使用 ItemId": "/videos/" + itemID + "/stream.mkv?Static=true",
+	} {
+		t.Run(caseName, func(t *testing.T) {
+			resp, err := client.Get(mediaWarp.URL + path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if location := resp.Header.Get("Location"); resp.StatusCode != http.StatusFound || location != "https://cdn.example.com/jellyfin.mkv" {
+				t.Errorf("重定向错误：%d %s", resp.StatusCode, location)
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package handler
+
+import (
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/logging"
+	"MediaWarp/internal/resolver"
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	linkExpiryMargin  = 5 * time.Minute  // 缓存时间相对链接过期时间的安全余量
+	linkMinCacheTime  = 30 * time.Second // 最短缓存时间
+	linkRefreshAhead  = 2 * time.Minute  // 缓存过期前多久开始后台刷新
+	linkActiveWindow  = time.Hour        // 最近访问时间在该时间内的链接视为正在观看
+	linkRefreshPeriod = 30 * time.Second // 后台刷新检查周期
+	unknownRemote     = "unknown"        // 无法识别协议的链接的统计名称
+)
+
+// 被跟踪的重定向链接
+type trackedLink struct {
+	path       string
+	userAgent  string
+	remote     string
+	expireAt   time.Time // 缓存过期时间
+	lastAccess time.Time // 客户端最近一次访问时间
+}
+
+// 单个远程存储的链接过期统计
+type RemoteExpiryStats struct {
+	Remote          string  `json:"remote"`
+	Resolved        int64   `json:"resolved"`         // 解析链接次数
+	WithExpiry      int64   `json:"with_expiry"`      // 可以解析出过期时间的链接数
+	AvgLifetimeSec  float64 `json:"avg_lifetime_sec"` // 链接平均有效期
+	MinLifetimeSec  float64 `json:"min_lifetime_sec"` // 链接最短有效期
+	Refreshed       int64   `json:"refreshed"`        // 后台刷新成功次数
+	RefreshFailures int64   `json:"refresh_failures"` // 后台刷新失败次数
+	Tracked         int     `json:"tracked"`          // 正在跟踪的链接数
+}
+
+// 链接过期跟踪器
+//
+// 根据签名链接的过期时间计算缓存时间，并在正在观看的链接过期前后台刷新
+type linkExpiryTracker struct {
+	mutex sync.Mutex
+	links map[string]*trackedLink
+	stats map[string]*RemoteExpiryStats
+	total map[string]time.Duration // 有效期总和，用于计算平均值
+}
+
+var (
+	expiryTracker = newLinkExpiryTracker()
+
+	refresherMutex  sync.Mutex
+	refresherCancel context.CancelFunc // 停止后台刷新，未运行时为 nil
+	refresherDone   chan struct{}      // 后台刷新退出后关闭
+)
+
+func newLinkExpiryTracker() *linkExpiryTracker {
+	return &linkExpiryTracker{
+		links: make(map[string]*trackedLink),
+		stats: make(map[string]*RemoteExpiryStats),
+		total: make(map[string]time.Duration),
+	}
+}
+
+// 获取链接所属的远程存储名称
+func remoteName(path string) string {
+	if remote := resolver.Remote(path); remote != "" {
+		return remote
+	}
+	return unknownRemote
+}
+
+// 计算下载链接的缓存时间
+//
+// 可以解析出过期时间时使用过期时间减去安全余量，且不超过默认缓存时间
+func linkCacheTime(redirectURL string) time.Duration {
+	expiry, ok := resolver.ParseExpiry(redirectURL)
+	if !ok {
+		return defaultCacheTime
+	}
+	cacheTime := time.Until(expiry) - linkExpiryMargin
+	if cacheTime > defaultCacheTime {
+		return defaultCacheTime
+	}
+	if cacheTime < linkMinCacheTime { // 有效期过短时使用剩余有效期的一半
+		cacheTime = max(time.Until(expiry)/2, 0)
+	}
+	return cacheTime
+}
+
+// 记录新解析的链接
+//
+// accessed 为 false 时（如预加载）只有在客户端访问后才会后台刷新
+func (tracker *linkExpiryTracker) record(cacheKey string, path string, userAgent string, redirectURL string, expireAt time.Time, accessed bool) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	remote := remoteName(path)
+	stats, ok := tracker.stats[remote]
+	if !ok {
+		stats = &RemoteExpiryStats{Remote: remote}
+		tracker.stats[remote] = stats
+	}
+	stats.Resolved++
+	if expiry, ok := resolver.ParseExpiry(redirectURL); ok {
+		lifetime := time.Until(expiry)
+		stats.WithExpiry++
+		tracker.total[remote] += lifetime
+		stats.AvgLifetimeSec = tracker.total[remote].Seconds() / float64(stats.WithExpiry)
+		if stats.MinLifetimeSec == 0 || lifetime.Seconds() < stats.MinLifetimeSec {
+			stats.MinLifetimeSec = lifetime.Seconds()
+		}
+	}
+
+	if link, ok := tracker.links[cacheKey]; ok { // 后台刷新时保留最近访问时间
+		link.expireAt = expireAt
+		return
+	}
+	link := &trackedLink{
+		path:      path,
+		userAgent: userAgent,
+		remote:    remote,
+		expireAt:  expireAt,
+	}
+	if accessed {
+		link.lastAccess = time.Now()
+	}
+	tracker.links[cacheKey] = link
+}
+
+// 更新链接的最近访问时间
+func (tracker *linkExpiryTracker) touch(cacheKey string) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	if link, ok := tracker.links[cacheKey]; ok {
+		link.lastAccess = time.Now()
+	}
+}
+
+// 获取需要后台刷新的链接，并移除不再观看的链接
+func (tracker *linkExpiryTracker) dueLinks() []trackedLink {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	now := time.Now()
+	var due []trackedLink
+	for cacheKey, link := range tracker.links {
+		active := now.Sub(link.lastAccess) < linkActiveWindow
+		switch {
+		case !active && now.After(link.expireAt):
+			delete(tracker.links, cacheKey)
+		case active && link.expireAt.Sub(now) < linkRefreshAhead:
+			due = append(due, *link)
+		}
+	}
+	return due
+}
+
+// 记录后台刷新结果
+func (tracker *linkExpiryTracker) recordRefresh(remote string, err error) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	stats, ok := tracker.stats[remote]
+	if !ok {
+		stats = &RemoteExpiryStats{Remote: remote}
+		tracker.stats[remote] = stats
+	}
+	if err != nil {
+		stats.RefreshFailures++
+	} else {
+		stats.Refreshed++
+	}
+}
+
+// 刷新即将过期的正在观看的链接
+func (tracker *linkExpiryTracker) refreshDue() {
+	for _, link := range tracker.dueLinks() {
+		_, err := refreshRedirectURL(link.path, link.userAgent)
+		tracker.recordRefresh(link.remote, err)
+		if err != nil {
+			logging.Warning("后台刷新下载链接失败：", link.path, "，错误：", err)
+		} else {
+			logging.Debug("后台刷新下载链接：", link.path)
+		}
+	}
+}
+
+// 后台定期刷新，直到 ctx 取消
+func (tracker *linkExpiryTracker) run(ctx context.Context, period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			tracker.refreshDue()
+		}
+	}
+}
+
+// 按配置启动或停止后台刷新
+//
+// 每次初始化（包括热重载）时调用，先停止正在运行的后台刷新
+func restartLinkRefresher() {
+	StopLinkRefresher()
+	if !config.Cache().RefreshLinks {
+		return
+	}
+
+	refresherMutex.Lock()
+	defer refresherMutex.Unlock()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	refresherCancel, refresherDone = cancel, done
+	go func() {
+		defer close(done)
+		expiryTracker.run(ctx, linkRefreshPeriod)
+	}()
+}
+
+// 停止后台刷新，等待正在进行的刷新完成
+func StopLinkRefresher() {
+	refresherMutex.Lock()
+	cancel, done := refresherCancel, refresherDone
+	refresherCancel, refresherDone = nil, nil
+	refresherMutex.Unlock()
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+// 获取各远程存储的链接过期统计
+func (tracker *linkExpiryTracker) Stats() []RemoteExpiryStats {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	tracked := make(map[string]int)
+	for _, link := range tracker.links {
+		tracked[link.remote]++
+	}
+	result := make([]RemoteExpiryStats, 0, len(tracker.stats))
+	for remote, stats := range tracker.stats {
+		item := *stats
+		item.Tracked = tracked[remote]
+		result = append(result, item)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Remote < result[j].Remote })
+	return result
+}
+
+// 获取链接过期统计
+func (h *CacheStatsHandler) GetExpiryStats(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{
+		"remotes":   expiryTracker.Stats(),
+		"timestamp": time.Now(),
+	})
+}
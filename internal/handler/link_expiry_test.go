@@ -0,0 +1,126 @@
+package handler_test
+
+import (
+	"MediaWarp/constants"
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/handler"
+	"MediaWarp/internal/resolver"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// 返回签名链接，soon 开头的链接 6 分钟后过期，其余 10 分钟后过期
+type expiringResolver struct {
+	count atomic.Int32
+}
+
+func (r *expiringResolver) Name() string {
+	return "expiring"
+}
+
+func (r *expiringResolver) Resolve(ctx context.Context, link string, userAgent string) (string, error) {
+	r.count.Add(1)
+	lifetime := 10 * time.Minute
+	if strings.HasPrefix(link, "expiring://soon") {
+		lifetime = 6 * time.Minute
+	}
+	return fmt.Sprintf("https://cdn.example.com/movie.mkv?t=%d", time.Now().Add(lifetime).Unix()), nil
+}
+
+func TestExpiryStats(t *testing.T) {
+	expiringResolver := &expiringResolver{}
+	resolver.Register("expiring", expiringResolver)
+
+	localPath := t.TempDir()
+	strmPath := filepath.Join(localPath, "Movie.strm")
+	if err := os.WriteFile(strmPath, []byte("expiring://movie.mkv"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mediaWarp, client := startMediaWarp(t, constants.PLEX, plexItemStub("2", strmPath), func(s *config.Snapshot) {
+		s.MediaSync = config.MediaSyncSetting{{Name: "expiring", Remote: "expiring:", LocalPath: localPath}}
+	})
+	client.Get(mediaWarp.URL + "/video/:/transcode/universal/decision?path=/library/metadata/2")
+	for range 2 { // 第二次请求命中缓存
+		resp, err := client.Get(mediaWarp.URL + "/library/parts/2/1/file.mkv")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	stats := expiryStats(t, mediaWarp.URL, client)
+	if stats.Resolved != 1 || stats.WithExpiry != 1 || stats.Tracked != 1 {
+		t.Errorf("统计错误：%+v", stats)
+	}
+	if stats.MinLifetimeSec < 590 || stats.MinLifetimeSec > 600 {
+		t.Errorf("链接有效期错误：%f", stats.MinLifetimeSec)
+	}
+	// 缓存时间为链接过期时间减去安全余量
+	expireAt, ok := handler.CachedLinkExpiry("expiring://movie.mkv", testUserAgent)
+	if want := time.Now().Add(10*time.Minute - handler.LinkExpiryMargin); !ok || expireAt.Sub(want).Abs() > 2*time.Second {
+		t.Errorf("缓存过期时间错误。期望: %s, 实际: %s", want, expireAt)
+	}
+	handler.RefreshDueLinks()
+	if count := expiringResolver.count.Load(); count != 1 {
+		t.Errorf("距离过期较远的链接不应刷新，解析次数：%d", count)
+	}
+
+	// Emby 的 VideosHandler：6 分钟后过期的链接缓存 1 分钟，正在观看时在过期前刷新
+	mediaWarp, client = startMediaWarp(t, constants.EMBY, embyItemStub("71", strmPath, "expiring://soon.mkv"), func(s *config.Snapshot) {
+		s.MediaSync = config.MediaSyncSetting{{Name: "expiring", Remote: "expiring:", LocalPath: localPath}}
+	})
+	resp, err := client.Get(mediaWarp.URL + "/emby/videos/71/stream?MediaSourceId=71&Static=true")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("状态码错误：%d", resp.StatusCode)
+	}
+	before, ok := handler.CachedLinkExpiry("expiring://soon.mkv", testUserAgent)
+	if want := time.Now().Add(6*time.Minute - handler.LinkExpiryMargin); !ok || before.Sub(want).Abs() > 2*time.Second {
+		t.Errorf("缓存过期时间错误。期望: %s, 实际: %s", want, before)
+	}
+	time.Sleep(time.Second) // 链接的过期时间精确到秒
+	handler.RefreshDueLinks()
+	if count := expiringResolver.count.Load(); count != 3 {
+		t.Errorf("即将过期的链接应在过期前刷新，解析次数：%d", count)
+	}
+	if after, _ := handler.CachedLinkExpiry("expiring://soon.mkv", testUserAgent); !after.After(before) {
+		t.Errorf("刷新后应延长缓存时间：%s -> %s", before, after)
+	}
+	if stats := expiryStats(t, mediaWarp.URL, client); stats.Refreshed != 1 || stats.Tracked != 2 {
+		t.Errorf("刷新统计错误：%+v", stats)
+	}
+}
+
+// 获取 expiring 的链接过期统计
+func expiryStats(t *testing.T, mediaWarpURL string, client *http.Client) handler.RemoteExpiryStats {
+	t.Helper()
+	resp, err := client.Get(mediaWarpURL + "/api/cache/expiry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var result struct {
+		Remotes []handler.RemoteExpiryStats `json:"remotes"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	for _, stats := range result.Remotes {
+		if stats.Remote == "expiring" {
+			return stats
+		}
+	}
+	t.Fatalf("未找到 expiring 的统计：%+v", result.Remotes)
+	return handler.RemoteExpiryStats{}
+}
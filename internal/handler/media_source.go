@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"MediaWarp/constants"
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/limiter"
+	"MediaWarp/internal/logging"
+	"MediaWarp/internal/resolver"
+)
+
+// 媒体源的远程存储是否熔断
+func sourceFailing(path string) bool {
+	path = strmLink(path)
+	if !resolver.NeedResolve(path) {
+		return false
+	}
+	return limiter.Failing(resolver.Remote(path))
+}
+
+// 按 FailingPolicy 排序或隐藏远程存储熔断的媒体源
+//
+// path 返回媒体源的路径（Strm 文件内容），其余媒体源保持原有顺序
+func applySourcePolicy[T any](sources []T, path func(T) string) []T {
+	policy := config.MediaSource().FailingPolicy
+	if policy != constants.SourcePolicySort && policy != constants.SourcePolicyHide {
+		return sources
+	}
+
+	var healthy, failing []T
+	for _, source := range sources {
+		if sourceFailing(path(source)) {
+			failing = append(failing, source)
+		} else {
+			healthy = append(healthy, source)
+		}
+	}
+	if len(failing) == 0 {
+		return sources
+	}
+	if policy == constants.SourcePolicyHide {
+		if len(healthy) == 0 { // 全部熔断时保留所有媒体源，由客户端自行重试
+			return sources
+		}
+		logging.Info("隐藏远程存储熔断的媒体源：", len(failing))
+		return healthy
+	}
+	logging.Info("远程存储熔断的媒体源排至最后：", len(failing))
+	return append(healthy, failing...)
+}
@@ -0,0 +1,60 @@
+package handler_test
+
+import (
+	"MediaWarp/constants"
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/limiter"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSourcePolicy(t *testing.T) {
+	t.Cleanup(limiter.Init) // 在恢复配置后重新初始化限流器
+	mediaWarp, client := startMediaWarp(t, constants.EMBY, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/PlaybackInfo") {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"MediaSources":[{"Id":"1","Path":"broken://2160p.mkv"},{"Id":"2","Path":"healthy://1080p.mkv"}]}`))
+			return
+		}
+		w.Write([]byte(`{"Items":[]}`))
+	}, func(s *config.Snapshot) {
+		s.RemoteLimit = config.RemoteLimitSetting{Enable: true, QPS: 100, Burst: 10, FailureThreshold: 1, Cooldown: 60}
+	})
+	limiter.Init()
+	limiter.Do(context.Background(), "broken", func() error { return errors.New("下载链接获取失败") })
+
+	tests := []struct {
+		policy   constants.SourcePolicy
+		expected string
+	}{
+		{constants.SourcePolicyNone, "1,2"},
+		{constants.SourcePolicySort, "2,1"},
+		{constants.SourcePolicyHide, "2"},
+	}
+	for _, tt := range tests {
+		config.Update(func(s *config.Snapshot) { s.MediaSource = config.MediaSourceSetting{FailingPolicy: tt.policy} })
+		resp, err := client.Get(mediaWarp.URL + "/emby/Items/100/PlaybackInfo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var playbackInfo struct {
+			MediaSources []struct{ Id string }
+		}
+		err = json.NewDecoder(resp.Body).Decode(&playbackInfo)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := make([]string, 0, len(playbackInfo.MediaSources))
+		for _, source := range playbackInfo.MediaSources {
+			ids = append(ids, source.Id)
+		}
+		if got := strings.Join(ids, ","); got != tt.expected {
+			t.Errorf("%s 策略的媒体源顺序错误：%s，期望：%s", tt.policy, got, tt.expected)
+		}
+	}
+}
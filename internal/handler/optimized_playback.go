@@ -30,7 +30,7 @@ func NewOptimizedPlaybackHandler(serverType string) *OptimizedPlaybackHandler {
 
 	switch serverType {
 	case "emby":
-		handler.embyServer = emby.New(config.MediaServer.ADDR, config.MediaServer.AUTH)
+		handler.embyServer = emby.New(config.MediaServer().ADDR, config.MediaServer().AUTH)
 	default:
 		logging.Warning("Unsupported server type:", serverType, "- only 'emby' is supported")
 		return nil
@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"MediaWarp/constants"
+	"MediaWarp/internal/cache"
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/logging"
+	"MediaWarp/internal/resolver"
+	"MediaWarp/internal/service/plex"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Plex服务器处理器
+type PlexServerHandler struct {
+	server      *plex.PlexServer       // Plex 服务器
+	routerRules []RegexpRouteRule      // 正则路由规则
+	proxy       *httputil.ReverseProxy // 反向代理
+	partCache   *cache.SafeCache       // PartID -> 媒体文件路径，以及已获取元数据的媒体项
+}
+
+const (
+	plexPartCacheTime     = 30 * time.Minute // Part 文件路径缓存时间
+	plexMetadataKeyPrefix = "metadata:"      // 已获取元数据的媒体项在 Part 缓存中的键前缀（PartID 均为数字，不会冲突）
+)
+
+// 初始化
+func NewPlexServerHandler(addr string, token string) (*PlexServerHandler, error) {
+	return newPlexServerHandler(addr, token, nil)
+}
+
+// 初始化并使用已有的 Part 缓存，partCache 为 nil 时创建新的缓存
+func newPlexServerHandler(addr string, token string, partCache *cache.SafeCache) (*PlexServerHandler, error) {
+	var plexServerHandler = PlexServerHandler{}
+	plexServerHandler.server = plex.New(addr, token)
+	if partCache == nil {
+		partCache = cache.NewSafeCache(5 * time.Minute)
+	}
+	plexServerHandler.partCache = partCache
+	target, err := url.Parse(plexServerHandler.server.GetEndpoint())
+	if err != nil {
+		return nil, err
+	}
+	plexServerHandler.proxy = httputil.NewSingleHostReverseProxy(target)
+
+	{ // 初始化路由规则
+		plexServerHandler.routerRules = []RegexpRouteRule{
+			{
+				Regexp:  constants.PlexRegexp.Router.VideosHandler,
+				Handler: plexServerHandler.VideosHandler,
+			},
+			{
+				Regexp:  constants.PlexRegexp.Router.DecisionHandler,
+				Handler: plexServerHandler.DecisionHandler,
+			},
+			{
+				Regexp:  constants.PlexRegexp.Router.MetadataHandler,
+				Handler: plexServerHandler.MetadataHandler,
+			},
+		}
+	}
+	return &plexServerHandler, nil
+}
+
+// 转发请求至上游服务器
+func (plexServerHandler *PlexServerHandler) ReverseProxy(rw http.ResponseWriter, req *http.Request) {
+	plexServerHandler.proxy.ServeHTTP(rw, req)
+}
+
+// 正则路由表
+func (plexServerHandler *PlexServerHandler) GetRegexpRouteRules() []RegexpRouteRule {
+	return plexServerHandler.routerRules
+}
+
+// 通过 Plex API 获取媒体元数据，并记录各 Part 对应的文件路径
+//
+// 相同媒体项的并发请求只会请求一次上游
+func (plexServerHandler *PlexServerHandler) indexMetadata(ratingKey string) error {
+	_, err := itemGroup.Do("plex|"+plexServerHandler.server.GetEndpoint()+"|"+ratingKey, func() (any, error) {
+		resp, err := plexServerHandler.server.GetMetadata(ratingKey)
+		if err != nil {
+			return nil, err
+		}
+		plexServerHandler.recordParts(resp.MediaContainer.Parts())
+		plexServerHandler.partCache.Set(plexMetadataKeyPrefix+ratingKey, "", time.Now().Add(plexPartCacheTime))
+		return nil, nil
+	})
+	return err
+}
+
+// 记录各 Part 对应的文件路径
+func (plexServerHandler *PlexServerHandler) recordParts(parts []plex.Part) {
+	expireTime := time.Now().Add(plexPartCacheTime)
+	for _, part := range parts {
+		if part.File == "" {
+			continue
+		}
+		plexServerHandler.partCache.Set(strconv.FormatInt(part.ID, 10), part.File, expireTime)
+		logging.Debug("记录 Plex Part：", part.ID, " -> ", part.File)
+	}
+}
+
+// 获取 Part 对应的媒体文件路径
+//
+// 未记录时（如客户端直接请求媒体文件）通过 /library/parts/:partId 获取
+func (plexServerHandler *PlexServerHandler) getPartFile(partID string) (string, bool) {
+	if item, found := plexServerHandler.partCache.Get(partID); found {
+		return item.URL, true
+	}
+	resp, err := plexServerHandler.server.GetPart(partID)
+	if err != nil {
+		logging.Debug("获取 Plex Part 失败：", partID, "，错误：", err)
+		return "", false
+	}
+	plexServerHandler.recordParts(resp.MediaContainer.Parts())
+	if item, found := plexServerHandler.partCache.Get(partID); found {
+		return item.URL, true
+	}
+	return "", false
+}
+
+// 判断文件是否位于 MediaSync 的本地路径下
+func isMediaSyncPath(filePath string) bool {
+	for _, server := range config.MediaSync() {
+		if server.LocalPath != "" && strings.HasPrefix(filePath, server.LocalPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// 媒体文件处理器
+//
+// /library/parts/:partId/:changestamp/file.ext
+// 位于 MediaSync 本地路径下的 Strm 文件重定向至真实链接，其余请求转发至上游服务器
+func (plexServerHandler *PlexServerHandler) VideosHandler(ctx *gin.Context) {
+	logging.Debug("======= Plex VideosHandler ======= ")
+
+	if ctx.Request.Method == http.MethodHead { // 不额外处理 HEAD 请求
+		plexServerHandler.ReverseProxy(ctx.Writer, ctx.Request)
+		logging.Debug("VideosHandler 不处理 HEAD 请求，转发至上游服务器")
+		return
+	}
+
+	matches := constants.PlexRegexp.Router.VideosHandler.FindStringSubmatch(ctx.Request.URL.Path)
+	if len(matches) < 2 {
+		plexServerHandler.ReverseProxy(ctx.Writer, ctx.Request)
+		return
+	}
+	partID := matches[1]
+
+	filePath, found := plexServerHandler.getPartFile(partID)
+	if !found {
+		logging.Debug("未找到 Plex Part 对应的文件：", partID, "，转发至上游服务器")
+		plexServerHandler.ReverseProxy(ctx.Writer, ctx.Request)
+		return
+	}
+
+	if !strings.HasSuffix(strings.ToLower(filePath), ".strm") || !isMediaSyncPath(filePath) {
+		logging.Debug("播放本地视频：" + filePath + "，不进行处理")
+		plexServerHandler.ReverseProxy(ctx.Writer, ctx.Request)
+		return
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		logging.Warning("读取 strm 文件失败：", err)
+		plexServerHandler.ReverseProxy(ctx.Writer, ctx.Request)
+		return
+	}
+	link := strings.TrimSpace(string(content))
+
+	if resolver.Scheme(link) == "" {
+		logging.Warning("无法识别的 Strm 内容：", link)
+		plexServerHandler.ReverseProxy(ctx.Writer, ctx.Request)
+		return
+	}
+
+	userAgent := ctx.Request.Header.Get("User-Agent")
+	redirectURL, err := getRedirectURL(link, userAgent)
+	if err != nil {
+		logging.Warning("获取下载链接失败：", err)
+		plexServerHandler.ReverseProxy(ctx.Writer, ctx.Request)
+		return
+	}
+	logging.Info("HTTPStrm 重定向至：", redirectURL)
+	serveStream(ctx, redirectURL, func() (string, error) { return refreshRedirectURL(link, userAgent) })
+}
+
+// 播放决策处理器
+//
+// /video/:/transcode/universal/decision?path=/library/metadata/:ratingKey
+// 在客户端请求媒体文件前记录 Part 对应的文件路径
+func (plexServerHandler *PlexServerHandler) DecisionHandler(ctx *gin.Context) {
+	logging.Debug("======= Plex DecisionHandler ======= ")
+
+	matches := constants.PlexRegexp.Others.MetadataPathReg.FindStringSubmatch(ctx.Query("path"))
+	if len(matches) == 2 {
+		if err := plexServerHandler.indexMetadata(matches[1]); err != nil {
+			logging.Warning("获取 Plex 媒体元数据失败：", err)
+		}
+	}
+	plexServerHandler.ReverseProxy(ctx.Writer, ctx.Request)
+}
+
+// 媒体元数据处理器
+//
+// /library/metadata/:ratingKey
+// 直接播放的客户端不会请求播放决策接口，在此异步记录 Part 对应的文件路径；
+// 浏览媒体库时会频繁请求，plexPartCacheTime 内已获取过元数据的媒体项不再请求上游
+func (plexServerHandler *PlexServerHandler) MetadataHandler(ctx *gin.Context) {
+	plexServerHandler.ReverseProxy(ctx.Writer, ctx.Request)
+
+	if ctx.Request.Method != http.MethodGet {
+		return
+	}
+	ratingKey := strings.TrimPrefix(ctx.Request.URL.Path, "/library/metadata/")
+	if _, found := plexServerHandler.partCache.Get(plexMetadataKeyPrefix + ratingKey); found {
+		return
+	}
+	go func() {
+		if err := plexServerHandler.indexMetadata(ratingKey); err != nil {
+			logging.Warning("获取 Plex 媒体元数据失败：", err)
+		}
+	}()
+}
+
+var _ MediaServerHandler = (*PlexServerHandler)(nil) // 确保 PlexServerHandler 实现 MediaServerHandler 接口
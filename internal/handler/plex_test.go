@@ -0,0 +1,125 @@
+package handler_test
+
+import (
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/handler"
+	"MediaWarp/internal/logging"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPlexStrmRedirect(t *testing.T) {
+	logging.Init()
+	gin.SetMode(gin.TestMode)
+
+	localPath := t.TempDir()
+	strmPath := filepath.Join(localPath, "Movie (2024).strm")
+	if err := os.WriteFile(strmPath, []byte("https://cdn.example.com/movie.mkv\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	localFile := filepath.Join(t.TempDir(), "Local.mkv")
+	var metadataQueries atomic.Int32 // MediaWarp 通过 API 获取元数据的次数
+	defer config.Update(func(s *config.Snapshot) {
+		s.MediaSync = config.MediaSyncSetting{{Name: "115", Remote: "115", LocalPath: localPath}}
+	})()
+
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/library/parts/779":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"MediaContainer":{"size":1,"Part":[{"id":779,"file":` + jsonString(strmPath) + `}]}}`))
+		case r.URL.Path == "/library/parts/999":
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == "/library/metadata/1234":
+			if r.URL.Query().Get("X-Plex-Token") != "" {
+				metadataQueries.Add(1)
+				time.Sleep(100 * time.Millisecond)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"MediaContainer":{"size":1,"Metadata":[{"ratingKey":"1234","Media":[{"id":1,"Part":[{"id":777,"file":` + jsonString(strmPath) + `},{"id":778,"file":` + jsonString(localFile) + `}]}]}]}}`))
+		default:
+			w.Write([]byte("upstream"))
+		}
+	}))
+	defer stub.Close()
+
+	plexServerHandler, err := handler.NewPlexServerHandler(stub.URL, "plex-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine := gin.New()
+	engine.NoRoute(func(ctx *gin.Context) {
+		for _, rule := range plexServerHandler.GetRegexpRouteRules() {
+			if rule.Regexp.MatchString(ctx.Request.URL.Path) {
+				rule.Handler(ctx)
+				return
+			}
+		}
+		plexServerHandler.ReverseProxy(ctx.Writer, ctx.Request)
+	})
+
+	mediaWarp := httptest.NewServer(engine)
+	defer mediaWarp.Close()
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	request := func(target string) *http.Response {
+		resp, err := client.Get(mediaWarp.URL + target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	// 浏览媒体库时并发请求同一媒体项的元数据只请求一次上游 API，之后不再请求
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			request("/library/metadata/1234")
+		}()
+	}
+	wg.Wait()
+	time.Sleep(300 * time.Millisecond) // 等待异步获取元数据完成
+	request("/library/metadata/1234")
+	request("/video/:/transcode/universal/decision?path=%2Flibrary%2Fmetadata%2F1234") // 播放决策始终重新获取
+	if count := metadataQueries.Load(); count != 2 {
+		t.Errorf("获取元数据次数错误：%d", count)
+	}
+
+	// Plex 中不存在的 Part 转发至上游
+	if resp := request("/library/parts/999/1700000000/file.strm"); resp.StatusCode != http.StatusOK {
+		t.Errorf("未找到的 Part 应转发至上游，实际状态码: %d", resp.StatusCode)
+	}
+
+	// 未记录的 Part 通过 /library/parts 获取文件路径
+	if resp := request("/library/parts/779/1700000000/file.strm"); resp.StatusCode != http.StatusFound {
+		t.Errorf("未记录的 Part 应通过 /library/parts 获取后重定向，实际状态码: %d", resp.StatusCode)
+	}
+
+	resp := request("/library/parts/777/1700000000/file.strm")
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("状态码错误。期望: %d, 实际: %d", http.StatusFound, resp.StatusCode)
+	}
+	if location := resp.Header.Get("Location"); location != "https://cdn.example.com/movie.mkv" {
+		t.Errorf("重定向地址错误：%s", location)
+	}
+
+	// 非 Strm 文件转发至上游
+	if resp := request("/library/parts/778/1700000000/file.mkv"); resp.StatusCode != http.StatusOK {
+		t.Errorf("非 Strm 文件应转发至上游，实际状态码: %d", resp.StatusCode)
+	}
+}
+
+func jsonString(s string) string {
+	return `"` + strings.ReplaceAll(s, `\`, `\\`) + `"`
+}
@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"MediaWarp/internal/cache"
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/logging"
+	"MediaWarp/internal/resolver"
+	"MediaWarp/internal/service/emby"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	preloadPlanInterval = 10 * time.Minute    // 同一客户端播放同一媒体项时，在该时间内只规划一次预加载
+	preloadItemFields   = "Path,MediaSources" // 预加载需要的媒体项字段
+)
+
+var (
+	plannedPreloads = cache.NewSafeCache(5 * time.Minute) // 已规划的预加载
+	planMutex       sync.Mutex                            // 保证同一规划只执行一次
+)
+
+// 预加载单个下载链接
+//
+// 已缓存、正在预加载、远程存储熔断或限流、并发已满时跳过，返回是否完成预加载
+func preloadLink(path string, userAgent string) bool {
+	path = strmLink(path)
+	cacheKey := redirectCacheKey(path, userAgent)
+
+	// 检查是否已缓存
+	if _, exists := redirectURLCache.Get(cacheKey); exists {
+		logging.Info("✅ 预加载跳过，已缓存:", userAgent)
+		return false
+	}
+
+	// 使用预加载管理器检查是否可以预加载
+	if !preloadManager.CanPreload(cacheKey, resolver.Remote(path)) {
+		logging.Info("⏸️ 预加载跳过，管理器拒绝:", cacheKey)
+		return false
+	}
+
+	// 尝试开始预加载（获取并发控制信号量）
+	if !preloadManager.StartPreload(cacheKey) {
+		logging.Info("⏸️ 预加载跳过，并发限制:", cacheKey)
+		return false
+	}
+
+	var err error
+	defer func() { // 确保在函数结束时释放资源
+		if r := recover(); r != nil {
+			logging.Error("预加载panic:", r)
+			err = fmt.Errorf("panic: %v", r)
+		}
+		preloadManager.FinishPreload(cacheKey, err)
+	}()
+
+	// 添加随机延迟，避免瞬间大量请求
+	delay := time.Duration(rand.Intn(500)+100) * time.Millisecond
+	time.Sleep(delay)
+	logging.Info("🕐 预加载延迟:", delay, "cacheKey:", cacheKey)
+
+	// 预加载下载链接（与同时发起的串流请求合并，解析超时见 linkResolveTimeout）
+	logging.Info("🔄 预加载下载链接，User-Agent:", userAgent)
+	if _, err = resolveAndCache(path, userAgent, true); err != nil {
+		return false
+	}
+	logging.Info("✅ 预加载完成并缓存:", userAgent)
+	return true
+}
+
+// 预加载媒体项的全部媒体源
+//
+// 逐个预加载需要解析的媒体源，返回完成预加载的数量
+func preloadMediaSources(mediaSources []emby.MediaSourceInfo, userAgent string) int {
+	preloaded := 0
+	for _, mediaSource := range mediaSources {
+		if mediaSource.Path == nil || !resolver.NeedResolve(*mediaSource.Path) {
+			continue
+		}
+		logging.Info("🎯 发现可预加载的视频:", *mediaSource.Path)
+		if preloadLink(*mediaSource.Path, userAgent) {
+			preloaded++
+		}
+	}
+	return preloaded
+}
+
+// 获取设备当前的会话
+func (embyServerHandler *EmbyServerHandler) getSession(deviceID string) (emby.SessionInfo, bool) {
+	if deviceID == "" {
+		return emby.SessionInfo{}, false
+	}
+	sessions, err := embyServerHandler.server.SessionsServiceGetSessions(deviceID)
+	if err != nil {
+		logging.Warning("获取会话列表失败：", err)
+		return emby.SessionInfo{}, false
+	}
+	for _, session := range sessions {
+		if session.UserID != nil && session.DeviceID != nil && *session.DeviceID == deviceID {
+			return session, true
+		}
+	}
+	return emby.SessionInfo{}, false
+}
+
+// 获取设备当前会话的用户 ID
+func (embyServerHandler *EmbyServerHandler) getUserID(deviceID string) string {
+	if session, ok := embyServerHandler.getSession(deviceID); ok {
+		return *session.UserID
+	}
+	return ""
+}
+
+// 获取需要预加载的媒体项
+//
+// 正在播放剧集时为之后的 NextEpisodes 集，以及用户继续观看列表中的前 ResumeItems 项
+func (embyServerHandler *EmbyServerHandler) preloadCandidates(itemID string, userID string) []emby.BaseItemDto {
+	var candidates []emby.BaseItemDto
+	if n := config.Preload().NextEpisodes; n > 0 {
+		itemResponse, err := embyServerHandler.queryItem(itemID)
+		if err != nil || len(itemResponse.Items) == 0 {
+			logging.Warning("预加载规划获取媒体项信息失败：", err)
+		} else if item := itemResponse.Items[0]; item.Type != nil && *item.Type == "Episode" && item.SeriesID != nil {
+			episodes, err := embyServerHandler.server.ShowsServiceGetEpisodes(*item.SeriesID, userID, itemID, n+1, preloadItemFields)
+			if err != nil {
+				logging.Warning("预加载规划获取剧集列表失败：", err)
+			} else {
+				candidates = append(candidates, episodes.Items...)
+			}
+		}
+	}
+	if n := config.Preload().ResumeItems; n > 0 && userID != "" {
+		resume, err := embyServerHandler.server.ItemsServiceGetResumeItems(userID, n, preloadItemFields)
+		if err != nil {
+			logging.Warning("预加载规划获取继续观看列表失败：", err)
+		} else {
+			candidates = append(candidates, resume.Items...)
+		}
+	}
+
+	result := make([]emby.BaseItemDto, 0, len(candidates))
+	seen := map[string]bool{itemID: true} // 跳过正在播放的媒体项和重复项
+	for _, candidate := range candidates {
+		if candidate.ID == nil || seen[*candidate.ID] {
+			continue
+		}
+		seen[*candidate.ID] = true
+		result = append(result, candidate)
+	}
+	return result
+}
+
+// 规划预加载
+//
+// 客户端开始播放时预加载之后的剧集和继续观看列表的下载链接
+// 逐个预加载，只占用 PreloadManager 的一个并发，且受远程存储的限流和熔断约束
+func (embyServerHandler *EmbyServerHandler) planPreload(itemID string, deviceID string, userAgent string) {
+	if !config.Preload().Enable || itemID == "" || userAgent == "" {
+		return
+	}
+
+	planKey := embyServerHandler.cacheNamespace + "|" + itemID + "|" + deviceID + "|" + userAgent
+	planMutex.Lock()
+	if _, planned := plannedPreloads.Get(planKey); planned {
+		planMutex.Unlock()
+		return
+	}
+	plannedPreloads.Set(planKey, itemID, time.Now().Add(preloadPlanInterval))
+	planMutex.Unlock()
+
+	userID := embyServerHandler.getUserID(deviceID)
+	candidates := embyServerHandler.preloadCandidates(itemID, userID)
+	logging.Info("🚀 预加载规划开始，ItemId:", itemID, "，候选媒体项：", len(candidates))
+
+	preloaded := 0
+	for _, candidate := range candidates {
+		preloaded += preloadMediaSources(candidate.MediaSources, userAgent)
+	}
+	logging.Info("🎉 预加载规划完成，ItemId:", itemID, "，预加载：", preloaded)
+}
+
+// 开始播放处理器
+//
+// /Sessions/Playing
+// 转发至上游服务器后根据正在播放的媒体项规划预加载
+func (embyServerHandler *EmbyServerHandler) SessionsPlayingHandler(ctx *gin.Context) {
+	logging.Debug("======= SessionsPlayingHandler ======= ")
+
+	var startInfo emby.PlaybackStartInfo
+	if ctx.Request.Body != nil {
+		body, err := io.ReadAll(ctx.Request.Body)
+		ctx.Request.Body.Close()
+		if err != nil {
+			logging.Warning("读取 Body 出错：", err)
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+		if err := json.Unmarshal(body, &startInfo); err != nil {
+			logging.Debug("解析 PlaybackStartInfo 失败：", err)
+		}
+	}
+	deviceID := getDeviceID(ctx.Request)
+	userAgent := ctx.Request.Header.Get("User-Agent")
+
+	embyServerHandler.ReverseProxy(ctx.Writer, ctx.Request)
+
+	if startInfo.ItemID != nil {
+		go embyServerHandler.planPreload(*startInfo.ItemID, deviceID, userAgent)
+	}
+}
@@ -0,0 +1,94 @@
+package handler_test
+
+import (
+	"MediaWarp/constants"
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/resolver"
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 记录解析过的链接
+type recordResolver struct {
+	mutex sync.Mutex
+	links []string
+}
+
+func (r *recordResolver) Name() string {
+	return "record"
+}
+
+func (r *recordResolver) Resolve(ctx context.Context, link string, userAgent string) (string, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.links = append(r.links, link)
+	return "https://cdn.example.com/" + strings.TrimPrefix(link, "record://"), nil
+}
+
+func (r *recordResolver) Links() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	links := append([]string(nil), r.links...)
+	sort.Strings(links)
+	return links
+}
+
+func TestPreloadPlanner(t *testing.T) {
+	recordResolver := &recordResolver{}
+	resolver.Register("record", recordResolver)
+
+	episode := func(id string) string {
+		return `{"Id":"` + id + `","Type":"Episode","SeriesId":"100","Path":"/media/` + id + `.strm","MediaSources":[{"Id":"` + id + `","Path":"record://` + id + `.mkv"}]}`
+	}
+	mediaWarp, client := startMediaWarp(t, constants.EMBY, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/Items":
+			w.Write([]byte(`{"Items":[` + episode("101") + `]}`))
+		case "/Sessions":
+			if r.URL.Query().Get("DeviceId") == "device-1" {
+				w.Write([]byte(`[{"Id":"s1","UserId":"user-1","DeviceId":"device-1"}]`))
+				return
+			}
+			w.Write([]byte(`[]`))
+		case "/Shows/100/Episodes":
+			if r.URL.Query().Get("StartItemId") != "101" || r.URL.Query().Get("Limit") != "3" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`{"Items":[` + episode("101") + `,` + episode("102") + `,` + episode("103") + `]}`))
+		case "/Users/user-1/Items/Resume":
+			w.Write([]byte(`{"Items":[` + episode("102") + `,{"Id":"200","Type":"Movie","MediaSources":[{"Id":"200","Path":"record://200.mkv"}]},{"Id":"300","Type":"Movie","MediaSources":[{"Id":"300","Path":"/media/local.mkv"}]}]}`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}, func(s *config.Snapshot) {
+		s.Preload = config.PreloadSetting{Enable: true, NextEpisodes: 2, ResumeItems: 3}
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, mediaWarp.URL+"/emby/Sessions/Playing", strings.NewReader(`{"ItemId":"101","MediaSourceId":"101"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Emby-Device-Id", "device-1")
+	req.Header.Set("User-Agent", "Infuse/7.0")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("开始播放请求未转发至上游：%d", resp.StatusCode)
+	}
+
+	expected := []string{"record://102.mkv", "record://103.mkv", "record://200.mkv"}
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) && len(recordResolver.Links()) < len(expected) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	if links := recordResolver.Links(); strings.Join(links, ",") != strings.Join(expected, ",") {
+		t.Errorf("预加载链接错误：%v", links)
+	}
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/logging"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 自定义改写器未设置优先级时使用的优先级，在内置改写器之后执行
+const customRewriterPriority = 1000
+
+// 响应改写器
+//
+// 改写匹配路由和 Content-Type 的上游响应体，同一请求匹配的多个改写器按 Priority 从小到大依次执行
+type ResponseRewriter struct {
+	Name        string                                               // 名称，配置中按名称禁用或调整优先级
+	Regexp      *regexp.Regexp                                       // 匹配请求路径
+	ContentType string                                               // 匹配响应的 Content-Type 前缀（如 application/json），为空时不限制
+	Priority    int                                                  // 优先级，越小越先执行
+	Enable      bool                                                 // 是否启用
+	Rewrite     func(rw *http.Response, body []byte) ([]byte, error) // 改写解码后的响应体，可修改响应头
+}
+
+// 判断改写器是否处理该 Content-Type
+func (rewriter *ResponseRewriter) matchContentType(contentType string) bool {
+	return rewriter.ContentType == "" || strings.HasPrefix(strings.ToLower(contentType), strings.ToLower(rewriter.ContentType))
+}
+
+// 响应改写器链，按优先级排序
+type rewriterChain []*ResponseRewriter
+
+// 创建响应改写器链
+//
+// 按 config.Rewriters() 禁用内置改写器或调整其优先级，并添加自定义的文本替换改写器；只保留启用的改写器
+func newRewriterChain(builtin []ResponseRewriter) (rewriterChain, error) {
+	var chain rewriterChain
+	rules := make(map[string]config.RewriterRule, len(config.Rewriters()))
+	for _, rule := range config.Rewriters() {
+		rules[strings.ToLower(rule.Name)] = rule
+	}
+	for i := range builtin {
+		rewriter := &builtin[i]
+		if rule, ok := rules[strings.ToLower(rewriter.Name)]; ok {
+			delete(rules, strings.ToLower(rewriter.Name))
+			rewriter.Enable = rewriter.Enable && !rule.Disable
+			if rule.Priority != 0 {
+				rewriter.Priority = rule.Priority
+			}
+		}
+		chain = append(chain, rewriter)
+	}
+
+	for _, rule := range config.Rewriters() { // 按配置顺序添加自定义改写器
+		if _, ok := rules[strings.ToLower(rule.Name)]; !ok {
+			continue
+		}
+		rewriter, err := newReplaceRewriter(rule)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, rewriter)
+	}
+
+	chain = slices.DeleteFunc(chain, func(rewriter *ResponseRewriter) bool { return !rewriter.Enable })
+	sort.SliceStable(chain, func(i, j int) bool { return chain[i].Priority < chain[j].Priority })
+	return chain, nil
+}
+
+// 根据配置创建文本替换改写器
+func newReplaceRewriter(rule config.RewriterRule) (*ResponseRewriter, error) {
+	if rule.Route == "" || rule.Pattern == "" {
+		return nil, fmt.Errorf("响应改写规则 %s 不是内置改写器，需要设置 Route 和 Pattern", rule.Name)
+	}
+	route, err := regexp.Compile(rule.Route)
+	if err != nil {
+		return nil, fmt.Errorf("响应改写规则 %s 的 Route 无效：%v", rule.Name, err)
+	}
+	pattern, err := regexp.Compile(rule.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("响应改写规则 %s 的 Pattern 无效：%v", rule.Name, err)
+	}
+	priority := rule.Priority
+	if priority == 0 {
+		priority = customRewriterPriority
+	}
+	replacement := []byte(rule.Replacement)
+	return &ResponseRewriter{
+		Name:        rule.Name,
+		Regexp:      route,
+		ContentType: rule.ContentType,
+		Priority:    priority,
+		Enable:      !rule.Disable,
+		Rewrite: func(rw *http.Response, body []byte) ([]byte, error) {
+			return pattern.ReplaceAll(body, replacement), nil
+		},
+	}, nil
+}
+
+// 获取匹配请求路径的改写器
+func (chain rewriterChain) match(requestPath string) rewriterChain {
+	var matched rewriterChain
+	for _, rewriter := range chain {
+		if rewriter.Regexp.MatchString(requestPath) {
+			matched = append(matched, rewriter)
+		}
+	}
+	return matched
+}
+
+// 生成改写器的正则路由规则
+//
+// 每个不同的路由正则生成一条规则，请求时执行所有匹配请求路径的改写器
+func (chain rewriterChain) routeRules(director func(*http.Request)) []RegexpRouteRule {
+	proxy := &httputil.ReverseProxy{Director: director, ModifyResponse: chain.modifyResponse}
+	handler := func(ctx *gin.Context) {
+		proxy.ServeHTTP(ctx.Writer, ctx.Request)
+	}
+
+	var (
+		rules []RegexpRouteRule
+		seen  = make(map[string]bool)
+	)
+	for _, rewriter := range chain {
+		if seen[rewriter.Regexp.String()] {
+			continue
+		}
+		seen[rewriter.Regexp.String()] = true
+		rules = append(rules, RegexpRouteRule{Regexp: rewriter.Regexp, Handler: handler})
+	}
+	return rules
+}
+
+// 依次执行匹配的改写器
+//
+// 只改写 2xx 响应；改写器出错时记录日志并跳过，继续执行之后的改写器
+func (chain rewriterChain) modifyResponse(rw *http.Response) error {
+	if rw.StatusCode < http.StatusOK || rw.StatusCode >= http.StatusMultipleChoices {
+		return nil
+	}
+	matched := chain.match(rw.Request.URL.Path)
+	if !slices.ContainsFunc(matched, func(rewriter *ResponseRewriter) bool {
+		return rewriter.matchContentType(rw.Header.Get("Content-Type"))
+	}) {
+		return nil
+	}
+
+	defer rw.Body.Close()
+	body, err := readBody(rw)
+	if err != nil {
+		logging.Warning("读取 Body 出错：", err)
+		return err
+	}
+	for _, rewriter := range matched {
+		if !rewriter.matchContentType(rw.Header.Get("Content-Type")) {
+			continue
+		}
+		logging.Debugf("执行响应改写器 %s：%s", rewriter.Name, rw.Request.URL.Path)
+		result, err := rewriter.Rewrite(rw, body)
+		if err != nil {
+			logging.Warningf("响应改写器 %s 执行失败，跳过：%v", rewriter.Name, err)
+			continue
+		}
+		body = result
+	}
+	return updateBody(rw, body)
+}
+
+// 解析 JSON 响应体，修改后重新序列化
+func rewriteJSON[T any](body []byte, modify func(*T)) ([]byte, error) {
+	var value T
+	if err := json.Unmarshal(body, &value); err != nil {
+		return nil, fmt.Errorf("解析 %T Json 错误：%w", value, err)
+	}
+	modify(&value)
+	return json.Marshal(value)
+}
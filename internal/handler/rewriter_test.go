@@ -0,0 +1,234 @@
+package handler_test
+
+import (
+	"MediaWarp/constants"
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/handler"
+	"MediaWarp/internal/logging"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+// 使用 testdata/rewriter 中录制的上游响应测试响应改写器链
+func TestResponseRewriters(t *testing.T) {
+	logging.Init()
+	gin.SetMode(gin.TestMode)
+
+	for caseName, testCase := range map[string]struct {
+		ServerType  constants.MediaServerType
+		Path        string
+		Fixture     string
+		ContentType string
+		Encoding    string // 上游响应的 Content-Encoding
+		Rewriters   []config.RewriterRule
+		Want        []string
+		NotWant     []string
+	}{
+		"Emby Strm 播放信息": {
+			ServerType:  constants.EMBY,
+			Path:        "/emby/Items/300/PlaybackInfo",
+			Fixture:     "emby_playbackinfo.json",
+			ContentType: "application/json; charset=utf-8",
+			Want:        []string{`"SupportsDirectPlay":true`, `/videos/300/stream?MediaSourceId=mediasource_300\u0026Static=true\u0026api_key=abc`, "中英双语 (MediaWarp)"},
+			NotWant:     []string{"master.m3u8"},
+		},
+		"Emby gzip 播放信息": {
+			ServerType:  constants.EMBY,
+			Path:        "/emby/Items/300/PlaybackInfo",
+			Fixture:     "emby_playbackinfo.json",
+			ContentType: "application/json",
+			Encoding:    "gzip",
+			Want:        []string{`"SupportsDirectPlay":true`},
+			NotWant:     []string{"master.m3u8"},
+		},
+		"Emby Brotli 播放信息": {
+			ServerType:  constants.EMBY,
+			Path:        "/emby/Items/300/PlaybackInfo",
+			Fixture:     "emby_playbackinfo.json",
+			ContentType: "application/json",
+			Encoding:    "br",
+			Want:        []string{`"SupportsDirectPlay":true`},
+			NotWant:     []string{"master.m3u8"},
+		},
+		"禁用内置改写器": {
+			ServerType:  constants.EMBY,
+			Path:        "/emby/Items/300/PlaybackInfo",
+			Fixture:     "emby_playbackinfo.json",
+			ContentType: "application/json",
+			Rewriters:   []config.RewriterRule{{Name: "bilingualsubtitles", Disable: true}},
+			Want:        []string{`"SupportsDirectPlay":true`},
+			NotWant:     []string{"中英双语 (MediaWarp)"},
+		},
+		"自定义改写器在内置改写器之后执行": {
+			ServerType:  constants.EMBY,
+			Path:        "/emby/Items/300/PlaybackInfo",
+			Fixture:     "emby_playbackinfo.json",
+			ContentType: "application/json",
+			Rewriters:   []config.RewriterRule{{Name: "NoStatic", Priority: 250, Route: `(?i)^(/emby)?/Items/\d+/PlaybackInfo$`, Pattern: `Static=true`, Replacement: "Static=false"}},
+			Want:        []string{`Static=false`},
+			NotWant:     []string{`Static=true`},
+		},
+		"自定义改写器在内置改写器之前执行": {
+			ServerType:  constants.EMBY,
+			Path:        "/emby/Items/300/PlaybackInfo",
+			Fixture:     "emby_playbackinfo.json",
+			ContentType: "application/json",
+			Rewriters:   []config.RewriterRule{{Name: "NoStatic", Priority: 150, Route: `(?i)^(/emby)?/Items/\d+/PlaybackInfo$`, Pattern: `Static=true`, Replacement: "Static=false"}},
+			Want:        []string{`Static=true`},
+			NotWant:     []string{`Static=false`},
+		},
+		"自定义改写器新增路由": {
+			ServerType:  constants.EMBY,
+			Path:        "/emby/System/Info/Public",
+			Fixture:     "emby_system_info.json",
+			ContentType: "application/json",
+			Rewriters:   []config.RewriterRule{{Name: "ServerName", Route: `(?i)^(/emby)?/System/Info/Public$`, ContentType: "application/json", Pattern: `"ServerName":"[^"]*"`, Replacement: `"ServerName":"MediaWarp"`}},
+			Want:        []string{`"ServerName":"MediaWarp"`, `"Version":"4.8.10.0"`},
+		},
+		"Content-Type 不匹配时不改写": {
+			ServerType:  constants.EMBY,
+			Path:        "/emby/System/Info/Public",
+			Fixture:     "emby_system_info.json",
+			ContentType: "text/plain",
+			Rewriters:   []config.RewriterRule{{Name: "ServerName", Route: `(?i)^(/emby)?/System/Info/Public$`, ContentType: "application/json", Pattern: `"ServerName":"[^"]*"`, Replacement: `"ServerName":"MediaWarp"`}},
+			Want:        []string{`"ServerName":"emby-nas"`},
+		},
+		"Emby basehtmlplayer.js": {
+			ServerType:  constants.EMBY,
+			Path:        "/web/modules/htmlvideoplayer/basehtmlplayer.js",
+			Fixture:     "emby_basehtmlplayer.js",
+			ContentType: "application/javascript",
+			Want:        []string{"{return null}"},
+			NotWant:     []string{`"anonymous"`},
+		},
+		"Emby 首页": {
+			ServerType:  constants.EMBY,
+			Path:        "/web/index.html",
+			Fixture:     "index.html",
+			ContentType: "text/html; charset=utf-8",
+			Want:        []string{"<script>console.log('MediaWarp')</script>\n</head>"},
+		},
+		"Jellyfin Strm 播放信息": {
+			ServerType:  constants.JELLYFIN,
+			Path:        "/Items/6b1a3d2b9f1c4e0e8f6c2a1b3c4d5e6f/PlaybackInfo",
+			Fixture:     "jellyfin_playbackinfo.json",
+			ContentType: "application/json",
+			Want:        []string{`"SupportsDirectPlay":true`},
+			NotWant:     []string{"master.m3u8"},
+		},
+		"Jellyfin 首页": {
+			ServerType:  constants.JELLYFIN,
+			Path:        "/web/",
+			Fixture:     "index.html",
+			ContentType: "text/html",
+			Want:        []string{"<script>console.log('MediaWarp')</script>\n</head>"},
+		},
+	} {
+		t.Run(caseName, func(t *testing.T) {
+			fixture, err := os.ReadFile(filepath.Join("testdata", "rewriter", testCase.Fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != testCase.Path { // 查询媒体项
+					w.Header().Set("Content-Type", "application/json")
+					w.Write([]byte(`{"Items":[{"Id":"300","Path":"/media/strm/movie.strm"}],"TotalRecordCount":1}`))
+					return
+				}
+				w.Header().Set("Content-Type", testCase.ContentType)
+				if testCase.Encoding == "" {
+					w.Write(fixture)
+					return
+				}
+				var buffer bytes.Buffer
+				var writer io.WriteCloser
+				switch testCase.Encoding {
+				case "gzip":
+					writer = gzip.NewWriter(&buffer)
+				case "br":
+					writer = brotli.NewWriter(&buffer)
+				}
+				writer.Write(fixture)
+				writer.Close()
+				w.Header().Set("Content-Encoding", testCase.Encoding)
+				w.Write(buffer.Bytes())
+			}))
+			defer upstream.Close()
+
+			defer config.Update(func(s *config.Snapshot) {
+				s.MediaSync = config.MediaSyncSetting{{Name: "strm", LocalPath: "/media/strm"}}
+				s.Subtitle = config.SubtitleSetting{Enable: true, Bilingual: true}
+				s.Web = config.WebSetting{Enable: true, Head: "<script>console.log('MediaWarp')</script>"}
+				s.Rewriters = testCase.Rewriters
+			})()
+
+			var server interface {
+				GetRegexpRouteRules() []handler.RegexpRouteRule
+				ReverseProxy(http.ResponseWriter, *http.Request)
+			}
+			switch testCase.ServerType {
+			case constants.EMBY:
+				server, err = handler.NewEmbyServerHandler(upstream.URL, "")
+			case constants.JELLYFIN:
+				server, err = handler.NewJellyfinServerHandler(upstream.URL, "")
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			engine := gin.New()
+			engine.NoRoute(func(ctx *gin.Context) {
+				for _, rule := range server.GetRegexpRouteRules() {
+					if rule.Regexp.MatchString(ctx.Request.URL.Path) {
+						rule.Handler(ctx)
+						return
+					}
+				}
+				server.ReverseProxy(ctx.Writer, ctx.Request)
+			})
+			mediaWarp := httptest.NewServer(engine)
+			defer mediaWarp.Close()
+
+			req, _ := http.NewRequest(http.MethodGet, mediaWarp.URL+testCase.Path+"?api_key=abc", nil)
+			req.Header.Set("Accept-Encoding", "gzip, br") // 手动解压，验证改写后重新压缩的响应体完整
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			var reader io.Reader = resp.Body
+			switch resp.Header.Get("Content-Encoding") {
+			case "gzip":
+				if reader, err = gzip.NewReader(resp.Body); err != nil {
+					t.Fatal(err)
+				}
+			case "br":
+				reader = brotli.NewReader(resp.Body)
+			}
+			body, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("读取响应失败：%v", err)
+			}
+			for _, want := range testCase.Want {
+				if !strings.Contains(string(body), want) {
+					t.Errorf("响应缺少 %s，实际: %s", want, body)
+				}
+			}
+			for _, notWant := range testCase.NotWant {
+				if strings.Contains(string(body), notWant) {
+					t.Errorf("响应不应包含 %s，实际: %s", notWant, body)
+				}
+			}
+		})
+	}
+}
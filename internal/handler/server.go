@@ -2,9 +2,16 @@ package handler
 
 import (
 	"MediaWarp/constants"
+	"MediaWarp/internal/cache"
 	"MediaWarp/internal/config"
+	"MediaWarp/utils"
 	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"regexp"
+	"strings"
+	"sync/atomic"
 )
 
 // 媒体服务器处理接口
@@ -13,22 +20,232 @@ type MediaServerHandler interface {
 	GetRegexpRouteRules() []RegexpRouteRule          // 获取正则路由表
 }
 
-var mediaServerHandler MediaServerHandler
+// 上游媒体服务器
+type upstream struct {
+	setting config.MediaServerSetting
+	handler MediaServerHandler
+}
+
+// 上游媒体服务器信息（不包含认证信息）
+type UpstreamInfo struct {
+	Name       string
+	Type       constants.MediaServerType
+	ADDR       string
+	Hosts      []string `json:",omitempty"`
+	PathPrefix string   `json:",omitempty"`
+}
+
+// 路由状态
+//
+// 热重载时整体替换，请求处理中读取的上游和自定义路由规则始终来自同一份配置
+type routeState struct {
+	upstreams        []upstream
+	customRules      []*customRule
+	customRouteRules []RegexpRouteRule
+}
+
+var state atomic.Pointer[routeState]
 var ErrInvalidMediaServerType = errors.New("错误的媒体服务器类型")
 
 // 初始化媒体服务器处理器
+//
+// 为每个上游媒体服务器创建处理器，第一个上游作为默认上游；
+// 热重载时重新创建处理器和自定义路由规则，沿用同名上游处理器持有的缓存，并关闭不再使用的缓存
 func Init() error {
-	var err error
-	switch config.MediaServer.Type {
+	previous := state.Load()
+	newState := &routeState{upstreams: make([]upstream, 0, len(config.MediaServers()))}
+	for _, setting := range config.MediaServers() {
+		namespace := "" // 只有一个上游时不使用缓存命名空间
+		if len(config.MediaServers()) > 1 {
+			namespace = setting.Name
+		}
+		serverHandler, err := newMediaServerHandler(setting, namespace, previous.handler(setting))
+		if err != nil {
+			newState.releaseCaches(previous)
+			return err
+		}
+		newState.upstreams = append(newState.upstreams, upstream{setting: setting, handler: serverHandler})
+	}
+	if len(newState.upstreams) == 0 {
+		return ErrInvalidMediaServerType
+	}
+	rules, err := compileCustomRules(config.Rules())
+	if err != nil {
+		newState.releaseCaches(previous)
+		return err
+	}
+
+	newState.customRules = rules
+	for _, rule := range rules {
+		newState.customRouteRules = append(newState.customRouteRules, RegexpRouteRule{Regexp: rule.regexp, Handler: rule.handle})
+	}
+	state.Store(newState)
+	previous.releaseCaches(newState)
+	resetFontLibrary()     // 热重载后重新扫描字体目录
+	restartLinkRefresher() // 后台刷新即将过期的下载链接
+	return nil
+}
+
+// 获取同名同类型上游的处理器，不存在时返回 nil
+func (s *routeState) handler(setting config.MediaServerSetting) MediaServerHandler {
+	if s == nil {
+		return nil
+	}
+	for _, u := range s.upstreams {
+		if u.setting.Name == setting.Name && u.setting.Type == setting.Type {
+			return u.handler
+		}
+	}
+	return nil
+}
+
+// 关闭不再使用的缓存
+//
+// 停止 keep 中的处理器未沿用的 Part 缓存的清理协程
+func (s *routeState) releaseCaches(keep *routeState) {
+	if s == nil {
+		return
+	}
+	for _, u := range s.upstreams {
+		plexServerHandler, ok := u.handler.(*PlexServerHandler)
+		if !ok || keep.usesCache(plexServerHandler.partCache) {
+			continue
+		}
+		plexServerHandler.partCache.Close()
+	}
+}
+
+// 是否有处理器使用该 Part 缓存
+func (s *routeState) usesCache(partCache *cache.SafeCache) bool {
+	if s == nil {
+		return false
+	}
+	for _, u := range s.upstreams {
+		if plexServerHandler, ok := u.handler.(*PlexServerHandler); ok && plexServerHandler.partCache == partCache {
+			return true
+		}
+	}
+	return false
+}
+
+// 根据媒体服务器类型创建处理器
+//
+// previous 为热重载前同名上游的处理器，地址未变化时沿用其 Part 缓存
+func newMediaServerHandler(setting config.MediaServerSetting, namespace string, previous MediaServerHandler) (MediaServerHandler, error) {
+	switch setting.Type {
 	case constants.EMBY:
-		mediaServerHandler, err = NewEmbyServerHandler(config.MediaServer.ADDR, config.MediaServer.AUTH)
+		embyServerHandler, err := NewEmbyServerHandler(setting.ADDR, setting.AUTH)
+		if err != nil {
+			return nil, err
+		}
+		embyServerHandler.cacheNamespace = namespace
+		return embyServerHandler, nil
+	case constants.JELLYFIN:
+		jellyfinServerHandler, err := NewJellyfinServerHandler(setting.ADDR, setting.AUTH)
+		if err != nil {
+			return nil, err
+		}
+		jellyfinServerHandler.cacheNamespace = namespace
+		return jellyfinServerHandler, nil
+	case constants.PLEX: // Part 缓存由每个处理器单独持有
+		var partCache *cache.SafeCache
+		if previousHandler, ok := previous.(*PlexServerHandler); ok && previousHandler.server.GetEndpoint() == utils.GetEndpoint(setting.ADDR) {
+			partCache = previousHandler.partCache
+		}
+		return newPlexServerHandler(setting.ADDR, setting.AUTH, partCache)
 	default:
-		err = ErrInvalidMediaServerType
+		return nil, ErrInvalidMediaServerType
 	}
-	return err
 }
 
 // 获取媒体服务器接口
+//
+// 返回默认上游的处理器
 func GetMediaServer() MediaServerHandler {
-	return mediaServerHandler
+	if current := state.Load(); current != nil {
+		return current.upstreams[0].handler
+	}
+	return nil
+}
+
+// Web 客户端路径
+//
+// Web 客户端使用绝对路径（/web/...、/emby/...）加载资源和请求接口，无法保持路径前缀，路径前缀路由仅适用于 API 客户端
+var webClientPathRegexp = regexp.MustCompile(`(?i)^(/emby)?/web(/|$)`)
+
+// 根据请求获取对应的媒体服务器接口
+//
+// 优先按 Host 请求头匹配，其次按路径前缀匹配（匹配后会去除请求路径中的前缀），均未匹配时返回默认上游；
+// 通过路径前缀访问 Web 客户端时返回错误
+func GetMediaServerForRequest(req *http.Request) (MediaServerHandler, error) {
+	u, err := matchUpstream(req)
+	if err != nil || u == nil {
+		return nil, err
+	}
+	return u.handler, nil
+}
+
+// 根据请求匹配上游媒体服务器，未初始化时返回 nil
+func matchUpstream(req *http.Request) (*upstream, error) {
+	current := state.Load()
+	if current == nil {
+		return nil, nil
+	}
+	upstreams := current.upstreams
+	if len(upstreams) == 1 {
+		return &upstreams[0], nil
+	}
+
+	host := req.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	for i, u := range upstreams {
+		for _, h := range u.setting.Hosts {
+			if strings.EqualFold(h, host) || strings.EqualFold(h, req.Host) {
+				return &upstreams[i], nil
+			}
+		}
+	}
+
+	for i, u := range upstreams {
+		prefix := u.setting.PathPrefix
+		if prefix == "" {
+			continue
+		}
+		if req.URL.Path == prefix || strings.HasPrefix(req.URL.Path, prefix+"/") {
+			path := strings.TrimPrefix(req.URL.Path, prefix)
+			if path == "" {
+				path = "/"
+			}
+			if webClientPathRegexp.MatchString(path) {
+				return nil, fmt.Errorf("上游 %s 的路径前缀 %s 仅支持 API 客户端，Web 客户端请使用 Hosts 路由", u.setting.Name, prefix)
+			}
+			req.URL.Path = path
+			if req.URL.RawPath != "" {
+				req.URL.RawPath = strings.TrimPrefix(req.URL.RawPath, prefix)
+			}
+			return &upstreams[i], nil
+		}
+	}
+	return &upstreams[0], nil
+}
+
+// 获取全部上游媒体服务器信息
+func GetUpstreams() []UpstreamInfo {
+	current := state.Load()
+	if current == nil {
+		return nil
+	}
+	infos := make([]UpstreamInfo, 0, len(current.upstreams))
+	for _, u := range current.upstreams {
+		infos = append(infos, UpstreamInfo{
+			Name:       u.setting.Name,
+			Type:       u.setting.Type,
+			ADDR:       u.setting.ADDR,
+			Hosts:      u.setting.Hosts,
+			PathPrefix: u.setting.PathPrefix,
+		})
+	}
+	return infos
 }
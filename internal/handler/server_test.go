@@ -0,0 +1,119 @@
+package handler_test
+
+import (
+	"MediaWarp/constants"
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/handler"
+	"fmt"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestGetMediaServerForRequest(t *testing.T) {
+	defer config.Update(func(s *config.Snapshot) {
+		s.MediaServers = []config.MediaServerSetting{
+			{Name: "4K", Type: constants.EMBY, ADDR: "127.0.0.1:8096", Hosts: []string{"4k.example.com"}},
+			{Name: "1080P", Type: constants.EMBY, ADDR: "127.0.0.1:8097", PathPrefix: "/1080p"},
+		}
+	})()
+	if err := handler.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	servers := map[string]handler.MediaServerHandler{}
+	for _, target := range []string{"http://4k.example.com:9096/emby/Items/1/PlaybackInfo", "http://mediawarp:9096/1080p/emby/Items/1/PlaybackInfo", "http://mediawarp:9096/emby/Items/1/PlaybackInfo"} {
+		server, err := handler.GetMediaServerForRequest(httptest.NewRequest("GET", target, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		servers[target] = server
+	}
+
+	if servers["http://4k.example.com:9096/emby/Items/1/PlaybackInfo"] != handler.GetMediaServer() {
+		t.Error("Host 匹配的请求应路由至 4K 上游")
+	}
+	if servers["http://mediawarp:9096/1080p/emby/Items/1/PlaybackInfo"] == handler.GetMediaServer() {
+		t.Error("路径前缀匹配的请求应路由至 1080P 上游")
+	}
+	if servers["http://mediawarp:9096/emby/Items/1/PlaybackInfo"] != handler.GetMediaServer() {
+		t.Error("未匹配的请求应路由至默认上游")
+	}
+
+	req := httptest.NewRequest("GET", "http://mediawarp:9096/1080p/emby/Items/1/PlaybackInfo", nil)
+	handler.GetMediaServerForRequest(req)
+	if req.URL.Path != "/emby/Items/1/PlaybackInfo" {
+		t.Errorf("路径前缀未去除：%s", req.URL.Path)
+	}
+
+	for _, target := range []string{"http://mediawarp:9096/1080p/web/index.html", "http://mediawarp:9096/1080p/emby/web/index.html"} {
+		if _, err := handler.GetMediaServerForRequest(httptest.NewRequest("GET", target, nil)); err == nil {
+			t.Errorf("通过路径前缀访问 Web 客户端时应返回错误：%s", target)
+		}
+	}
+
+	upstreams := handler.GetUpstreams()
+	if len(upstreams) != 2 || upstreams[1].Name != "1080P" {
+		t.Errorf("上游列表错误：%+v", upstreams)
+	}
+}
+
+func TestInitReleasesPartCaches(t *testing.T) {
+	defer config.Update(func(s *config.Snapshot) {
+		s.MediaServers = []config.MediaServerSetting{{Name: "plex", Type: constants.PLEX, ADDR: "127.0.0.1:32400"}}
+	})()
+	if err := handler.Init(); err != nil {
+		t.Fatal(err)
+	}
+	baseline := runtime.NumGoroutine()
+
+	for i := range 10 {
+		config.Update(func(s *config.Snapshot) {
+			s.MediaServers = []config.MediaServerSetting{{Name: "plex", Type: constants.PLEX, ADDR: fmt.Sprintf("127.0.0.1:%d", 32400+i%2)}}
+		})
+		if err := handler.Init(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !waitGoroutines(baseline) {
+		t.Errorf("热重载后未关闭不再使用的 Part 缓存：%d -> %d 个协程", baseline, runtime.NumGoroutine())
+	}
+}
+
+func TestLinkRefresherRestart(t *testing.T) {
+	defer config.Update(func(s *config.Snapshot) {
+		s.MediaServers = []config.MediaServerSetting{{Name: "plex", Type: constants.PLEX, ADDR: "127.0.0.1:32400"}}
+		s.Cache.RefreshLinks = false
+	})()
+	if err := handler.Init(); err != nil {
+		t.Fatal(err)
+	}
+	baseline := runtime.NumGoroutine()
+
+	config.Update(func(s *config.Snapshot) { s.Cache.RefreshLinks = true })
+	for range 5 {
+		if err := handler.Init(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !waitGoroutines(baseline + 1) {
+		t.Errorf("多次初始化后应只有一个后台刷新协程：%d -> %d 个协程", baseline, runtime.NumGoroutine())
+	}
+
+	handler.StopLinkRefresher()
+	if !waitGoroutines(baseline) {
+		t.Errorf("停止后台刷新后协程未退出：%d -> %d 个协程", baseline, runtime.NumGoroutine())
+	}
+}
+
+// 等待协程数降至 limit 以下
+func waitGoroutines(limit int) bool {
+	for range 100 {
+		if runtime.NumGoroutine() <= limit {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"MediaWarp/constants"
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/logging"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 代理推流时链接失效的最大刷新次数
+const maxStreamRefresh = 3
+
+var (
+	deviceIDRegexp = regexp.MustCompile(`(?i)DeviceId="([^"]*)"`)
+
+	streamClient = &http.Client{
+		Transport: &http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			ResponseHeaderTimeout: 30 * time.Second,
+			IdleConnTimeout:       90 * time.Second,
+		},
+	}
+
+	// 透传给客户端的响应头
+	streamResponseHeaders = []string{"Content-Type", "Content-Length", "Content-Range", "Accept-Ranges", "ETag", "Last-Modified", "Content-Disposition"}
+)
+
+// 刷新下载链接
+//
+// 为 nil 时表示链接无法刷新
+type linkRefresher func() (string, error)
+
+// 获取请求的设备 ID
+func getDeviceID(req *http.Request) string {
+	for _, header := range []string{"X-Emby-Device-Id", "X-Plex-Client-Identifier"} {
+		if deviceID := req.Header.Get(header); deviceID != "" {
+			return deviceID
+		}
+	}
+	for key, values := range req.URL.Query() {
+		if strings.EqualFold(key, "DeviceId") && len(values) > 0 {
+			return values[0]
+		}
+	}
+	for _, header := range []string{"X-Emby-Authorization", "Authorization"} {
+		if matches := deviceIDRegexp.FindStringSubmatch(req.Header.Get(header)); len(matches) == 2 {
+			return matches[1]
+		}
+	}
+	return ""
+}
+
+// 判断客户端是否使用代理推流
+func useProxyStream(req *http.Request) bool {
+	if !config.ProxyStream().Enable {
+		return false
+	}
+	if userAgent := req.UserAgent(); userAgent != "" {
+		for _, ua := range config.ProxyStream().ClientList {
+			if strings.Contains(userAgent, ua) {
+				return true
+			}
+		}
+	}
+	if deviceID := getDeviceID(req); deviceID != "" {
+		for _, id := range config.ProxyStream().DeviceList {
+			if deviceID == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// 重定向至下载链接或代理推流
+func serveStream(ctx *gin.Context, redirectURL string, refresh linkRefresher) {
+	if !useProxyStream(ctx.Request) {
+		ctx.Set(constants.StreamModeKey, constants.RedirectStream)
+		ctx.Redirect(http.StatusFound, redirectURL)
+		return
+	}
+
+	ctx.Set(constants.StreamModeKey, constants.ProxyStream)
+	logging.Info("代理推流：", redirectURL)
+	if err := proxyStream(ctx.Writer, ctx.Request, redirectURL, refresh); err != nil {
+		logging.Warning("代理推流失败：", err)
+	}
+}
+
+// 请求下载链接
+func requestStream(req *http.Request, streamURL string, rangeHeader string, ifRange string) (*http.Response, error) {
+	upstreamReq, err := http.NewRequestWithContext(req.Context(), req.Method, streamURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	upstreamReq.Header.Set("User-Agent", req.UserAgent()) // 部分网盘的下载链接与 User-Agent 绑定
+	upstreamReq.Header.Set("Accept-Encoding", "identity")
+	if rangeHeader != "" {
+		upstreamReq.Header.Set("Range", rangeHeader)
+		if ifRange != "" {
+			upstreamReq.Header.Set("If-Range", ifRange)
+		}
+	}
+	return streamClient.Do(upstreamReq)
+}
+
+// 判断下载链接是否失效
+func isStreamExpired(statusCode int) bool {
+	return statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden || statusCode == http.StatusGone || statusCode == http.StatusNotFound
+}
+
+// 解析 Content-Range 响应头
+//
+// bytes 0-99/1000 -> 0, 99
+func parseContentRange(contentRange string) (int64, int64, bool) {
+	spec, ok := strings.CutPrefix(contentRange, "bytes ")
+	if !ok {
+		return 0, 0, false
+	}
+	spec, _, _ = strings.Cut(spec, "/")
+	startStr, endStr, ok := strings.Cut(spec, "-")
+	if !ok {
+		return 0, 0, false
+	}
+	start, err1 := strconv.ParseInt(startStr, 10, 64)
+	end, err2 := strconv.ParseInt(endStr, 10, 64)
+	if err1 != nil || err2 != nil || start > end {
+		return 0, 0, false
+	}
+	return start, end, true
+}
+
+// 代理推流
+//
+// 透传 Range、If-Range 请求头和 206 响应
+// 下载链接失效时（包括推流过程中断开）刷新链接并从断点继续推流
+func proxyStream(rw http.ResponseWriter, req *http.Request, streamURL string, refresh linkRefresher) error {
+	rangeHeader := req.Header.Get("Range")
+	ifRange := req.Header.Get("If-Range")
+
+	resp, err := requestStream(req, streamURL, rangeHeader, ifRange)
+	for refreshCount := 0; refresh != nil && refreshCount < maxStreamRefresh && (err != nil || isStreamExpired(resp.StatusCode)); refreshCount++ {
+		if err == nil {
+			resp.Body.Close()
+			logging.Info("下载链接已失效，状态码：", resp.StatusCode, "，刷新链接")
+		} else {
+			logging.Info("请求下载链接失败：", err, "，刷新链接")
+		}
+		if streamURL, err = refresh(); err != nil {
+			break
+		}
+		resp, err = requestStream(req, streamURL, rangeHeader, ifRange)
+	}
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadGateway)
+		return err
+	}
+	defer resp.Body.Close()
+
+	for _, header := range streamResponseHeaders {
+		if value := resp.Header.Get(header); value != "" {
+			rw.Header().Set(header, value)
+		}
+	}
+	rw.WriteHeader(resp.StatusCode)
+	if req.Method == http.MethodHead || (resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent) {
+		_, err = io.Copy(rw, resp.Body)
+		return err
+	}
+
+	// 计算本次响应的字节范围，用于断点续传
+	var start, end int64 = 0, resp.ContentLength - 1
+	if resp.StatusCode == http.StatusPartialContent {
+		var ok bool
+		if start, end, ok = parseContentRange(resp.Header.Get("Content-Range")); !ok {
+			_, err = io.Copy(rw, resp.Body) // 多段 Range 等无法续传的情况
+			return err
+		}
+	}
+	validator := resp.Header.Get("ETag") // 续传时确保文件未发生变化
+	if validator == "" || strings.HasPrefix(validator, "W/") {
+		validator = resp.Header.Get("Last-Modified")
+	}
+
+	body := resp.Body
+	for refreshCount := 0; ; refreshCount++ {
+		written, copyErr := io.Copy(rw, body)
+		start += written
+		body.Close()
+		if copyErr == nil && (end < 0 || start > end) { // 推流完成（未知长度时以 EOF 为准）
+			return nil
+		}
+		if req.Context().Err() != nil { // 客户端断开连接
+			return req.Context().Err()
+		}
+		if copyErr == nil {
+			copyErr = io.ErrUnexpectedEOF
+		}
+		if refresh == nil || end < 0 || refreshCount >= maxStreamRefresh {
+			return copyErr
+		}
+
+		logging.Info("推流中断：", copyErr, "，刷新链接后从 ", start, " 字节继续推流")
+		newURL, err := refresh()
+		if err != nil {
+			return errors.Join(copyErr, err)
+		}
+		resumeResp, err := requestStream(req, newURL, fmt.Sprintf("bytes=%d-%d", start, end), validator)
+		if err != nil {
+			return errors.Join(copyErr, err)
+		}
+		if resumeStart, _, ok := parseContentRange(resumeResp.Header.Get("Content-Range")); resumeResp.StatusCode != http.StatusPartialContent || !ok || resumeStart != start {
+			resumeResp.Body.Close()
+			return fmt.Errorf("续传失败，状态码：%d，Content-Range：%s", resumeResp.StatusCode, resumeResp.Header.Get("Content-Range"))
+		}
+		body = resumeResp.Body
+	}
+}
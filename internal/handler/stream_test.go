@@ -0,0 +1,175 @@
+package handler_test
+
+import (
+	"MediaWarp/constants"
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/resolver"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// 每次解析返回一个新的下载链接
+type tokenResolver struct {
+	cdnURL string
+	count  atomic.Int32
+}
+
+func (r *tokenResolver) Name() string {
+	return "token"
+}
+
+func (r *tokenResolver) Resolve(ctx context.Context, link string, userAgent string) (string, error) {
+	return fmt.Sprintf("%s/movie.mkv?token=%d", r.cdnURL, r.count.Add(1)), nil
+}
+
+// 写入指定字节数后中断连接
+type abortWriter struct {
+	http.ResponseWriter
+	remain int
+}
+
+func (w *abortWriter) Write(p []byte) (int, error) {
+	if len(p) > w.remain {
+		w.ResponseWriter.Write(p[:w.remain])
+		w.ResponseWriter.(http.Flusher).Flush()
+		panic(http.ErrAbortHandler)
+	}
+	w.remain -= len(p)
+	return w.ResponseWriter.Write(p)
+}
+
+func TestProxyStream(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 10000)
+	modTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var aborted atomic.Bool
+	cdn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.UserAgent() != "OldTV/1.0" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("ETag", `"movie-v1"`)
+		if r.Header.Get("Range") == "" && !aborted.Swap(true) { // 第一次完整请求在推流过程中中断
+			http.ServeContent(&abortWriter{ResponseWriter: w, remain: 4096}, r, "movie.mkv", modTime, bytes.NewReader(content))
+			return
+		}
+		http.ServeContent(w, r, "movie.mkv", modTime, bytes.NewReader(content))
+	}))
+	defer cdn.Close()
+
+	tokenResolver := &tokenResolver{cdnURL: cdn.URL}
+	resolver.Register("token", tokenResolver)
+
+	localPath := t.TempDir()
+	strmPath := filepath.Join(localPath, "Movie.strm")
+	if err := os.WriteFile(strmPath, []byte("token://movie.mkv"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mediaWarp, client := startMediaWarp(t, constants.PLEX, plexItemStub("1", strmPath), func(s *config.Snapshot) {
+		s.MediaSync = config.MediaSyncSetting{{Name: "token", Remote: "token:", LocalPath: localPath}}
+		s.ProxyStream = config.ProxyStreamSetting{Enable: true, ClientList: []string{"OldTV"}, DeviceList: []string{"tv-device"}}
+	})
+	client.Get(mediaWarp.URL + "/video/:/transcode/universal/decision?path=/library/metadata/1")
+
+	request := func(userAgent string, header map[string]string) (*http.Response, []byte) {
+		req, _ := http.NewRequest(http.MethodGet, mediaWarp.URL+"/library/parts/1/1/file.mkv", nil)
+		req.Header.Set("User-Agent", userAgent)
+		for key, value := range header {
+			req.Header.Set(key, value)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp, body
+	}
+
+	t.Run("Redirect", func(t *testing.T) {
+		resp, _ := request("Infuse/7.0", nil)
+		if resp.StatusCode != http.StatusFound || !strings.HasPrefix(resp.Header.Get("Location"), cdn.URL) {
+			t.Errorf("未匹配的客户端应重定向，状态码：%d", resp.StatusCode)
+		}
+	})
+
+	t.Run("RefreshMidStream", func(t *testing.T) { // 第一个链接中断后刷新链接续传
+		resolveCount := tokenResolver.count.Load()
+		resp, body := request("OldTV/1.0", nil)
+		if resp.StatusCode != http.StatusOK || !bytes.Equal(body, content) {
+			t.Errorf("代理推流内容错误，状态码：%d，长度：%d", resp.StatusCode, len(body))
+		}
+		if !aborted.Load() || tokenResolver.count.Load()-resolveCount != 2 {
+			t.Errorf("推流中断后应刷新链接，解析次数：%d", tokenResolver.count.Load()-resolveCount)
+		}
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		resp, body := request("OldTV/1.0", map[string]string{"Range": "bytes=10-19"})
+		if resp.StatusCode != http.StatusPartialContent || resp.Header.Get("Content-Range") != fmt.Sprintf("bytes 10-19/%d", len(content)) || string(body) != "0123456789" {
+			t.Errorf("Range 请求错误，状态码：%d，Content-Range：%s，内容：%s", resp.StatusCode, resp.Header.Get("Content-Range"), body)
+		}
+	})
+
+	t.Run("IfRange", func(t *testing.T) {
+		resp, _ := request("OldTV/1.0", map[string]string{"Range": "bytes=10-19", "If-Range": `"movie-v1"`})
+		if resp.StatusCode != http.StatusPartialContent {
+			t.Errorf("If-Range 匹配时应返回 206，实际：%d", resp.StatusCode)
+		}
+		resp, body := request("OldTV/1.0", map[string]string{"Range": "bytes=10-19", "If-Range": `"movie-v0"`})
+		if resp.StatusCode != http.StatusOK || len(body) != len(content) {
+			t.Errorf("If-Range 不匹配时应返回完整内容，状态码：%d，长度：%d", resp.StatusCode, len(body))
+		}
+	})
+
+	t.Run("Emby", func(t *testing.T) { // Emby 的 VideosHandler 同样代理推流
+		mediaWarp, client := startMediaWarp(t, constants.EMBY, embyItemStub("61", strmPath, "token://movie.mkv"), func(s *config.Snapshot) {
+			s.MediaSync = config.MediaSyncSetting{{Name: "token", Remote: "token:", LocalPath: localPath}}
+			s.ProxyStream = config.ProxyStreamSetting{Enable: true, ClientList: []string{"OldTV"}}
+		})
+		for _, testCase := range []struct {
+			UserAgent string
+			Range     string
+			Status    int
+			Want      []byte
+		}{
+			{"Infuse/7.0", "", http.StatusFound, nil},
+			{"OldTV/1.0", "", http.StatusOK, content},
+			{"OldTV/1.0", "bytes=10-19", http.StatusPartialContent, []byte("0123456789")},
+		} {
+			req, _ := http.NewRequest(http.MethodGet, mediaWarp.URL+"/emby/videos/61/stream?MediaSourceId=61&Static=true", nil)
+			req.Header.Set("User-Agent", testCase.UserAgent)
+			if testCase.Range != "" {
+				req.Header.Set("Range", testCase.Range)
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != testCase.Status {
+				t.Errorf("%s %s 状态码错误。期望: %d, 实际: %d", testCase.UserAgent, testCase.Range, testCase.Status, resp.StatusCode)
+			}
+			if testCase.Want == nil {
+				if location := resp.Header.Get("Location"); !strings.HasPrefix(location, cdn.URL) {
+					t.Errorf("未匹配的客户端应重定向：%s", location)
+				}
+			} else if !bytes.Equal(body, testCase.Want) {
+				t.Errorf("%s %s 代理推流内容错误，长度：%d", testCase.UserAgent, testCase.Range, len(body))
+			}
+		}
+	})
+}
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"MediaWarp/constants"
+	"MediaWarp/internal/cache"
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/logging"
+	"MediaWarp/internal/resolver"
+	"MediaWarp/internal/service/alist"
+	"MediaWarp/internal/useragent"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 判断 Strm 内容是否为非 HTTP 协议的路径（如 115://xxx、alist://xxx）
+func isRclonePath(path string) bool {
+	return strings.Contains(path, "://") && !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://")
+}
+
+// Strm 内容中实际的链接
+//
+// Strm 内容为 MediaWarp 的 Strm 下载链接接口（/MediaWarp/strm?link=xxx）时返回 link 参数，其余原样返回
+func strmLink(path string) string {
+	if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
+		return path
+	}
+	u, err := url.Parse(path)
+	if err != nil || !constants.EmbyRegexp.Router.StrmEndpoint.MatchString(u.Path) {
+		return path
+	}
+	if link := u.Query().Get("link"); link != "" {
+		return link
+	}
+	return path
+}
+
+// 解析下载链接的超时时间
+const linkResolveTimeout = 30 * time.Second
+
+// 获取 Strm 链接的重定向链接
+//
+// 无需解析的链接直接返回，MediaWarp 的 Strm 下载链接接口使用其 link 参数
+// 优先从缓存（包括预加载缓存）中获取，未命中时调用链接对应的解析器获取真实下载链接并缓存
+func getRedirectURL(path string, userAgent string) (string, error) {
+	path = strmLink(path)
+	if !resolver.NeedResolve(path) {
+		return path, nil
+	}
+
+	logging.Info("🔍 获取下载链接 - User-Agent:", userAgent)
+	logging.Info("🔍 User-Agent长度:", len(userAgent))
+	logging.Info("🔍 User-Agent内容:", fmt.Sprintf("'%s'", userAgent))
+	cacheKey := redirectCacheKey(path, userAgent)
+	logging.Info("🔑 缓存键:", cacheKey)
+
+	// 尝试从缓存获取URL
+	if cachedItem, exists := redirectURLCache.Get(cacheKey); exists {
+		expiryTracker.touch(cacheKey)
+		cachedURL := cachedItem.URL
+		if strings.HasSuffix(cachedURL, "#PRELOADED") {
+			redirectURL := strings.TrimSuffix(cachedURL, "#PRELOADED")
+			logging.Info("🚀 从预加载缓存获取重定向URL：", redirectURL)
+			return redirectURL, nil
+		}
+		logging.Info("✅ 从普通缓存获取重定向URL：", cachedURL)
+		return cachedURL, nil
+	}
+
+	logging.Info("❌ 缓存未命中，使用解析器获取下载链接:", path)
+	redirectURL, err := resolveAndCache(path, userAgent, false)
+	if err != nil {
+		return "", err
+	}
+
+	// 🔍 详细分析下载链接
+	logging.Info("🔗 获取到的下载链接:", redirectURL)
+	if strings.Contains(userAgent, "VidHub") {
+		logging.Info("🎯 VidHub 客户端请求")
+		logging.Info("🔍 链接长度:", len(redirectURL))
+		logging.Info("🔍 链接域名:", extractDomain(redirectURL))
+		logging.Info("🔍 链接参数数量:", countURLParams(redirectURL))
+	}
+	return redirectURL, nil
+}
+
+// 解析下载链接并缓存
+//
+// 合并相同 path|UA 的并发请求（如 Infuse 打开媒体时同时发起的多个串流请求），避免重复请求网盘接口触发限流
+// preloaded 为 true 时标记为预加载缓存；否则不覆盖已有的预加载缓存
+func resolveAndCache(path string, userAgent string, preloaded bool) (string, error) {
+	cacheKey := redirectCacheKey(path, userAgent)
+	value, err := linkGroup.Do(cacheKey, func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), linkResolveTimeout)
+		defer cancel()
+		redirectURL, err := resolver.Resolve(ctx, path, useragent.Normalize(userAgent))
+		if err != nil {
+			return "", err
+		}
+
+		// 缓存结果，缓存时间根据链接的过期时间计算
+		cacheTime := linkCacheTime(redirectURL)
+		if cacheTime <= 0 {
+			logging.Warning("下载链接即将过期，不进行缓存：", redirectURL)
+			return redirectURL, nil
+		}
+		expireTime := time.Now().Add(cacheTime)
+		if preloaded {
+			redirectURLCache.Set(cacheKey, redirectURL+"#PRELOADED", expireTime)
+		} else if existingItem, exists := redirectURLCache.Get(cacheKey); exists && strings.HasSuffix(existingItem.URL, "#PRELOADED") {
+			logging.Info("⚠️ 跳过缓存设置，保留预加载缓存")
+			return redirectURL, nil
+		} else {
+			redirectURLCache.Set(cacheKey, redirectURL, expireTime)
+		}
+		expiryTracker.record(cacheKey, path, userAgent, redirectURL, expireTime, !preloaded)
+		logging.Info("缓存重定向URL，过期时间：", expireTime)
+		return redirectURL, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return value.(string), nil
+}
+
+// 重定向链接的缓存键
+//
+// 与获取下载链接时使用的 User-Agent 对应，规则指定 User-Agent 时同一类别的客户端共用缓存
+func redirectCacheKey(path string, userAgent string) string {
+	return path + "|" + useragent.CacheKey(userAgent)
+}
+
+// 刷新 Strm 链接的重定向链接
+//
+// 删除缓存（包括预加载缓存）后重新解析
+func refreshRedirectURL(path string, userAgent string) (string, error) {
+	redirectURLCache.Delete(redirectCacheKey(strmLink(path), userAgent))
+	return getRedirectURL(path, userAgent)
+}
+
+// AlistStrm 下载链接缓存时间
+const alistLinkCacheTime = 10 * time.Minute
+
+var alistServers sync.Map // Alist 服务器地址 -> *alist.AlistServer
+
+// 获取 AlistStrm 配置
+func getAlistSetting(addr string) (config.AlistSetting, bool) {
+	for _, alistSetting := range config.AlistStrm().List {
+		if alistSetting.ADDR == addr {
+			return alistSetting, true
+		}
+	}
+	return config.AlistSetting{}, false
+}
+
+// 获取 AlistStrm 的重定向链接
+//
+// 优先从 AlistLink 缓存中获取，未命中时请求 /api/fs/get 并缓存
+func getAlistRedirectURL(playbackCache *cache.PlaybackInfoCache, addr string, alistPath string) (string, error) {
+	if cachedLink, found := playbackCache.GetAlistLink(addr + "|" + alistPath); found {
+		logging.Info("AlistLink 缓存命中：", alistPath)
+		return cachedLink.DownloadURL, nil
+	}
+	return refreshAlistRedirectURL(playbackCache, addr, alistPath)
+}
+
+// 重新获取 AlistStrm 的重定向链接并更新缓存
+//
+// 用于代理串流时链接过期后刷新
+func refreshAlistRedirectURL(playbackCache *cache.PlaybackInfoCache, addr string, alistPath string) (string, error) {
+	alistSetting, ok := getAlistSetting(addr)
+	if !ok {
+		return "", fmt.Errorf("未找到 Alist 服务器配置：%s", addr)
+	}
+
+	value, _ := alistServers.LoadOrStore(addr, alist.New(alistSetting.ADDR, alistSetting.Token))
+	alistServer := value.(*alist.AlistServer)
+	ctx, cancel := context.WithTimeout(context.Background(), linkResolveTimeout)
+	defer cancel()
+	redirectURL, fsGetData, err := alistServer.GetDownloadURL(ctx, alistPath, alistSetting.RawURL)
+	if err != nil {
+		return "", err
+	}
+	playbackCache.SetAlistLink(addr+"|"+alistPath, redirectURL, fsGetData.Sign, fsGetData.RawURL, alistLinkCacheTime)
+	return redirectURL, nil
+}
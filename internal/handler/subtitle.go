@@ -0,0 +1,302 @@
+package handler
+
+import (
+	"MediaWarp/constants"
+	"MediaWarp/internal/cache"
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/font"
+	"MediaWarp/internal/logging"
+	"MediaWarp/internal/opencc"
+	"MediaWarp/internal/subtitle"
+	"MediaWarp/internal/useragent"
+	"MediaWarp/utils"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	subtitleCacheBytes = 64 << 20    // 繁简转换、字体子集化后的字幕缓存的总字节数上限（各自）
+	fontCheckInterval  = time.Minute // 检查字体目录是否变化的间隔
+)
+
+var (
+	subsetCache  = cache.NewLRUCache(subtitleCacheBytes) // 字幕哈希 -> 内嵌子集字体后的字幕
+	convertCache = cache.NewLRUCache(subtitleCacheBytes) // 字幕哈希:转换模式 -> 繁简转换后的字幕
+
+	fontLibraryMutex   sync.Mutex
+	fontLibrary        *font.Library
+	fontLibraryDir     string    // 字体库对应的字体目录
+	fontLibraryModTime time.Time // 加载字体库时字体目录（含子目录）的最近修改时间
+	fontLibraryChecked time.Time // 上次检查字体目录的时间
+)
+
+// 修改字幕响应
+//
+// Emby、Jellyfin 共用的字幕处理逻辑：将字幕转为 UTF-8 编码后按 processSubtitles 处理
+func modifySubtitles(rw *http.Response, subtitile []byte, mode constants.ChineseConvertMode) []byte {
+	if config.Subtitle().ToUTF8 {
+		var charset string
+		if subtitile, charset = subtitleToUTF8(subtitile); charset != "" && charset != subtitle.CharsetUTF8 {
+			setUTF8Charset(rw)
+		}
+	}
+	return processSubtitles(subtitile, rw.Request.URL.Path, mode)
+}
+
+// 将字幕转为 UTF-8 编码，失败时返回原字幕
+func subtitleToUTF8(content []byte) ([]byte, string) {
+	converted, charset, err := subtitle.ToUTF8(content)
+	if err != nil {
+		logging.Warning("字幕编码转换失败，返回原字幕：", err)
+		return content, ""
+	}
+	if charset != subtitle.CharsetUTF8 {
+		logging.Infof("已将 %s 编码的字幕转为 UTF-8", charset)
+	}
+	return converted, charset
+}
+
+// 处理 UTF-8 编码的字幕
+//
+// 依次进行繁简转换、按请求的扩展名转换字幕格式、调整时间轴、ASS 字幕字体子集化并内嵌；
+// SRT 字幕仅在请求 .ass 且启用 SRT2ASS 时转为 ASS
+func processSubtitles(subtitile []byte, requestPath string, mode constants.ChineseConvertMode) []byte {
+	if mode != "" && mode != constants.ChineseConvertNone {
+		subtitile = convertChinese(subtitile, mode)
+	}
+
+	source := subtitle.Detect(subtitile)
+	target := subtitle.FormatFromExt(path.Ext(requestPath))
+	if source == subtitle.FormatSRT && target == subtitle.FormatASS && !config.Subtitle().SRT2ASS {
+		logging.Debug("未启用 SRT2ASS，不转换 SRT 字幕")
+	} else if source != subtitle.FormatUnknown && target != subtitle.FormatUnknown && source != target {
+		if converted, err := subtitle.Convert(subtitile, target, config.Subtitle().ASSStyle); err != nil {
+			logging.Warningf("字幕 %s 转 %s 失败，返回原字幕：%v", source, target, err)
+		} else {
+			logging.Infof("已将 %s 字幕转为 %s 格式", source, target)
+			subtitile = converted
+		}
+	}
+	subtitile = retimeSubtitles(requestPath, subtitile)
+	if config.Subtitle().SubSet && bytes.Contains(subtitile, []byte("[Script Info]")) {
+		subtitile = embedSubsetFonts(subtitile)
+	}
+	return subtitile
+}
+
+// 将响应的 Content-Type 中的字符集设置为 UTF-8
+func setUTF8Charset(rw *http.Response) {
+	mediaType, params, err := mime.ParseMediaType(rw.Header.Get("Content-Type"))
+	if err != nil {
+		return
+	}
+	params["charset"] = "utf-8"
+	rw.Header.Set("Content-Type", mime.FormatMediaType(mediaType, params))
+}
+
+// 获取字幕的繁简转换模式
+//
+// 按顺序匹配 ChineseConvert.Rules，均未匹配时使用默认模式；users 为请求用户的 ID 和名称，仅在规则设置了 Users 时获取
+func chineseConvertMode(req *http.Request, users func() []string) constants.ChineseConvertMode {
+	setting := config.Subtitle().ChineseConvert
+	if len(setting.Rules) == 0 {
+		return setting.Mode
+	}
+
+	var (
+		userAgent = req.Header.Get("User-Agent")
+		client    = useragent.Classify(userAgent)
+		userNames []string
+		userOnce  sync.Once
+	)
+	for _, rule := range setting.Rules {
+		if len(rule.Clients) > 0 && !slices.ContainsFunc(rule.Clients, func(name string) bool {
+			return strings.EqualFold(name, client.Class) || strings.Contains(strings.ToLower(userAgent), strings.ToLower(name))
+		}) {
+			continue
+		}
+		if len(rule.Users) > 0 {
+			if users == nil {
+				continue
+			}
+			userOnce.Do(func() { userNames = users() })
+			if !slices.ContainsFunc(rule.Users, func(name string) bool {
+				return slices.ContainsFunc(userNames, func(user string) bool { return strings.EqualFold(name, user) })
+			}) {
+				continue
+			}
+		}
+		return rule.Mode
+	}
+	return setting.Mode
+}
+
+// 字幕繁简转换
+//
+// 结果按字幕内容的哈希和转换模式缓存，转换失败时返回原字幕
+func convertChinese(content []byte, mode constants.ChineseConvertMode) []byte {
+	hash := sha256.Sum256(content)
+	cacheKey := hex.EncodeToString(hash[:]) + ":" + string(mode)
+	if cached, ok := convertCache.Get(cacheKey); ok {
+		logging.Debug("字幕繁简转换缓存命中：", cacheKey)
+		return cached
+	}
+
+	converter, err := opencc.Get(mode)
+	if err != nil {
+		logging.Warning("字幕繁简转换失败，返回原字幕：", err)
+		return content
+	}
+	result := subtitle.MapText(content, converter.Convert)
+	convertCache.Set(cacheKey, result)
+	logging.Infof("已按 %s 模式完成字幕繁简转换", mode)
+	return result
+}
+
+// 获取字体库
+//
+// 首次使用、字体目录配置变化或字体目录中的文件变化（每 fontCheckInterval 检查一次）时重新扫描，
+// 并清空字体子集化缓存
+func getFontLibrary() *font.Library {
+	fontLibraryMutex.Lock()
+	defer fontLibraryMutex.Unlock()
+
+	dir := config.FontDir()
+	if fontLibrary != nil && dir == fontLibraryDir && time.Since(fontLibraryChecked) < fontCheckInterval {
+		return fontLibrary
+	}
+	fontLibraryChecked = time.Now()
+	modTime := fontDirModTime(dir)
+	if fontLibrary != nil && dir == fontLibraryDir && modTime.Equal(fontLibraryModTime) {
+		return fontLibrary
+	}
+
+	library := font.NewLibrary(dir)
+	if err := library.Load(); err != nil {
+		logging.Warning("加载字体目录失败：", err)
+	}
+	fontLibrary, fontLibraryDir, fontLibraryModTime = library, dir, modTime
+	subsetCache.Clear()
+	return fontLibrary
+}
+
+// 重置字体库，下次使用时重新扫描字体目录
+func resetFontLibrary() {
+	fontLibraryMutex.Lock()
+	defer fontLibraryMutex.Unlock()
+	fontLibrary = nil
+}
+
+// 字体目录及其子目录的最近修改时间
+//
+// 添加、删除字体文件会更新所在目录的修改时间
+func fontDirModTime(dir string) time.Time {
+	var latest time.Time
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+		return nil
+	})
+	return latest
+}
+
+// 子集化 ASS 字幕使用的字体并内嵌到字幕中
+//
+// 结果按字幕内容的哈希缓存
+func embedSubsetFonts(assText []byte) []byte {
+	hash := sha256.Sum256(assText)
+	cacheKey := hex.EncodeToString(hash[:])
+	if cached, ok := subsetCache.Get(cacheKey); ok {
+		logging.Debug("字体子集化缓存命中：", cacheKey)
+		return cached
+	}
+
+	result := []byte(subsetFonts(string(assText)))
+	subsetCache.Set(cacheKey, result)
+	return result
+}
+
+// 子集化的字体
+type subsetFace struct {
+	face  font.Face
+	name  string // ASS 字幕中的字体名称
+	runes utils.SetInterface[rune]
+}
+
+// 子集化 ASS 字幕使用的字体
+//
+// 已内嵌字体、解析失败或未找到字体时返回原字幕；不同样式匹配到同一字体时合并字符集
+func subsetFonts(assText string) string {
+	if font.HasEmbeddedFonts(assText) {
+		logging.Debug("ASS 字幕已内嵌字体，跳过字体子集化")
+		return assText
+	}
+	fontSets, err := utils.AnalyseASS(assText)
+	if err != nil {
+		logging.Warning("分析 ASS 字幕字体失败：", err)
+		return assText
+	}
+
+	styles := make([]utils.ASSFontStyle, 0, len(fontSets))
+	for style := range fontSets {
+		styles = append(styles, style)
+	}
+	sort.Slice(styles, func(i, j int) bool {
+		if styles[i].Name != styles[j].Name {
+			return styles[i].Name < styles[j].Name
+		}
+		if styles[i].Weight != styles[j].Weight {
+			return styles[i].Weight < styles[j].Weight
+		}
+		return !styles[i].Italic && styles[j].Italic
+	})
+
+	library := getFontLibrary()
+	var faces []*subsetFace
+	merged := make(map[string]*subsetFace) // 字体文件路径和序号 -> 子集化的字体
+	for _, style := range styles {
+		face, ok := library.Match(style.Name, style.Weight, style.Italic)
+		if !ok {
+			logging.Warningf("字体目录中未找到字体：%s（字重 %d，斜体 %t）", style.Name, style.Weight, style.Italic)
+			continue
+		}
+		if !face.Glyf {
+			logging.Warningf("字体 %s 为 CFF 轮廓字体，不支持子集化：%s", style.Name, face.Path)
+			continue
+		}
+		key := fmt.Sprintf("%s#%d", face.Path, face.Index)
+		if merged[key] == nil {
+			merged[key] = &subsetFace{face: face, name: strings.TrimSpace(style.Name), runes: utils.NewSet[rune]()}
+			faces = append(faces, merged[key])
+		}
+		merged[key].runes.Adds(fontSets[style].Values()...)
+	}
+
+	fonts := make([]font.EmbeddedFont, 0, len(faces))
+	for index, face := range faces {
+		data, err := face.face.Subset(face.runes.Values())
+		if err != nil {
+			logging.Warningf("字体 %s 子集化失败：%v", face.face.Path, err)
+			continue
+		}
+		fonts = append(fonts, font.EmbeddedFont{Name: fmt.Sprintf("%s_%d.ttf", face.name, index), Data: data})
+		logging.Infof("字体 %s 子集化完成，字符数：%d，大小：%d 字节", face.name, face.runes.Len(), len(data))
+	}
+	return font.EmbedFonts(assText, fonts)
+}
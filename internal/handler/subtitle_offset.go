@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/logging"
+	"MediaWarp/internal/subtitle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 从字幕请求路径中提取媒体项 ID、媒体源 ID 和字幕流序号
+var subtitleStreamRegexp = regexp.MustCompile(`(?i)/Videos/([^/]+)/([^/]+)/Subtitles/(\d+)/`)
+
+// 最多保存的字幕时间轴调整数量，超出时删除最早设置的调整
+const maxSubtitleOffsets = 10000
+
+// 字幕时间轴调整
+type SubtitleOffset struct {
+	Offset    float64   `json:"offset"`          // 时间偏移（秒），正数时字幕延后
+	Ratio     float64   `json:"ratio,omitempty"` // 时间缩放比例（字幕帧率 / 视频帧率），为 0 时不缩放
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// 调整后的时间：原时间 × Ratio + Offset
+func (o SubtitleOffset) correct(d time.Duration) time.Duration {
+	if o.Ratio > 0 {
+		d = time.Duration(float64(d) * o.Ratio)
+	}
+	return d + time.Duration(o.Offset*float64(time.Second))
+}
+
+// 设置字幕时间轴调整的请求
+//
+// 同时传入 from_fps 和 to_fps 时按两者计算 ratio（如字幕按 25 帧制作、视频为 23.976 帧）
+type subtitleOffsetRequest struct {
+	Offset  float64 `json:"offset"`
+	Ratio   float64 `json:"ratio"`
+	FromFPS float64 `json:"from_fps"`
+	ToFPS   float64 `json:"to_fps"`
+}
+
+// 字幕时间轴调整存储
+//
+// 以「媒体项 ID/媒体源 ID/字幕流序号」为键，保存在配置目录的 JSON 文件中；
+// 同一媒体项的不同版本剪辑可能不同，需要分别调整
+type subtitleOffsetStore struct {
+	mutex   sync.RWMutex
+	path    string
+	offsets map[string]SubtitleOffset
+}
+
+// 首次使用时从文件加载
+var subtitleOffsets = sync.OnceValue(func() *subtitleOffsetStore {
+	store := &subtitleOffsetStore{path: config.SubtitleOffsetPath(), offsets: make(map[string]SubtitleOffset)}
+	data, err := os.ReadFile(store.path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			logging.Warning("读取字幕时间轴调整失败：", err)
+		}
+		return store
+	}
+	if err := json.Unmarshal(data, &store.offsets); err != nil {
+		logging.Warning("解析字幕时间轴调整失败：", err)
+	}
+	return store
+})
+
+// 字幕时间轴调整的键
+//
+// 媒体源 ID 去除 mediasource_ 前缀
+func subtitleOffsetKey(itemID string, mediaSourceID string, index string) string {
+	mediaSourceID = strings.TrimPrefix(strings.ToLower(mediaSourceID), "mediasource_")
+	return strings.ToLower(itemID) + "/" + mediaSourceID + "/" + index
+}
+
+// 按路由参数获取字幕时间轴调整的键
+func subtitleOffsetKeyFromParams(ctx *gin.Context) string {
+	return subtitleOffsetKey(ctx.Param("itemId"), ctx.Param("mediaSourceId"), ctx.Param("index"))
+}
+
+// 获取字幕时间轴调整
+func (s *subtitleOffsetStore) get(key string) (SubtitleOffset, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	offset, ok := s.offsets[key]
+	return offset, ok
+}
+
+// 设置字幕时间轴调整，offset 为 nil 时删除
+func (s *subtitleOffsetStore) set(key string, offset *SubtitleOffset) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if offset == nil {
+		delete(s.offsets, key)
+	} else {
+		if _, ok := s.offsets[key]; !ok && len(s.offsets) >= maxSubtitleOffsets {
+			s.evictOldest()
+		}
+		s.offsets[key] = *offset
+	}
+
+	data, err := json.MarshalIndent(s.offsets, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), os.ModePerm); err != nil {
+		return err
+	}
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, s.path)
+}
+
+// 删除最早设置的调整
+func (s *subtitleOffsetStore) evictOldest() {
+	var oldestKey string
+	for key, offset := range s.offsets {
+		if oldestKey == "" || offset.UpdatedAt.Before(s.offsets[oldestKey].UpdatedAt) {
+			oldestKey = key
+		}
+	}
+	delete(s.offsets, oldestKey)
+}
+
+// 按请求路径调整字幕时间轴
+func retimeSubtitles(requestPath string, content []byte) []byte {
+	matches := subtitleStreamRegexp.FindStringSubmatch(requestPath)
+	if matches == nil {
+		return content
+	}
+	key := subtitleOffsetKey(matches[1], matches[2], matches[3])
+	offset, ok := subtitleOffsets().get(key)
+	if !ok {
+		return content
+	}
+	logging.Infof("字幕 %s 时间轴调整：偏移 %.3f 秒，缩放 %.6f", key, offset.Offset, offset.Ratio)
+	return subtitle.Retime(content, offset.correct)
+}
+
+// 获取字幕时间轴调整
+//
+// GET /MediaWarp/subtitles/:itemId/:mediaSourceId/:index/offset
+func GetSubtitleOffset(ctx *gin.Context) {
+	offset, ok := subtitleOffsets().get(subtitleOffsetKeyFromParams(ctx))
+	if !ok {
+		offset.Ratio = 1
+	}
+	ctx.JSON(http.StatusOK, offset)
+}
+
+// 设置字幕时间轴调整
+//
+// POST /MediaWarp/subtitles/:itemId/:mediaSourceId/:index/offset
+// 偏移为 0 且不缩放时删除调整
+func SetSubtitleOffset(ctx *gin.Context) {
+	var request subtitleOffsetRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		return
+	}
+	if request.FromFPS > 0 && request.ToFPS > 0 {
+		request.Ratio = request.FromFPS / request.ToFPS
+	}
+	if request.Ratio != 0 && (request.Ratio < 0.5 || request.Ratio > 2) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid ratio: %g", request.Ratio)})
+		return
+	}
+
+	var (
+		key    = subtitleOffsetKeyFromParams(ctx)
+		offset *SubtitleOffset
+	)
+	if request.Offset != 0 || (request.Ratio != 0 && request.Ratio != 1) {
+		offset = &SubtitleOffset{Offset: request.Offset, Ratio: request.Ratio, UpdatedAt: time.Now()}
+	}
+	if err := subtitleOffsets().set(key, offset); err != nil {
+		logging.Warning("保存字幕时间轴调整失败：", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if offset == nil {
+		logging.Info("已清除字幕时间轴调整：", key)
+		ctx.JSON(http.StatusOK, SubtitleOffset{Ratio: 1})
+		return
+	}
+	logging.Infof("已设置字幕 %s 时间轴调整：偏移 %.3f 秒，缩放 %.6f", key, offset.Offset, offset.Ratio)
+	ctx.JSON(http.StatusOK, offset)
+}
+
+// 清除字幕时间轴调整
+//
+// DELETE /MediaWarp/subtitles/:itemId/:mediaSourceId/:index/offset
+func DeleteSubtitleOffset(ctx *gin.Context) {
+	key := subtitleOffsetKeyFromParams(ctx)
+	if err := subtitleOffsets().set(key, nil); err != nil {
+		logging.Warning("保存字幕时间轴调整失败：", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	logging.Info("已清除字幕时间轴调整：", key)
+	ctx.Status(http.StatusNoContent)
+}
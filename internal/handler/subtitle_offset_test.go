@@ -0,0 +1,135 @@
+package handler_test
+
+import (
+	"MediaWarp/constants"
+	"MediaWarp/internal/config"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// 媒体服务器用户认证令牌验证接口桩，仅 token 有效，validations 记录验证次数
+func systemInfoStub(w http.ResponseWriter, r *http.Request, token string, validations *atomic.Int32) bool {
+	if !strings.EqualFold(r.URL.Path, "/System/Info") {
+		return false
+	}
+	validations.Add(1)
+	if r.Header.Get("X-Emby-Token") != token && r.Header.Get("Authorization") != `MediaBrowser Token="`+token+`"` {
+		w.WriteHeader(http.StatusUnauthorized)
+		return true
+	}
+	w.Write([]byte(`{}`))
+	return true
+}
+
+func TestSubtitleOffset(t *testing.T) {
+	var validations atomic.Int32
+	mediaWarp, client := startMediaWarp(t, constants.EMBY, func(w http.ResponseWriter, r *http.Request) {
+		if systemInfoStub(w, r, "user-token", &validations) {
+			return
+		}
+		w.Write([]byte("1\n00:00:10,000 --> 00:00:12,500\nHello\n\n2\n00:01:00,000 --> 00:01:02,000\nWorld\n"))
+	}, func(s *config.Snapshot) {
+		s.Subtitle = config.SubtitleSetting{Enable: true}
+	})
+
+	getSubtitle := func(mediaSourceID string, index string) string {
+		resp, err := client.Get(mediaWarp.URL + "/emby/Videos/300/" + mediaSourceID + "/Subtitles/" + index + "/Stream.srt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	endpoint := mediaWarp.URL + "/MediaWarp/subtitles/300/mediasource_300/2/offset"
+	setOffset := func(body string, token string) int {
+		req, _ := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Emby-Token", token)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if code := setOffset(`{"offset":-1.5}`, ""); code != http.StatusUnauthorized {
+		t.Errorf("未提供认证令牌时应拒绝设置，实际状态码：%d", code)
+	}
+	if code := setOffset(`{"offset":-1.5}`, "wrong-token"); code != http.StatusUnauthorized {
+		t.Errorf("认证令牌无效时应拒绝设置，实际状态码：%d", code)
+	}
+	resp, err := client.Get(endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("未提供认证令牌时应拒绝读取，实际状态码：%d", resp.StatusCode)
+	}
+	if code := setOffset(`{"offset":-1.5,"from_fps":25,"to_fps":25}`, "user-token"); code != http.StatusOK {
+		t.Fatalf("设置时间轴调整失败：%d", code)
+	}
+	resp, err = client.Get(endpoint + "?api_key=user-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), `"offset":-1.5`) {
+		t.Errorf("读取时间轴调整失败：%d %s", resp.StatusCode, body)
+	}
+	if n := validations.Load(); n != 2 {
+		t.Errorf("验证通过的认证令牌应缓存，验证次数：%d", n)
+	}
+	if body := getSubtitle("mediasource_300", "2"); !strings.Contains(body, "00:00:08,500 --> 00:00:11,000") || !strings.Contains(body, "00:00:58,500 --> 00:01:00,500") {
+		t.Errorf("字幕时间轴未调整：\n%s", body)
+	}
+	if body := getSubtitle("mediasource_300", "3"); !strings.Contains(body, "00:00:10,000 --> 00:00:12,500") {
+		t.Errorf("其他字幕流不应调整：\n%s", body)
+	}
+	if body := getSubtitle("mediasource_301", "2"); !strings.Contains(body, "00:00:10,000 --> 00:00:12,500") {
+		t.Errorf("其他媒体源不应调整：\n%s", body)
+	}
+
+	setOffset(`{"offset":1,"from_fps":23.976,"to_fps":25}`, "user-token")
+	if body := getSubtitle("mediasource_300", "2"); !strings.Contains(body, "00:00:10,590 --> 00:00:12,988") {
+		t.Errorf("字幕帧率未调整：\n%s", body)
+	}
+
+	req, _ := http.NewRequest(http.MethodDelete, endpoint, nil)
+	req.Header.Set("X-Emby-Token", "user-token")
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if body := getSubtitle("mediasource_300", "2"); !strings.Contains(body, "00:00:10,000 --> 00:00:12,500") {
+		t.Errorf("清除后字幕时间轴仍被调整：\n%s", body)
+	}
+}
+
+func TestSubtitleOffsetJellyfinToken(t *testing.T) {
+	var validations atomic.Int32
+	mediaWarp, client := startMediaWarp(t, constants.JELLYFIN, func(w http.ResponseWriter, r *http.Request) {
+		systemInfoStub(w, r, "jellyfin-user-token", &validations)
+	}, func(s *config.Snapshot) {
+		s.Subtitle = config.SubtitleSetting{Enable: true}
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, mediaWarp.URL+"/MediaWarp/subtitles/310/mediasource_310/1/offset", nil)
+	req.Header.Set("Authorization", `MediaBrowser Client="Jellyfin Web", Token="jellyfin-user-token"`)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || validations.Load() != 1 {
+		t.Errorf("Jellyfin 用户认证令牌验证失败：%d，验证次数：%d", resp.StatusCode, validations.Load())
+	}
+}
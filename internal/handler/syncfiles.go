@@ -3,6 +3,7 @@ package handler
 import (
 	"MediaWarp/internal/cache"
 	"MediaWarp/internal/config"
+	"MediaWarp/internal/limiter"
 	"MediaWarp/internal/logging"
 	"MediaWarp/internal/process"
 	"MediaWarp/internal/security"
@@ -170,13 +171,13 @@ func MediaFileSyncHandler(ctx *gin.Context) {
 		"path":    fullPath})
 
 	// 获取服务器地址，默认使用第一个
-	if serverAddr == "" && len(config.MediaSync) > 0 {
-		serverAddr = config.MediaSync[0].Name
+	if serverAddr == "" && len(config.MediaSync()) > 0 {
+		serverAddr = config.MediaSync()[0].Name
 	}
 
 	// 查找对应的服务器配置
 	var serverConfig *config.MediaSyncServerSetting
-	for _, server := range config.MediaSync {
+	for _, server := range config.MediaSync() {
 		if server.Name == serverAddr {
 			serverConfig = &server
 			break
@@ -286,8 +287,8 @@ func scanMediaLibrary() {
 	// apiKey := config.ApiKey // 替换为实际的 API 密钥
 	// 构建获取任务列表的 URL
 
-	embyServer := fmt.Sprintf("http://0.0.0.0:%d", config.Port)
-	apiKey := config.MediaServer.AUTH
+	embyServer := fmt.Sprintf("http://0.0.0.0:%d", config.Port())
+	apiKey := config.MediaServer().AUTH
 	url := fmt.Sprintf("%s/emby/ScheduledTasks?api_key=%s", embyServer, apiKey)
 
 	// 发送 GET 请求获取任务列表
@@ -349,10 +350,10 @@ func scanMediaLibrary() {
 }
 
 // Middleware to check API Key
-func apiKeyAuth() gin.HandlerFunc {
+func APIKeyAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		apiKey := c.GetHeader("X-API-Key")
-		if apiKey != config.MediaServer.AUTH {
+		if apiKey == "" || apiKey != config.MediaServer().AUTH {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: Invalid API Key"})
 			c.Abort()
 			return
@@ -364,7 +365,7 @@ func apiKeyAuth() gin.HandlerFunc {
 // Handle the API Key verification
 func verifyAPIKey(c *gin.Context) {
 	apiKey := c.GetHeader("X-API-Key")
-	if apiKey == config.MediaServer.AUTH {
+	if apiKey == config.MediaServer().AUTH {
 		c.JSON(http.StatusOK, gin.H{"message": "API Key is valid"})
 	} else {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API Key"})
@@ -383,13 +384,13 @@ func SyncfolderHandler(ctx *gin.Context) {
 	}
 
 	// 获取服务器地址，默认使用第一个
-	if serverAddr == "" && len(config.MediaSync) > 0 {
-		serverAddr = config.MediaSync[0].Name
+	if serverAddr == "" && len(config.MediaSync()) > 0 {
+		serverAddr = config.MediaSync()[0].Name
 	}
 
 	// 查找对应的服务器配置
 	var serverConfig *config.MediaSyncServerSetting
-	for _, server := range config.MediaSync {
+	for _, server := range config.MediaSync() {
 		if server.Name == serverAddr {
 			serverConfig = &server
 			break
@@ -400,7 +401,7 @@ func SyncfolderHandler(ctx *gin.Context) {
 		ctx.HTML(http.StatusOK, "syncFolder.html", gin.H{
 			"Path":          path,
 			"Folders":       []string{},
-			"Servers":       config.MediaSync,
+			"Servers":       config.MediaSync(),
 			"CurrentServer": serverAddr,
 			"PrefixList":    []string{},
 			"CurrentPrefix": "",
@@ -415,7 +416,7 @@ func SyncfolderHandler(ctx *gin.Context) {
 	}
 
 	var folders []string
-	if apiKey == config.MediaServer.AUTH {
+	if apiKey == config.MediaServer().AUTH {
 		// 安全验证参数
 		if err := security.ValidatePath(path); err != nil {
 			logging.Warning("无效的路径参数:", err)
@@ -451,7 +452,16 @@ func SyncfolderHandler(ctx *gin.Context) {
 			defer cancel()
 
 			rcloneCmd := serverAddr + ":" + path
-			output, err := process.RunWithOutput(ctx_timeout, timeout, "rclone", "lsf", rcloneCmd, "--dirs-only")
+			var output []byte
+			remote := serverConfig.Remote // 与播放、预加载共用远程存储的限流和熔断
+			if remote == "" {
+				remote = serverAddr
+			}
+			err := limiter.Do(ctx_timeout, remote, func() error {
+				var err error
+				output, err = process.RunWithOutput(ctx_timeout, timeout, "rclone", "lsf", rcloneCmd, "--dirs-only")
+				return err
+			})
 			if err != nil {
 				logging.Error("执行rclone命令失败", "server", serverAddr, "path", path, "command", rcloneCmd, "error", err)
 
@@ -492,7 +502,7 @@ func SyncfolderHandler(ctx *gin.Context) {
 	ctx.HTML(http.StatusOK, "syncFolder.html", gin.H{
 		"Path":          path,
 		"Folders":       folders,
-		"Servers":       config.MediaSync,
+		"Servers":       config.MediaSync(),
 		"CurrentServer": serverAddr,
 		"PrefixList":    []string{prefixPath}, // 当前使用的前缀路径
 		"CurrentPrefix": prefixPath,
@@ -554,11 +564,11 @@ func SyncFilesRouter(router *gin.Engine) {
 
 	// Routes with API Key auth
 	router.GET("/syncfolder", SyncfolderHandler)
-	router.POST("/Sync/*path", apiKeyAuth(), MediaFileSyncHandler)
+	router.POST("/Sync/*path", APIKeyAuth(), MediaFileSyncHandler)
 
 	// 缓存管理API (需要API Key认证)
-	router.GET("/cache/stats", apiKeyAuth(), cacheStatsHandler)
-	router.POST("/cache/clear", apiKeyAuth(), clearCacheHandler)
-	router.GET("/cache/export", apiKeyAuth(), exportCacheHandler)
+	router.GET("/cache/stats", APIKeyAuth(), cacheStatsHandler)
+	router.POST("/cache/clear", APIKeyAuth(), clearCacheHandler)
+	router.GET("/cache/export", APIKeyAuth(), exportCacheHandler)
 
 }
@@ -0,0 +1 @@
+define(["exports"],function(_exports){function getCrossOriginValue(mediaSource,playMethod){return mediaSource.IsRemote&&"DirectPlay"===playMethod?null:"anonymous"}_exports.getCrossOriginValue=getCrossOriginValue});
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"MediaWarp/constants"
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/logging"
+	"MediaWarp/internal/service/emby"
+	"MediaWarp/internal/useragent"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 是否允许转码
+//
+// 需要开启 HTTPStrm.TransCode，并且客户端和视频编码均在允许列表中（列表为空时不限制）
+func allowTranscode(userAgent string, codec string) bool {
+	setting := config.HTTPStrm()
+	if !setting.TransCode {
+		return false
+	}
+	if len(setting.TransCodeClients) > 0 {
+		client := useragent.Classify(userAgent)
+		matched := false
+		for _, name := range setting.TransCodeClients {
+			if strings.EqualFold(name, client.Class) || strings.Contains(strings.ToLower(userAgent), strings.ToLower(name)) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(setting.TransCodeCodecs) > 0 {
+		matched := false
+		for _, name := range setting.TransCodeCodecs {
+			if strings.EqualFold(name, codec) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// 获取媒体源的视频编码
+func videoCodec(mediaStreams []emby.MediaStream) string {
+	for _, stream := range mediaStreams {
+		if stream.Type != nil && *stream.Type == emby.Video && stream.Codec != nil {
+			return *stream.Codec
+		}
+	}
+	return ""
+}
+
+// 请求是否携带上游媒体服务器的 API Key
+//
+// 从 X-API-Key 请求头或 api_key 查询参数中获取
+func (embyServerHandler *EmbyServerHandler) validAPIKey(ctx *gin.Context) bool {
+	apiKey := ctx.GetHeader("X-API-Key")
+	if apiKey == "" {
+		apiKey = ctx.Query("api_key")
+	}
+	expected := embyServerHandler.server.GetAPIKey()
+	return expected != "" && subtle.ConstantTimeCompare([]byte(apiKey), []byte(expected)) == 1
+}
+
+// Strm 下载链接处理器
+//
+// /MediaWarp/strm/:itemId?MediaSourceId=xxx&api_key=xxx
+// /MediaWarp/strm?link=115://xxx&api_key=xxx
+// 重定向至媒体源或 link 最新的下载链接，MediaSourceId 为空时使用第一个需要解析的媒体源；
+// Strm 内容为该接口时上游转码可以读取 rclone 链接的媒体源，也可供外部播放器或脚本使用，需要携带上游媒体服务器的 API Key
+func (embyServerHandler *EmbyServerHandler) StrmHandler(ctx *gin.Context) {
+	logging.Debug("======= StrmHandler ======= ")
+
+	if !embyServerHandler.validAPIKey(ctx) {
+		ctx.String(http.StatusUnauthorized, "无效的 API Key")
+		return
+	}
+
+	if link := ctx.Query("link"); link != "" {
+		embyServerHandler.redirectStrm(ctx, link)
+		return
+	}
+
+	matches := constants.EmbyRegexp.Router.StrmEndpoint.FindStringSubmatch(ctx.Request.URL.Path)
+	if len(matches) != 2 || matches[1] == "" {
+		ctx.String(http.StatusBadRequest, "无效的媒体项 ID")
+		return
+	}
+	itemID := matches[1]
+	mediaSourceID := ctx.Query("mediasourceid")
+
+	itemResponse, err := embyServerHandler.queryItem(itemID)
+	if err != nil || len(itemResponse.Items) == 0 {
+		logging.Warning("Strm 下载链接接口获取媒体项信息失败：", itemID, err)
+		ctx.String(http.StatusNotFound, "未找到媒体项")
+		return
+	}
+
+	var strmPath string
+	for _, mediaSource := range itemResponse.Items[0].MediaSources {
+		if mediaSource.Path == nil {
+			continue
+		}
+		if mediaSourceID != "" {
+			if mediaSource.ID != nil && strings.TrimPrefix(*mediaSource.ID, "mediasource_") == strings.TrimPrefix(mediaSourceID, "mediasource_") {
+				strmPath = *mediaSource.Path
+				break
+			}
+		} else if isRclonePath(*mediaSource.Path) || strings.HasPrefix(*mediaSource.Path, "http") {
+			strmPath = *mediaSource.Path
+			break
+		}
+	}
+	if strmPath == "" {
+		ctx.String(http.StatusNotFound, "未找到媒体源")
+		return
+	}
+	embyServerHandler.redirectStrm(ctx, strmPath)
+}
+
+// 重定向至 Strm 链接最新的下载链接
+func (embyServerHandler *EmbyServerHandler) redirectStrm(ctx *gin.Context, strmPath string) {
+	redirectURL, err := getRedirectURL(strmPath, ctx.Request.Header.Get("User-Agent"))
+	if err != nil {
+		logging.Warning("Strm 下载链接接口获取下载链接失败：", err)
+		ctx.String(http.StatusBadGateway, "获取下载链接失败")
+		return
+	}
+	logging.Info("Strm 下载链接接口重定向至：", redirectURL)
+	ctx.Redirect(http.StatusFound, redirectURL)
+}
@@ -0,0 +1,112 @@
+package handler_test
+
+import (
+	"MediaWarp/constants"
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/resolver"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestTranscode(t *testing.T) {
+	resolver.Register("record", &recordResolver{})
+	// Strm 内容为 MediaWarp 的 Strm 下载链接接口的 rclone 链接，上游转码时可以读取
+	wrapped := "http://mediawarp:9000/MediaWarp/strm?link=" + url.QueryEscape("record://wrapped.mkv") + "&api_key=emby-key"
+
+	source := func(id string, path string, codec string) string {
+		return `{"Id":"` + id + `","ItemId":"200","Path":"` + path + `","SupportsDirectPlay":false,"SupportsDirectStream":false,` +
+			`"TranscodingUrl":"/videos/200/master.m3u8?MediaSourceId=` + id + `","MediaStreams":[{"Type":"Video","Codec":"` + codec + `"}]}`
+	}
+	mediaWarp, client := startMediaWarp(t, constants.EMBY, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/PlaybackInfo") {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"MediaSources":[` + source("21", "https://cdn.example.com/hevc.mkv", "hevc") + `,` +
+				source("22", "record://h264.mkv", "h264") + `,` + source("23", "record://av1.mkv", "hevc") + `,` + source("24", wrapped, "hevc") + `]}`))
+			return
+		}
+		w.Write([]byte(`{"Items":[{"Id":"200","Path":"/media/200.strm","MediaSources":[{"Id":"21","Path":"https://cdn.example.com/hevc.mkv"},{"Id":"22","Path":"record://h264.mkv"},` +
+			`{"Id":"24","Path":"` + wrapped + `","Protocol":"Http"}]}]}`))
+	}, func(s *config.Snapshot) {
+		s.HTTPStrm = config.HTTPStrmSetting{TransCode: true, TransCodeCodecs: []string{"HEVC"}}
+		s.MediaServers[0].AUTH = "emby-key"
+	})
+
+	resp, err := client.Get(mediaWarp.URL + "/emby/Items/200/PlaybackInfo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var playbackInfo struct {
+		MediaSources []struct {
+			Id             string
+			Path           string
+			Protocol       string
+			TranscodingUrl *string
+		}
+	}
+	err = json.NewDecoder(resp.Body).Decode(&playbackInfo)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(playbackInfo.MediaSources) != 4 {
+		t.Fatalf("媒体源数量错误：%d", len(playbackInfo.MediaSources))
+	}
+	if hevc := playbackInfo.MediaSources[0]; hevc.TranscodingUrl == nil || hevc.Path != "https://cdn.example.com/hevc.mkv" {
+		t.Errorf("允许转码的媒体源处理错误：%+v", hevc)
+	}
+	if h264 := playbackInfo.MediaSources[1]; h264.TranscodingUrl != nil {
+		t.Errorf("未允许转码的编码应强制关闭转码：%+v", h264)
+	}
+	if rclone := playbackInfo.MediaSources[2]; rclone.TranscodingUrl != nil || rclone.Path != "record://av1.mkv" {
+		t.Errorf("上游无法读取的 Strm 链接应强制关闭转码：%+v", rclone)
+	}
+	if rclone := playbackInfo.MediaSources[3]; rclone.TranscodingUrl == nil || rclone.Path != wrapped {
+		t.Errorf("通过 Strm 下载链接接口读取的 rclone 链接应保留转码：%+v", rclone)
+	}
+
+	for caseName, testCase := range map[string]struct {
+		Query    string
+		APIKey   string
+		Status   int
+		Location string
+	}{
+		"缺少 API Key":       {"?MediaSourceId=22", "", http.StatusUnauthorized, ""},
+		"错误的 API Key":      {"?MediaSourceId=22&api_key=wrong", "", http.StatusUnauthorized, ""},
+		"指定媒体源":            {"?MediaSourceId=22&api_key=emby-key", "", http.StatusFound, "https://cdn.example.com/h264.mkv"},
+		"请求头携带 API Key":    {"", "emby-key", http.StatusFound, "https://cdn.example.com/hevc.mkv"},
+		"上游转码读取 rclone 链接": {"?link=" + url.QueryEscape("record://wrapped.mkv") + "&api_key=emby-key", "", http.StatusFound, "https://cdn.example.com/wrapped.mkv"},
+		"link 缺少 API Key":  {"?link=" + url.QueryEscape("record://wrapped.mkv"), "", http.StatusUnauthorized, ""},
+	} {
+		t.Run(caseName, func(t *testing.T) {
+			path := "/MediaWarp/strm/200"
+			if strings.HasPrefix(testCase.Query, "?link=") {
+				path = "/MediaWarp/strm"
+			}
+			req, _ := http.NewRequest(http.MethodGet, mediaWarp.URL+path+testCase.Query, nil)
+			if testCase.APIKey != "" {
+				req.Header.Set("X-API-Key", testCase.APIKey)
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if location := resp.Header.Get("Location"); resp.StatusCode != testCase.Status || location != testCase.Location {
+				t.Errorf("Strm 下载链接接口响应错误：%d %s", resp.StatusCode, location)
+			}
+		})
+	}
+
+	// 客户端直链播放时直接解析 Strm 下载链接接口中的 rclone 链接
+	resp, err = client.Get(mediaWarp.URL + "/emby/videos/200/stream?MediaSourceId=24&Static=true")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if location := resp.Header.Get("Location"); location != "https://cdn.example.com/wrapped.mkv" {
+		t.Errorf("直链播放重定向错误：%d %s", resp.StatusCode, location)
+	}
+}
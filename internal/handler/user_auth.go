@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"MediaWarp/internal/cache"
+	"MediaWarp/internal/logging"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const userTokenTTL = 5 * time.Minute // 验证通过的用户认证令牌的缓存时间
+
+// 验证通过的用户认证令牌，键为上游地址和令牌的摘要
+var userTokens = sync.OnceValue(func() *cache.SafeCache {
+	return cache.NewSafeCache(userTokenTTL)
+})
+
+// 可验证用户认证令牌的媒体服务器
+type userTokenValidator interface {
+	GetEndpoint() string
+	ValidateToken(ctx context.Context, token string) (bool, error)
+}
+
+// 请求对应上游的用户认证令牌验证器，上游不支持时返回 nil
+func userTokenValidatorFor(req *http.Request) userTokenValidator {
+	u, err := matchUpstream(req)
+	if err != nil || u == nil {
+		return nil
+	}
+	switch h := u.handler.(type) {
+	case *EmbyServerHandler:
+		return h.server
+	case *JellyfinServerHandler:
+		return h.server
+	default:
+		return nil
+	}
+}
+
+// 媒体服务器用户认证中间件
+//
+// 使用 Web 客户端已携带的用户认证令牌（X-Emby-Token 请求头、api_key 查询参数等），
+// 向请求对应的 Emby / Jellyfin 上游验证，验证结果缓存 userTokenTTL
+func UserTokenAuth() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token := embyToken(ctx.Request)
+		validator := userTokenValidatorFor(ctx.Request)
+		if token == "" || validator == nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: Invalid token"})
+			return
+		}
+
+		sum := sha256.Sum256([]byte(validator.GetEndpoint() + "|" + token))
+		key := hex.EncodeToString(sum[:])
+		if _, ok := userTokens().Get(key); !ok {
+			valid, err := validator.ValidateToken(ctx.Request.Context(), token)
+			if err != nil {
+				logging.Warning("验证用户认证令牌失败：", err)
+				ctx.AbortWithStatusJSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+				return
+			}
+			if !valid {
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: Invalid token"})
+				return
+			}
+			userTokens().Set(key, "", time.Now().Add(userTokenTTL))
+		}
+		ctx.Next()
+	}
+}
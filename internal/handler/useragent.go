@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"MediaWarp/internal/logging"
+	"MediaWarp/internal/useragent"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 预览 User-Agent 的规范化结果
+//
+// ua 为空时使用请求的 User-Agent；传入 path 时同时返回下载链接的缓存键
+func PreviewUserAgent(ctx *gin.Context) {
+	userAgent := ctx.Query("ua")
+	if userAgent == "" {
+		userAgent = ctx.Request.UserAgent()
+	}
+	response := gin.H{"client": useragent.Classify(userAgent)}
+	if path := ctx.Query("path"); path != "" {
+		response["cache_key"] = redirectCacheKey(path, userAgent)
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// 注册 User-Agent 相关路由
+func RegisterUserAgentRoutes(router *gin.Engine) {
+	router.GET("/api/useragent/preview", PreviewUserAgent)
+	logging.Info("User-Agent 规范化预览API路由已注册")
+}
@@ -0,0 +1,91 @@
+package handler_test
+
+import (
+	"MediaWarp/constants"
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/resolver"
+	"MediaWarp/internal/useragent"
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// 记录获取下载链接时使用的 User-Agent 的解析器，下载链接与 User-Agent 绑定
+type userAgentResolver struct {
+	mutex      sync.Mutex
+	userAgents []string
+}
+
+func (r *userAgentResolver) Name() string {
+	return "ua"
+}
+
+func (r *userAgentResolver) Resolve(ctx context.Context, link string, userAgent string) (string, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.userAgents = append(r.userAgents, userAgent)
+	return "https://cdn.example.com/movie.mkv?ua=" + url.QueryEscape(userAgent), nil
+}
+
+func TestUserAgentCacheKey(t *testing.T) {
+	uaResolver := &userAgentResolver{}
+	resolver.Register("ua", uaResolver)
+
+	localPath := t.TempDir()
+	strmPath := filepath.Join(localPath, "Movie.strm")
+	if err := os.WriteFile(strmPath, []byte("ua://movie.mkv"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { useragent.Init() }) // 在恢复配置之后执行
+	mediaWarp, client := startMediaWarp(t, constants.PLEX, plexItemStub("4", strmPath), func(s *config.Snapshot) {
+		s.MediaSync = config.MediaSyncSetting{{Name: "ua", Remote: "ua:", LocalPath: localPath}}
+		s.UserAgent = config.UserAgentSetting{Enable: true}
+	})
+	if err := useragent.Init(); err != nil {
+		t.Fatal(err)
+	}
+	client.Get(mediaWarp.URL + "/video/:/transcode/universal/decision?path=/library/metadata/4")
+
+	play := func(userAgent string) string {
+		req, _ := http.NewRequest(http.MethodGet, mediaWarp.URL+"/library/parts/4/1/file.mkv", nil)
+		req.Header.Set("User-Agent", userAgent)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.Header.Get("Location")
+	}
+
+	// 内置规则未指定 User-Agent：同一类别的不同版本分别获取与自身 User-Agent 绑定的链接
+	for _, userAgent := range []string{"Infuse/7.6", "Infuse/7.7"} {
+		if location, want := play(userAgent), "https://cdn.example.com/movie.mkv?ua="+url.QueryEscape(userAgent); location != want {
+			t.Errorf("%s 的下载链接错误。期望: %s, 实际: %s", userAgent, want, location)
+		}
+	}
+	if !slices.Equal(uaResolver.userAgents, []string{"Infuse/7.6", "Infuse/7.7"}) {
+		t.Errorf("获取下载链接使用的 User-Agent 错误：%v", uaResolver.userAgents)
+	}
+
+	// 规则指定 User-Agent：同一类别共用使用该 User-Agent 获取的链接
+	config.Update(func(s *config.Snapshot) {
+		s.UserAgent.Rules = []config.UserAgentRule{{Class: "Infuse", Pattern: `(?i)infuse`, UserAgent: "Infuse-Direct/8.0"}}
+	})
+	if err := useragent.Init(); err != nil {
+		t.Fatal(err)
+	}
+	uaResolver.userAgents = nil
+	for _, userAgent := range []string{"Infuse/7.6", "Infuse/7.7"} {
+		if location, want := play(userAgent), "https://cdn.example.com/movie.mkv?ua=Infuse-Direct%2F8.0"; location != want {
+			t.Errorf("%s 的下载链接错误。期望: %s, 实际: %s", userAgent, want, location)
+		}
+	}
+	if !slices.Equal(uaResolver.userAgents, []string{"Infuse-Direct/8.0"}) {
+		t.Errorf("同一类别应共用下载链接：%v", uaResolver.userAgents)
+	}
+}
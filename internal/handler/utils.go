@@ -9,30 +9,41 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"net/http/httputil"
 	"os"
 	"strconv"
 	"strings"
 
 	"github.com/andybalholm/brotli"
-	"github.com/gin-gonic/gin"
 )
 
-// 将需要修改上游响应的处理器包装成一个 gin.HandlerFunc 处理器
-func responseModifyCreater(proxy *httputil.ReverseProxy, modifyResponseFN func(rw *http.Response) error) gin.HandlerFunc {
-	proxy.ModifyResponse = modifyResponseFN
-	return func(ctx *gin.Context) {
-		proxy.ServeHTTP(ctx.Writer, ctx.Request)
+// 为缓存键添加命名空间
+func namespacedKey(namespace string, key string) string {
+	if namespace == "" {
+		return key
 	}
+	return namespace + "|" + key
 }
 
 // 根据 Strm 文件路径识别 Strm 文件类型
 // 返回 Strm 文件类型和一个可选配置
+//
+// AlistStrm 返回 Alist 服务器地址和是否使用 raw_url
 func recgonizeStrmFileType(strmFilePath string) (constants.StrmFileType, any, any) {
 	logging.Debug("识别 Strm 文件类型，路径：" + strmFilePath)
 
+	if config.AlistStrm().Enable {
+		for _, alistStrmConfig := range config.AlistStrm().List {
+			for _, prefix := range alistStrmConfig.PrefixList {
+				if strings.HasPrefix(strmFilePath, prefix) {
+					logging.Debug(strmFilePath + " 匹配 AlistStrm 路径：" + prefix + "，Alist 服务器：" + alistStrmConfig.ADDR)
+					return constants.AlistStrm, alistStrmConfig.ADDR, alistStrmConfig.RawURL
+				}
+			}
+		}
+	}
+
 	// 1. MediaSync 检查 - 检查是否匹配任何 MediaSync 服务器的本地路径
-	for _, server := range config.MediaSync {
+	for _, server := range config.MediaSync() {
 		if strings.HasPrefix(strmFilePath, server.LocalPath) {
 			logging.Debug(strmFilePath + " 匹配 MediaSync 服务器：" + server.Name + "，路径：" + server.LocalPath + "，类型：HTTPStrm")
 			return constants.HTTPStrm, nil, nil
@@ -111,40 +122,35 @@ func updateBody(rw *http.Response, content []byte) error {
 	encoding := rw.Header.Get("Content-Encoding")
 	var (
 		compressed bytes.Buffer
-		writer     io.Writer
+		writer     io.WriteCloser
 	)
 
 	// 根据原始编码选择压缩方式
 	switch encoding {
 	case "gzip":
 		logging.Debug("使用 GZIP 重新编码数据")
-		gw := gzip.NewWriter(&compressed)
-		defer gw.Close()
-		writer = gw
+		writer = gzip.NewWriter(&compressed)
 
 	case "br":
 		logging.Debug("使用 Brotli 重新编码数据")
-		bw := brotli.NewWriter(&compressed)
-		defer bw.Close()
-		writer = bw
+		writer = brotli.NewWriter(&compressed)
 
 	case "": // 无压缩
 		logging.Debug("无压缩数据")
-		writer = &compressed
 
 	default:
 		logging.Warningf("不支持的重新编码：%s，将不对数据进行压缩编码", encoding)
 		rw.Header.Del("Content-Encoding")
 	}
 
-	if _, err := writer.Write(content); err != nil {
-		return fmt.Errorf("compression write error: %w", err)
-	}
-
-	// Brotli 需要显式 Flush
-	if bw, ok := writer.(*brotli.Writer); ok {
-		if err := bw.Flush(); err != nil {
-			return err
+	if writer == nil {
+		compressed.Write(content)
+	} else {
+		if _, err := writer.Write(content); err != nil {
+			return fmt.Errorf("compression write error: %w", err)
+		}
+		if err := writer.Close(); err != nil { // 关闭后才会写入完整的压缩数据
+			return fmt.Errorf("compression close error: %w", err)
 		}
 	}
 
@@ -0,0 +1,126 @@
+package limiter
+
+import (
+	"sync"
+	"time"
+)
+
+// 熔断器状态
+type BreakerState string
+
+const (
+	StateClosed   BreakerState = "closed"    // 正常
+	StateOpen     BreakerState = "open"      // 熔断中，拒绝全部请求
+	StateHalfOpen BreakerState = "half-open" // 冷却结束，放行一个探测请求
+)
+
+// 熔断器
+type circuitBreaker struct {
+	mutex       sync.Mutex
+	threshold   int           // 连续失败多少次后熔断
+	cooldown    time.Duration // 熔断持续时间
+	current     BreakerState
+	consecutive int       // 连续失败次数
+	openUntil   time.Time // 熔断结束时间
+	probing     bool      // 半开状态下是否已有探测请求
+	requests    int64
+	failures    int64
+	rejected    int64
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		current:   StateClosed,
+	}
+}
+
+// 是否放行请求
+func (b *circuitBreaker) allow() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.current == StateOpen && time.Now().After(b.openUntil) {
+		b.current = StateHalfOpen
+		b.probing = false
+	}
+	switch b.current {
+	case StateOpen:
+		b.rejected++
+		return false
+	case StateHalfOpen:
+		if b.probing {
+			b.rejected++
+			return false
+		}
+		b.probing = true
+	}
+	return true
+}
+
+// 记录请求结果，返回是否因此进入熔断状态
+func (b *circuitBreaker) record(err error) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.requests++
+	if err == nil {
+		b.current = StateClosed
+		b.consecutive = 0
+		b.probing = false
+		return false
+	}
+
+	b.failures++
+	b.consecutive++
+	if b.current == StateHalfOpen || b.consecutive >= b.threshold { // 探测失败时重新熔断
+		b.current = StateOpen
+		b.openUntil = time.Now().Add(b.cooldown)
+		b.probing = false
+		return true
+	}
+	return false
+}
+
+// 放行的请求未实际执行（如等待令牌超时），半开状态下允许重新探测
+func (b *circuitBreaker) cancel() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.current == StateHalfOpen {
+		b.probing = false
+	}
+}
+
+// 当前状态（熔断已到期时视为半开状态）
+func (b *circuitBreaker) state() BreakerState {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.current == StateOpen && time.Now().After(b.openUntil) {
+		return StateHalfOpen
+	}
+	return b.current
+}
+
+// 获取熔断器状态
+func (b *circuitBreaker) snapshot() RemoteState {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	state := RemoteState{
+		State:               b.current,
+		ConsecutiveFailures: b.consecutive,
+		Requests:            b.requests,
+		Failures:            b.failures,
+		Rejected:            b.rejected,
+	}
+	if b.current == StateOpen {
+		if time.Now().After(b.openUntil) {
+			state.State = StateHalfOpen
+		} else {
+			openUntil := b.openUntil
+			state.OpenUntil = &openUntil
+		}
+	}
+	return state
+}
@@ -0,0 +1,46 @@
+package limiter
+
+import (
+	"MediaWarp/internal/health"
+	"context"
+	"strings"
+	"time"
+)
+
+// 远程存储健康检查
+//
+// 任意远程存储熔断时标记为降级，其余远程存储不受影响
+type RemoteHealthCheck struct{}
+
+func (c *RemoteHealthCheck) Name() string {
+	return "remotes"
+}
+
+func (c *RemoteHealthCheck) Check(ctx context.Context) health.CheckResult {
+	start := time.Now()
+	states := States()
+
+	var open []string
+	details := make(map[string]interface{}, len(states))
+	for _, state := range states {
+		details[state.Remote] = state
+		if state.State == StateOpen {
+			open = append(open, state.Remote)
+		}
+	}
+
+	result := health.CheckResult{
+		Name:     c.Name(),
+		Status:   health.StatusHealthy,
+		Message:  "all remotes are available",
+		Duration: time.Since(start),
+		Details:  details,
+	}
+	if len(open) > 0 {
+		result.Status = health.StatusDegraded
+		result.Message = "circuit open: " + strings.Join(open, ", ")
+	}
+	return result
+}
+
+var _ health.HealthCheck = (*RemoteHealthCheck)(nil) // 确保 RemoteHealthCheck 实现 HealthCheck 接口
@@ -0,0 +1,212 @@
+package limiter
+
+import (
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/logging"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// 默认限流和熔断参数
+const (
+	defaultQPS              = 2
+	defaultBurst            = 5
+	defaultFailureThreshold = 5
+	defaultCooldown         = 60 * time.Second
+)
+
+var ErrCircuitOpen = errors.New("远程存储熔断中")
+
+// 远程存储限流器
+//
+// 令牌桶限制请求速率，连续失败达到阈值后熔断，冷却时间过后放行一个探测请求（半开状态）
+type RemoteLimiter struct {
+	name    string
+	limiter *rate.Limiter
+	breaker *circuitBreaker
+	limited bool // 是否启用限流和熔断
+}
+
+// 远程存储限流状态
+type RemoteState struct {
+	Remote              string       `json:"remote"`
+	State               BreakerState `json:"state"`
+	QPS                 float64      `json:"qps"`
+	Burst               int          `json:"burst"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	OpenUntil           *time.Time   `json:"open_until,omitempty"`
+	Requests            int64        `json:"requests"`
+	Failures            int64        `json:"failures"`
+	Rejected            int64        `json:"rejected"` // 因熔断被拒绝的请求数
+}
+
+var (
+	limiters = make(map[string]*RemoteLimiter)
+	applied  config.RemoteLimitSetting // 创建限流器使用的配置
+	mutex    sync.Mutex
+)
+
+// 初始化限流器
+//
+// 清空已创建的限流器，之后按当前配置重新创建
+func Init() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	limiters = make(map[string]*RemoteLimiter)
+	applied = config.RemoteLimit()
+}
+
+// 热重载
+//
+// 配置未修改时保留已创建的限流器和熔断状态
+func Reload() {
+	mutex.Lock()
+	unchanged := reflect.DeepEqual(applied, config.RemoteLimit())
+	mutex.Unlock()
+	if !unchanged {
+		logging.Info("远程存储限流配置已修改，重新创建限流器")
+		Init()
+	}
+}
+
+// 远程存储名称
+//
+// rclone 远程名称去除末尾的 : 和 /（115: -> 115），作为限流器的键
+func RemoteName(remote string) string {
+	return strings.ToLower(strings.TrimRight(remote, ":/"))
+}
+
+// 获取远程存储的限流器
+func Get(remote string) *RemoteLimiter {
+	remote = RemoteName(remote)
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if l, ok := limiters[remote]; ok {
+		return l
+	}
+	l := newRemoteLimiter(remote)
+	limiters[remote] = l
+	return l
+}
+
+// 根据配置创建限流器
+func newRemoteLimiter(remote string) *RemoteLimiter {
+	setting := config.RemoteLimit()
+	qps, burst := setting.QPS, setting.Burst
+	for _, rule := range setting.Remotes {
+		if RemoteName(rule.Remote) == remote {
+			if rule.QPS > 0 {
+				qps = rule.QPS
+			}
+			if rule.Burst > 0 {
+				burst = rule.Burst
+			}
+			break
+		}
+	}
+	if qps <= 0 {
+		qps = defaultQPS
+	}
+	if burst <= 0 {
+		burst = defaultBurst
+	}
+	threshold := setting.FailureThreshold
+	if threshold <= 0 {
+		threshold = defaultFailureThreshold
+	}
+	cooldown := time.Duration(setting.Cooldown) * time.Second
+	if cooldown <= 0 {
+		cooldown = defaultCooldown
+	}
+	return &RemoteLimiter{
+		name:    remote,
+		limiter: rate.NewLimiter(rate.Limit(qps), burst),
+		breaker: newCircuitBreaker(threshold, cooldown),
+		limited: setting.Enable,
+	}
+}
+
+// 在限流和熔断的保护下执行请求
+//
+// 等待令牌直到 ctx 结束；熔断中直接返回 ErrCircuitOpen
+func (l *RemoteLimiter) Do(ctx context.Context, fn func() error) error {
+	if !l.limited {
+		return fn()
+	}
+	if !l.breaker.allow() {
+		return fmt.Errorf("%w：%s", ErrCircuitOpen, l.name)
+	}
+	if err := l.limiter.Wait(ctx); err != nil {
+		l.breaker.cancel()
+		return fmt.Errorf("等待远程存储 %s 限流令牌失败：%w", l.name, err)
+	}
+	err := fn()
+	if err != nil && errors.Is(err, context.Canceled) { // 调用方取消的请求不计入失败
+		l.breaker.cancel()
+		return err
+	}
+	if l.breaker.record(err) {
+		logging.Warningf("远程存储 %s 连续请求失败，熔断 %s", l.name, l.breaker.cooldown)
+	}
+	return err
+}
+
+// 是否可以立即发起请求（未熔断且有可用令牌），不消耗令牌
+//
+// 用于预加载等可跳过的请求
+func (l *RemoteLimiter) Available() bool {
+	if !l.limited {
+		return true
+	}
+	return l.breaker.state() != StateOpen && l.limiter.Tokens() >= 1
+}
+
+// 是否处于熔断状态
+func (l *RemoteLimiter) Open() bool {
+	return l.limited && l.breaker.state() == StateOpen
+}
+
+// 获取限流状态
+func (l *RemoteLimiter) State() RemoteState {
+	state := l.breaker.snapshot()
+	state.Remote = l.name
+	state.QPS = float64(l.limiter.Limit())
+	state.Burst = l.limiter.Burst()
+	return state
+}
+
+// 在远程存储限流器的保护下执行请求
+func Do(ctx context.Context, remote string, fn func() error) error {
+	return Get(remote).Do(ctx, fn)
+}
+
+// 远程存储是否处于熔断状态
+func Failing(remote string) bool {
+	return Get(remote).Open()
+}
+
+// 获取全部远程存储的限流状态
+func States() []RemoteState {
+	mutex.Lock()
+	list := make([]*RemoteLimiter, 0, len(limiters))
+	for _, l := range limiters {
+		list = append(list, l)
+	}
+	mutex.Unlock()
+
+	states := make([]RemoteState, 0, len(list))
+	for _, l := range list {
+		states = append(states, l.State())
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Remote < states[j].Remote })
+	return states
+}
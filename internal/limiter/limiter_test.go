@@ -0,0 +1,77 @@
+package limiter_test
+
+import (
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/health"
+	"MediaWarp/internal/limiter"
+	"MediaWarp/internal/logging"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	logging.Init()
+	defer config.Update(func(s *config.Snapshot) {
+		s.RemoteLimit = config.RemoteLimitSetting{Enable: true, QPS: 100, Burst: 10, FailureThreshold: 2, Cooldown: 1}
+	})()
+	limiter.Init()
+
+	ctx := context.Background()
+	errThrottled := errors.New("405 throttled")
+	fail := func() error { return errThrottled }
+	succeed := func() error { return nil }
+
+	for range 2 {
+		if err := limiter.Do(ctx, "115", fail); !errors.Is(err, errThrottled) {
+			t.Fatalf("返回错误不正确：%v", err)
+		}
+	}
+	if err := limiter.Do(ctx, "115", succeed); !errors.Is(err, limiter.ErrCircuitOpen) {
+		t.Errorf("连续失败后未熔断：%v", err)
+	}
+	if err := limiter.Do(ctx, "123", succeed); err != nil { // 其他远程存储不受影响
+		t.Errorf("其他远程存储被熔断：%v", err)
+	}
+
+	result := (&limiter.RemoteHealthCheck{}).Check(ctx)
+	if result.Status != health.StatusDegraded {
+		t.Errorf("健康检查状态错误：%s", result.Status)
+	}
+
+	time.Sleep(1100 * time.Millisecond)
+	if err := limiter.Do(ctx, "115", succeed); err != nil { // 半开状态放行探测请求
+		t.Errorf("冷却后探测请求被拒绝：%v", err)
+	}
+	for _, state := range limiter.States() {
+		if state.State != limiter.StateClosed {
+			t.Errorf("%s 状态错误：%s", state.Remote, state.State)
+		}
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	defer config.Update(func(s *config.Snapshot) {
+		s.RemoteLimit = config.RemoteLimitSetting{Enable: true, QPS: 1, Burst: 1, Remotes: []config.RemoteLimitRule{{Remote: "123", QPS: 1000, Burst: 1}}}
+	})()
+	limiter.Init()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	succeed := func() error { return nil }
+	if err := limiter.Do(ctx, "115", succeed); err != nil {
+		t.Fatal(err)
+	}
+	if limiter.Get("115").Available() {
+		t.Error("令牌用尽后仍可立即请求")
+	}
+	if err := limiter.Do(ctx, "115", succeed); err == nil { // 下一个令牌在超时之后才可用
+		t.Error("超出速率的请求未被限制")
+	}
+	for range 3 {
+		if err := limiter.Do(ctx, "123", succeed); err != nil {
+			t.Errorf("单独设置的速率未生效：%v", err)
+		}
+	}
+}
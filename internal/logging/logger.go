@@ -26,19 +26,19 @@ func Init() {
 	accessLogger.SetFormatter(aLS)
 	serviceLogger.SetFormatter(sLS)
 
-	if !config.Logger.AccessLogger.Console { // 访问日志不输出到终端
+	if !config.Logger().AccessLogger.Console { // 访问日志不输出到终端
 		accessLogger.Out = io.Discard
 	}
 
-	if !config.Logger.ServiceLogger.Console { // 服务日志不输出到终端
+	if !config.Logger().ServiceLogger.Console { // 服务日志不输出到终端
 		serviceLogger.Out = io.Discard
 	}
 
-	if config.Logger.AccessLogger.File {
+	if config.Logger().AccessLogger.File {
 		accessLogger.AddHook(aLS)
 	}
 
-	if config.Logger.ServiceLogger.File {
+	if config.Logger().ServiceLogger.File {
 		serviceLogger.AddHook(sLS)
 	}
 
@@ -11,17 +11,23 @@ import (
 )
 
 // 客户端过滤器
+//
+// 每次请求时读取配置，热重载后无需重新注册
 func ClientFilter() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
+		if !config.ClientFilter().Enable {
+			ctx.Next()
+			return
+		}
 		userAgent := ctx.Request.UserAgent()
 		var allowed bool
 		if userAgent == "" { // 开启了客户端过滤器后禁止所有未提供User-Agent的链接
 			allowed = false
 		} else {
-			switch config.ClientFilter.Mode {
+			switch config.ClientFilter().Mode {
 			case constants.WHITELIST: // 白名单模式
 				allowed = false
-				for _, ua := range config.ClientFilter.ClientList {
+				for _, ua := range config.ClientFilter().ClientList {
 					if strings.Contains(userAgent, ua) {
 						allowed = true
 						break
@@ -29,7 +35,7 @@ func ClientFilter() gin.HandlerFunc {
 				}
 			case constants.BLACKLIST: // 黑名单模式
 				allowed = true
-				for _, ua := range config.ClientFilter.ClientList {
+				for _, ua := range config.ClientFilter().ClientList {
 					if strings.Contains(userAgent, ua) {
 						allowed = false
 						break
@@ -3,6 +3,7 @@ package middleware
 import (
 	"MediaWarp/constants"
 	"MediaWarp/internal/logging"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -27,15 +28,20 @@ func Logger() gin.HandlerFunc {
 		statusCode := ctx.Writer.Status()
 
 		statusColor, methodColor := getColor(statusCode, method)
+		var streamMode string
+		if mode, exists := ctx.Get(constants.StreamModeKey); exists { // 视频流处理方式：Redirect、Proxy
+			streamMode = fmt.Sprintf(" [%s]", mode)
+		}
 
 		logging.AccessLog(
-			"【Access】 %s |\033[4%dm %d \033[0m| %-10s |\033[4%dm %-7s \033[0m| %s \"%s\"",
+			"【Access】 %s |\033[4%dm %d \033[0m| %-10s |\033[4%dm %-7s \033[0m| %s \"%s\"%s",
 			startTime.Format(constants.FORMATE_TIME),
 			statusColor, statusCode,
 			wasteTime,
 			methodColor, method,
 			clientIP,
 			path,
+			streamMode,
 		)
 	}
 }
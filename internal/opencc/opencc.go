@@ -0,0 +1,137 @@
+package opencc
+
+import (
+	"MediaWarp/constants"
+	"bufio"
+	"embed"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// 内置词典，格式与 OpenCC 的 txt 词典相同：每行为「原文\t候选1 候选2 ...」，使用第一个候选
+//
+// 词典摘自 OpenCC（Apache-2.0），来源和修改说明见 dict/NOTICE
+//
+//go:embed dict/*.txt
+var dictFS embed.FS
+
+// 简繁转换器
+type Converter struct {
+	phrases   map[string]string // 词组
+	chars     map[rune]rune     // 单字
+	maxPhrase int               // 最长词组的字数
+}
+
+var converters = map[constants.ChineseConvertMode]func() (*Converter, error){
+	constants.ChineseConvertS2T: sync.OnceValues(func() (*Converter, error) {
+		return load("dict/STPhrases.txt", "dict/STCharacters.txt")
+	}),
+	constants.ChineseConvertT2S: sync.OnceValues(func() (*Converter, error) {
+		return load("dict/TSPhrases.txt", "dict/TSCharacters.txt")
+	}),
+}
+
+// 获取转换模式对应的转换器，词典在首次使用时加载
+func Get(mode constants.ChineseConvertMode) (*Converter, error) {
+	loader, ok := converters[mode]
+	if !ok {
+		return nil, fmt.Errorf("不支持的繁简转换模式：%s", mode)
+	}
+	return loader()
+}
+
+// 按转换模式转换文本
+func Convert(mode constants.ChineseConvertMode, text string) (string, error) {
+	converter, err := Get(mode)
+	if err != nil {
+		return "", err
+	}
+	return converter.Convert(text), nil
+}
+
+// 加载词组和单字词典
+func load(phrasesFile string, charsFile string) (*Converter, error) {
+	converter := &Converter{
+		phrases: make(map[string]string),
+		chars:   make(map[rune]rune),
+	}
+	if err := readDict(phrasesFile, func(key string, value string) {
+		converter.phrases[key] = value
+		converter.maxPhrase = max(converter.maxPhrase, utf8.RuneCountInString(key))
+	}); err != nil {
+		return nil, err
+	}
+	if err := readDict(charsFile, func(key string, value string) {
+		k, _ := utf8.DecodeRuneInString(key)
+		v, _ := utf8.DecodeRuneInString(value)
+		converter.chars[k] = v
+	}); err != nil {
+		return nil, err
+	}
+	return converter, nil
+}
+
+// 读取词典文件
+func readDict(name string, add func(key string, value string)) error {
+	file, err := dictFS.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, values, found := strings.Cut(line, "\t")
+		candidates := strings.Fields(values)
+		if !found || key == "" || len(candidates) == 0 {
+			return fmt.Errorf("词典 %s 第 %d 行格式错误：%s", name, lineNumber, line)
+		}
+		add(key, candidates[0])
+	}
+	return scanner.Err()
+}
+
+// 转换文本
+//
+// 从左到右优先匹配最长的词组，未匹配词组时逐字转换
+func (c *Converter) Convert(text string) string {
+	var (
+		runes   = []rune(text)
+		builder strings.Builder
+	)
+	builder.Grow(len(text))
+	for i := 0; i < len(runes); {
+		if runes[i] < 0x2E80 { // CJK 部首之前的字符（ASCII、ASS 标签等）无需转换
+			builder.WriteRune(runes[i])
+			i++
+			continue
+		}
+
+		matched := false
+		for n := min(c.maxPhrase, len(runes)-i); n >= 2; n-- {
+			if value, ok := c.phrases[string(runes[i:i+n])]; ok {
+				builder.WriteString(value)
+				i += n
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+
+		if value, ok := c.chars[runes[i]]; ok {
+			builder.WriteRune(value)
+		} else {
+			builder.WriteRune(runes[i])
+		}
+		i++
+	}
+	return builder.String()
+}
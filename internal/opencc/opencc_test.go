@@ -0,0 +1,36 @@
+package opencc_test
+
+import (
+	"MediaWarp/constants"
+	"MediaWarp/internal/opencc"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	for caseName, testCase := range map[string]struct {
+		Mode constants.ChineseConvertMode
+		Text string
+		Want string
+	}{
+		"简转繁":   {constants.ChineseConvertS2T, "这个头发太长了，我们去理发吧。", "這個頭髮太長了，我們去理髮吧。"},
+		"简转繁词组": {constants.ChineseConvertS2T, "他后来发现干净的面条在锅里。", "他後來發現乾淨的麵條在鍋裡。"},
+		"简转繁保留": {constants.ChineseConvertS2T, "皇后说这只是一只猫，不准确。", "皇后說這只是一隻貓，不準確。"},
+		"繁转简":   {constants.ChineseConvertT2S, "這個頭髮太長了，我們去理髮吧。", "这个头发太长了，我们去理发吧。"},
+		"繁转简着":  {constants.ChineseConvertT2S, "他看著窗外，想著那本著名的書。", "他看着窗外，想着那本著名的书。"},
+		"ASCII": {constants.ChineseConvertS2T, "{\\an8}Hello 世界", "{\\an8}Hello 世界"},
+	} {
+		t.Run(caseName, func(t *testing.T) {
+			result, err := opencc.Convert(testCase.Mode, testCase.Text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != testCase.Want {
+				t.Errorf("转换错误。期望: %s, 实际: %s", testCase.Want, result)
+			}
+		})
+	}
+
+	if _, err := opencc.Convert(constants.ChineseConvertNone, "测试"); err == nil {
+		t.Error("不支持的转换模式应返回错误")
+	}
+}
@@ -0,0 +1,42 @@
+package resolver
+
+import (
+	"MediaWarp/internal/service/alist"
+	"context"
+	"path"
+	"strings"
+)
+
+// Alist/OpenList 解析器
+//
+// alist://path/to/file -> /api/fs/get 返回的 raw_url
+type AlistResolver struct {
+	server     *alist.AlistServer
+	pathPrefix string
+}
+
+func NewAlistResolver(addr string, token string, pathPrefix string) *AlistResolver {
+	return &AlistResolver{
+		server:     alist.New(addr, token),
+		pathPrefix: pathPrefix,
+	}
+}
+
+func (r *AlistResolver) Name() string {
+	return "alist"
+}
+
+func (r *AlistResolver) Resolve(ctx context.Context, link string, userAgent string) (string, error) {
+	downloadURL, _, err := r.server.GetDownloadURL(ctx, joinLinkPath(r.pathPrefix, link), true)
+	return downloadURL, err
+}
+
+// 去除链接的协议并拼接路径前缀
+//
+// alist://movie/a.mkv + /115 -> /115/movie/a.mkv
+func joinLinkPath(prefix string, link string) string {
+	if index := strings.Index(link, "://"); index != -1 {
+		link = link[index+3:]
+	}
+	return path.Join("/", prefix, link)
+}
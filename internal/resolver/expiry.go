@@ -0,0 +1,38 @@
+package resolver
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// 签名链接中表示过期时间的查询参数（115 使用 t，123 等使用 expires）
+var expiryQueryKeys = []string{"t", "expires", "Expires"}
+
+// 从签名链接中解析过期时间
+//
+// 支持秒级和毫秒级 Unix 时间戳，无法解析时返回 false
+func ParseExpiry(link string) (time.Time, bool) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return time.Time{}, false
+	}
+	query := u.Query()
+	for _, key := range expiryQueryKeys {
+		value := query.Get(key)
+		if value == "" {
+			continue
+		}
+		timestamp, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || timestamp <= 0 {
+			continue
+		}
+		if timestamp > 1e12 { // 毫秒级时间戳
+			return time.UnixMilli(timestamp), true
+		}
+		if timestamp > 1e9 { // 排除 t=1 之类的非时间戳参数
+			return time.Unix(timestamp, 0), true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,93 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// 直链解析器
+//
+// 链接本身即可被客户端直接访问
+type DirectResolver struct{}
+
+func (r *DirectResolver) Name() string {
+	return "direct"
+}
+
+func (r *DirectResolver) Resolve(ctx context.Context, link string, userAgent string) (string, error) {
+	return link, nil
+}
+
+// HTTP 重定向解析器
+//
+// 发送 HEAD 请求并跟随重定向，返回最终的链接
+type HTTPResolver struct {
+	client       *http.Client
+	maxRedirects int
+}
+
+func NewHTTPResolver() *HTTPResolver {
+	return &HTTPResolver{
+		client: &http.Client{
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse // 手动处理重定向
+			},
+		},
+		maxRedirects: 10,
+	}
+}
+
+func (r *HTTPResolver) Name() string {
+	return "http"
+}
+
+func (r *HTTPResolver) Resolve(ctx context.Context, link string, userAgent string) (string, error) {
+	current := link
+	for range r.maxRedirects {
+		location, err := headLocation(ctx, r.client, current, userAgent, "", "")
+		if err != nil {
+			return "", err
+		}
+		if location == "" {
+			return current, nil
+		}
+		current = location
+	}
+	return "", fmt.Errorf("重定向次数过多：%s", link)
+}
+
+// 发送 HEAD 请求，返回重定向地址
+//
+// 未发生重定向时返回空字符串
+func headLocation(ctx context.Context, client *http.Client, link string, userAgent string, username string, password string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, link, nil)
+	if err != nil {
+		return "", err
+	}
+	if userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+	if username != "" {
+		req.SetBasicAuth(username, password)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 300 && resp.StatusCode < 400:
+		location, err := resp.Location()
+		if err != nil {
+			return "", fmt.Errorf("重定向响应缺少 Location：%w", err)
+		}
+		return location.String(), nil
+	case resp.StatusCode >= 400 && resp.StatusCode != http.StatusMethodNotAllowed: // 部分服务器不支持 HEAD 请求
+		return "", fmt.Errorf("请求 %s 失败：%d", (&url.URL{Scheme: req.URL.Scheme, Host: req.URL.Host, Path: req.URL.Path}).String(), resp.StatusCode)
+	default:
+		return "", nil
+	}
+}
@@ -0,0 +1,19 @@
+package resolver
+
+import (
+	"MediaWarp/internal/rclone"
+	"context"
+)
+
+// rclone 解析器
+//
+// 调用 rclone 后端的 get-download-url 命令获取下载链接
+type RcloneResolver struct{}
+
+func (r *RcloneResolver) Name() string {
+	return "rclone"
+}
+
+func (r *RcloneResolver) Resolve(ctx context.Context, link string, userAgent string) (string, error) {
+	return rclone.GlobalClient.GetDownloadURL(ctx, link, userAgent)
+}
@@ -0,0 +1,159 @@
+package resolver
+
+import (
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/limiter"
+	"MediaWarp/internal/logging"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// 链接解析器
+//
+// 将 Strm 文件中的链接解析为客户端可以直接访问的下载链接
+type LinkResolver interface {
+	Name() string                                                               // 解析器名称
+	Resolve(ctx context.Context, link string, userAgent string) (string, error) // 解析链接
+}
+
+var (
+	registry = builtinResolvers()      // 协议名称 -> 解析器
+	remotes  = make(map[string]string) // 协议名称 -> rclone 远程名称，用于限流和熔断
+	mutex    sync.RWMutex
+
+	fallbackResolver LinkResolver = &RcloneResolver{} // 未注册的协议交给 rclone 处理
+)
+
+// 内置解析器，HTTP 链接无需解析
+func builtinResolvers() map[string]LinkResolver {
+	return map[string]LinkResolver{
+		"http":  &DirectResolver{},
+		"https": &DirectResolver{},
+	}
+}
+
+// 注册解析器
+//
+// 同一协议重复注册时覆盖之前的解析器，调用 Init 后只保留内置解析器和 MediaSync 的解析器
+func Register(scheme string, linkResolver LinkResolver) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	registry[strings.ToLower(scheme)] = linkResolver
+}
+
+// 获取链接的协议名称
+//
+// 115://path/to/file -> 115
+func Scheme(link string) string {
+	index := strings.Index(link, "://")
+	if index == -1 {
+		return ""
+	}
+	return strings.ToLower(link[:index])
+}
+
+// 获取链接所属的远程存储名称
+//
+// MediaSync 中配置的协议返回对应的 rclone 远程名称（与目录列表共用限流和熔断），其余返回协议名称
+func Remote(link string) string {
+	scheme := Scheme(link)
+	mutex.RLock()
+	defer mutex.RUnlock()
+	if remote, ok := remotes[scheme]; ok {
+		return remote
+	}
+	return scheme
+}
+
+// 获取链接对应的解析器
+func Get(link string) LinkResolver {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	if linkResolver, ok := registry[Scheme(link)]; ok {
+		return linkResolver
+	}
+	return fallbackResolver
+}
+
+// 解析链接
+//
+// 按链接所属的远程存储进行限流和熔断，避免单个网盘被限流时影响其他网盘
+func Resolve(ctx context.Context, link string, userAgent string) (string, error) {
+	linkResolver := Get(link)
+	logging.Debug("使用 ", linkResolver.Name(), " 解析器解析链接：", link)
+	var redirectURL string
+	err := limiter.Do(ctx, Remote(link), func() error {
+		var err error
+		redirectURL, err = linkResolver.Resolve(ctx, link, userAgent)
+		return err
+	})
+	return redirectURL, err
+}
+
+// 链接是否需要解析
+//
+// 没有协议的路径和使用 DirectResolver 的链接无需解析
+func NeedResolve(link string) bool {
+	if Scheme(link) == "" {
+		return false
+	}
+	_, direct := Get(link).(*DirectResolver)
+	return !direct
+}
+
+// 根据配置创建解析器
+func New(setting config.ResolverSetting) (LinkResolver, error) {
+	switch strings.ToLower(setting.Type) {
+	case "", "rclone":
+		return &RcloneResolver{}, nil
+	case "direct":
+		return &DirectResolver{}, nil
+	case "http":
+		return NewHTTPResolver(), nil
+	case "alist", "openlist":
+		if setting.ADDR == "" {
+			return nil, fmt.Errorf("alist 解析器缺少 ADDR")
+		}
+		return NewAlistResolver(setting.ADDR, setting.Token, setting.PathPrefix), nil
+	case "webdav":
+		if setting.ADDR == "" {
+			return nil, fmt.Errorf("webdav 解析器缺少 ADDR")
+		}
+		return NewWebDAVResolver(setting.ADDR, setting.Username, setting.Password, setting.PathPrefix), nil
+	default:
+		return nil, fmt.Errorf("未知的解析器类型：%s", setting.Type)
+	}
+}
+
+// 初始化解析器
+//
+// 为每个 MediaSync 服务器注册解析器；每次初始化（包括热重载）时从内置解析器重新创建注册表，
+// 移除已删除或改名的 MediaSync 的解析器，初始化失败时保留原注册表
+func Init() error {
+	schemeResolvers := builtinResolvers()
+	schemeRemotes := make(map[string]string)
+	for _, server := range config.MediaSync() {
+		scheme := server.Resolver.Scheme
+		if scheme == "" {
+			scheme = strings.TrimRight(server.Remote, ":/")
+		}
+		if scheme == "" {
+			continue
+		}
+		linkResolver, err := New(server.Resolver)
+		if err != nil {
+			return fmt.Errorf("MediaSync %s 解析器初始化失败：%w", server.Name, err)
+		}
+		schemeResolvers[strings.ToLower(scheme)] = linkResolver
+		if remote := limiter.RemoteName(server.Remote); remote != "" {
+			schemeRemotes[strings.ToLower(scheme)] = remote
+		}
+		logging.Info("MediaSync ", server.Name, " 使用 ", linkResolver.Name(), " 解析器处理 ", scheme, ":// 链接")
+	}
+	mutex.Lock()
+	registry, remotes = schemeResolvers, schemeRemotes
+	mutex.Unlock()
+	return nil
+}
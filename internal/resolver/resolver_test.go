@@ -0,0 +1,219 @@
+package resolver_test
+
+import (
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/logging"
+	"MediaWarp/internal/resolver"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGet(t *testing.T) {
+	testCases := []struct {
+		link string
+		want string
+	}{
+		{"https://example.com/a.mkv", "direct"},
+		{"HTTP://example.com/a.mkv", "direct"},
+		{"115://movie/a.mkv", "rclone"},
+		{"unknown://movie/a.mkv", "rclone"},
+	}
+	for _, tc := range testCases {
+		if got := resolver.Get(tc.link).Name(); got != tc.want {
+			t.Errorf("Get(%q) = %s，期望 %s", tc.link, got, tc.want)
+		}
+	}
+}
+
+func TestInit(t *testing.T) {
+	logging.Init()
+	defer config.Update(func(s *config.Snapshot) {
+		s.MediaSync = config.MediaSyncSetting{
+			{Name: "alist", Remote: "openlist:", Resolver: config.ResolverSetting{Type: "alist", ADDR: "127.0.0.1:5244"}},
+			{Name: "dav", Remote: "dav", Resolver: config.ResolverSetting{Type: "webdav", Scheme: "mydav", ADDR: "http://127.0.0.1:5005"}},
+		}
+	})()
+	if err := resolver.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if got := resolver.Get("openlist://movie/a.mkv").Name(); got != "alist" {
+		t.Errorf("openlist:// 应使用 alist 解析器，实际：%s", got)
+	}
+	if got := resolver.Get("mydav://movie/a.mkv").Name(); got != "webdav" {
+		t.Errorf("mydav:// 应使用 webdav 解析器，实际：%s", got)
+	}
+	for link, want := range map[string]string{"openlist://movie/a.mkv": "openlist", "mydav://movie/a.mkv": "dav", "123://movie/a.mkv": "123"} {
+		if got := resolver.Remote(link); got != want { // 与目录列表按 rclone 远程名称共用限流器
+			t.Errorf("%s 的远程存储名称应为 %s，实际：%s", link, want, got)
+		}
+	}
+
+	config.Update(func(s *config.Snapshot) {
+		s.MediaSync = config.MediaSyncSetting{{Name: "bad", Remote: "bad:", Resolver: config.ResolverSetting{Type: "ftp"}}}
+	})
+	if err := resolver.Init(); err == nil {
+		t.Error("未知的解析器类型应返回错误")
+	}
+	if got := resolver.Get("mydav://movie/a.mkv").Name(); got != "webdav" {
+		t.Errorf("初始化失败时应保留原解析器，实际：%s", got)
+	}
+
+	// 热重载时删除 MediaSync：移除对应的解析器，保留内置解析器
+	config.Update(func(s *config.Snapshot) {
+		s.MediaSync = config.MediaSyncSetting{{Name: "alist", Remote: "openlist:", Resolver: config.ResolverSetting{Type: "alist", ADDR: "127.0.0.1:5244"}}}
+	})
+	if err := resolver.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if got := resolver.Get("mydav://movie/a.mkv").Name(); got != "rclone" {
+		t.Errorf("已删除的 MediaSync 的解析器应移除，实际：%s", got)
+	}
+	if got := resolver.Remote("mydav://movie/a.mkv"); got != "mydav" {
+		t.Errorf("已删除的 MediaSync 的远程存储名称应移除，实际：%s", got)
+	}
+	if got := resolver.Get("openlist://movie/a.mkv").Name(); got != "alist" {
+		t.Errorf("openlist:// 应使用 alist 解析器，实际：%s", got)
+	}
+	if resolver.NeedResolve("https://cdn.example.com/a.mkv") {
+		t.Error("重新初始化后应保留内置的 HTTP 解析器")
+	}
+}
+
+func TestHTTPResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a":
+			http.Redirect(w, r, "/b", http.StatusFound)
+		case "/b":
+			http.Redirect(w, r, "/file.mkv?ua="+r.UserAgent(), http.StatusFound)
+		case "/file.mkv":
+			w.WriteHeader(http.StatusOK)
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	httpResolver := resolver.NewHTTPResolver()
+	got, err := httpResolver.Resolve(context.Background(), server.URL+"/a", "VidHub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != server.URL+"/file.mkv?ua=VidHub" {
+		t.Errorf("解析结果错误：%s", got)
+	}
+	if _, err := httpResolver.Resolve(context.Background(), server.URL+"/loop", ""); err == nil {
+		t.Error("循环重定向应返回错误")
+	}
+	if _, err := httpResolver.Resolve(context.Background(), server.URL+"/missing", ""); err == nil {
+		t.Error("404 应返回错误")
+	}
+}
+
+func TestAlistResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct{ Path string }
+		json.NewDecoder(r.Body).Decode(&req)
+		if r.URL.Path != "/api/fs/get" || r.Header.Get("Authorization") != "alist-token" {
+			w.Write([]byte(`{"code":401,"message":"token is invalidated"}`))
+			return
+		}
+		switch req.Path {
+		case "/115/movie/a.mkv":
+			w.Write([]byte(`{"code":200,"message":"success","data":{"name":"a.mkv","raw_url":"https://cdn.example.com/a.mkv","sign":"abc"}}`))
+		case "/115/movie/b.mkv":
+			w.Write([]byte(`{"code":200,"message":"success","data":{"name":"b.mkv","sign":"xyz"}}`))
+		default:
+			w.Write([]byte(`{"code":500,"message":"object not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	alistResolver := resolver.NewAlistResolver(server.URL, "alist-token", "/115")
+	testCases := []struct {
+		link string
+		want string
+	}{
+		{"alist://movie/a.mkv", "https://cdn.example.com/a.mkv"},
+		{"alist://movie/b.mkv", server.URL + "/d/115/movie/b.mkv?sign=xyz"},
+	}
+	for _, tc := range testCases {
+		got, err := alistResolver.Resolve(context.Background(), tc.link, "")
+		if err != nil {
+			t.Errorf("解析 %s 失败：%s", tc.link, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("解析 %s 结果错误。期望: %s, 实际: %s", tc.link, tc.want, got)
+		}
+	}
+	if _, err := alistResolver.Resolve(context.Background(), "alist://movie/c.mkv", ""); err == nil {
+		t.Error("文件不存在时应返回错误")
+	}
+}
+
+func TestWebDAVResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "user" || password != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/dav/115/movie/a.mkv":
+			http.Redirect(w, r, "https://cdn.example.com/a.mkv", http.StatusFound)
+		case "/dav/115/movie/b.mkv":
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	anonymous := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer anonymous.Close()
+
+	webdavResolver := resolver.NewWebDAVResolver(server.URL+"/dav", "user", "pass", "115")
+	got, err := webdavResolver.Resolve(context.Background(), "webdav://movie/a.mkv", "")
+	if err != nil || got != "https://cdn.example.com/a.mkv" {
+		t.Errorf("重定向解析错误：%s %v", got, err)
+	}
+	got, err = webdavResolver.Resolve(context.Background(), "webdav://movie/b.mkv", "")
+	if err == nil || strings.Contains(got, "pass") || strings.Contains(err.Error(), "pass") {
+		t.Errorf("未重定向时不应将认证信息返回给客户端：%s %v", got, err)
+	}
+
+	anonymousResolver := resolver.NewWebDAVResolver(anonymous.URL+"/dav", "", "", "115")
+	got, err = anonymousResolver.Resolve(context.Background(), "webdav://movie/b.mkv", "")
+	if err != nil || got != anonymous.URL+"/dav/115/movie/b.mkv" {
+		t.Errorf("无需认证的文件地址解析错误：%s %v", got, err)
+	}
+}
+
+func TestParseExpiry(t *testing.T) {
+	testCases := []struct {
+		link string
+		want int64
+		ok   bool
+	}{
+		{"https://cdnfhnfile.115.com/abc/movie.mkv?t=1735689600&u=1&s=104857600&d=xxx", 1735689600, true},
+		{"https://download.123pan.cn/file.mkv?expires=1735689600&sign=abc", 1735689600, true},
+		{"https://example.com/file.mkv?Expires=1735689600000", 1735689600, true},
+		{"https://example.com/file.mkv?t=1", 0, false},
+		{"https://example.com/file.mkv?expires=abc", 0, false},
+		{"https://example.com/file.mkv", 0, false},
+	}
+	for _, tc := range testCases {
+		got, ok := resolver.ParseExpiry(tc.link)
+		if ok != tc.ok || (ok && got.Unix() != tc.want) {
+			t.Errorf("ParseExpiry(%q) = %v, %v，期望 %d, %v", tc.link, got.Unix(), ok, tc.want, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// WebDAV 解析器
+//
+// webdav://path/to/file -> WebDAV 服务器返回的重定向地址；未重定向且无需认证时返回文件地址
+type WebDAVResolver struct {
+	client     *http.Client
+	endpoint   *url.URL
+	username   string
+	password   string
+	pathPrefix string
+}
+
+func NewWebDAVResolver(addr string, username string, password string, pathPrefix string) *WebDAVResolver {
+	endpoint, err := url.Parse(addr)
+	if err != nil || endpoint.Scheme == "" {
+		endpoint = &url.URL{Scheme: "http", Host: addr}
+	}
+	return &WebDAVResolver{
+		client: &http.Client{
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		endpoint:   endpoint,
+		username:   username,
+		password:   password,
+		pathPrefix: pathPrefix,
+	}
+}
+
+func (r *WebDAVResolver) Name() string {
+	return "webdav"
+}
+
+func (r *WebDAVResolver) Resolve(ctx context.Context, link string, userAgent string) (string, error) {
+	fileURL := *r.endpoint
+	fileURL.Path = joinLinkPath(r.endpoint.Path+"/"+r.pathPrefix, link)
+
+	location, err := headLocation(ctx, r.client, fileURL.String(), userAgent, r.username, r.password)
+	if err != nil {
+		return "", err
+	}
+	if location != "" {
+		return location, nil
+	}
+
+	// 需要认证的文件不能重定向，否则会将 WebDAV 的用户名和密码发送给客户端
+	if r.username != "" {
+		return "", fmt.Errorf("WebDAV 服务器未返回重定向地址，文件需要认证才能访问：%s", fileURL.Path)
+	}
+	return fileURL.String(), nil
+}
@@ -10,6 +10,7 @@ import (
 	"html/template"
 	"io/fs"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -24,8 +25,8 @@ func InitRouter() *gin.Engine {
 		middleware.AntiBotMiddleware(), // 防爬虫中间件
 	)
 
-	if config.ClientFilter.Enable {
-		ginR.Use(middleware.ClientFilter())
+	ginR.Use(middleware.ClientFilter()) // 按配置决定是否过滤，热重载后生效
+	if config.ClientFilter().Enable {
 		logging.Info("客户端过滤中间件已启用")
 	} else {
 		logging.Info("客户端过滤中间件未启用")
@@ -44,14 +45,28 @@ func InitRouter() *gin.Engine {
 	{
 
 		mediawarpRouter.Any("/version", func(ctx *gin.Context) {
-			ctx.JSON(http.StatusOK, config.Version())
+			ctx.JSON(http.StatusOK, struct {
+				*config.VersionInfo
+				Upstreams []handler.UpstreamInfo
+			}{config.Version(), handler.GetUpstreams()})
 		})
-		if config.Web.Enable { // 启用 Web 页面修改相关设置
-			mediawarpRouter.StaticFS("/static", http.FS(static.EmbeddedStaticAssets))
-			if config.Web.Custom { // 用户自定义静态资源目录
-				mediawarpRouter.Static("/custom", config.CostomDir())
-			}
+		// 路由试运行：查看请求会命中的自定义路由规则和内置路由
+		mediawarpRouter.GET("/rules/match", handler.APIKeyAuth(), handler.RouteMatchHandler)
+		mediawarpRouter.POST("/config/reload", handler.APIKeyAuth(), reloadConfigHandler) // 热重载配置
+
+		// 以下路由始终注册，按功能开关决定是否可用，热重载后生效
+		subtitleRouter := mediawarpRouter.Group("/subtitles", featureGate(func() bool { return config.Subtitle().Enable }))
+		{ // 字幕时间轴调整：需要携带媒体服务器的用户认证令牌
+			subtitleRouter.GET("/:itemId/:mediaSourceId/:index/offset", handler.UserTokenAuth(), handler.GetSubtitleOffset)
+			subtitleRouter.POST("/:itemId/:mediaSourceId/:index/offset", handler.UserTokenAuth(), handler.SetSubtitleOffset)
+			subtitleRouter.DELETE("/:itemId/:mediaSourceId/:index/offset", handler.UserTokenAuth(), handler.DeleteSubtitleOffset)
 		}
+		// 启用 Web 页面修改相关设置
+		mediawarpRouter.Group("/static", featureGate(func() bool { return config.Web().Enable })).
+			StaticFS("/", http.FS(static.EmbeddedStaticAssets))
+		// 用户自定义静态资源目录
+		mediawarpRouter.Group("/custom", featureGate(func() bool { return config.Web().Enable && config.Web().Custom })).
+			Static("/", config.CostomDir())
 
 	}
 	// 根路径重定向到 Emby 服务器
@@ -122,10 +137,25 @@ Crawl-delay: 86400`)
 
 // 正则表达式路由处理器
 //
-// 从媒体服务器处理结构体中获取正则路由规则
+// 先按配置顺序匹配自定义路由规则，再根据请求选择上游媒体服务器，从媒体服务器处理结构体中获取正则路由规则
 // 依次匹配请求, 找到对应的处理器
 func RegexpRouterHandler(ctx *gin.Context) {
-	mediaServerHandler := handler.GetMediaServer()
+	for _, rule := range handler.GetCustomRouteRules() { // 自定义路由规则，Rewrite、Header 规则修改请求后继续匹配
+		if rule.Regexp.MatchString(ctx.Request.URL.Path) {
+			logging.Debugf("URL: %s 匹配自定义路由规则 -> %s", ctx.Request.URL.Path, rule.Regexp.String())
+			rule.Handler(ctx)
+			if ctx.IsAborted() {
+				return
+			}
+		}
+	}
+
+	mediaServerHandler, err := handler.GetMediaServerForRequest(ctx.Request)
+	if err != nil {
+		logging.Warning(err)
+		ctx.String(http.StatusNotFound, err.Error())
+		return
+	}
 
 	for _, rule := range mediaServerHandler.GetRegexpRouteRules() {
 		if rule.Regexp.MatchString(ctx.Request.URL.Path) { // 不带查询参数的字符串：/emby/Items/54/Images/Primary
@@ -138,3 +168,47 @@ func RegexpRouterHandler(ctx *gin.Context) {
 	// 未匹配路由
 	mediaServerHandler.ReverseProxy(ctx.Writer, ctx.Request)
 }
+
+// 功能开关中间件
+//
+// 功能关闭时返回 404
+func featureGate(enabled func() bool) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !enabled() {
+			ctx.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// 热重载配置并记录结果
+//
+// 返回已修改但需要重启才能生效的配置项
+func ReloadConfig() ([]string, error) {
+	restartRequired, err := config.Reload()
+	if err != nil {
+		logging.Warning("配置热重载失败，继续使用原配置：", err)
+		return nil, err
+	}
+	logging.Info("配置热重载成功")
+	for _, warning := range config.Warnings() {
+		logging.Warning("配置验证警告：", warning)
+	}
+	if len(restartRequired) > 0 {
+		logging.Warning("以下配置需要重启后生效：", strings.Join(restartRequired, "、"))
+	}
+	return restartRequired, nil
+}
+
+// 热重载配置
+//
+// POST /MediaWarp/config/reload，需要 X-API-Key 请求头
+func reloadConfigHandler(ctx *gin.Context) {
+	restartRequired, err := ReloadConfig()
+	if err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"restart_required": restartRequired})
+}
@@ -0,0 +1,106 @@
+package alist
+
+import (
+	"MediaWarp/internal/logging"
+	"MediaWarp/utils"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// 请求 Alist API 的超时时间
+const requestTimeout = 30 * time.Second
+
+type AlistServer struct {
+	client   *http.Client
+	endpoint string
+	token    string // 认证方式：Token；获取方式：Alist/OpenList 管理 -> 设置 -> 其他 -> 令牌
+}
+
+// 获取Alist连接地址
+//
+// 包含协议、服务器域名（IP）、端口号
+// 示例：return "http://alist.example.com:5244"
+func (alistServer *AlistServer) GetEndpoint() string {
+	return alistServer.endpoint
+}
+
+// 获取Alist的Token
+func (alistServer *AlistServer) GetToken() string {
+	return alistServer.token
+}
+
+// 获取文件信息
+//
+// POST /api/fs/get
+func (alistServer *AlistServer) FsGet(ctx context.Context, path string) (*FsGetData, error) {
+	reqBody, err := json.Marshal(FsGetRequest{Path: path})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, alistServer.GetEndpoint()+"/api/fs/get", bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if alistServer.GetToken() != "" {
+		req.Header.Set("Authorization", alistServer.GetToken())
+	}
+	resp, err := alistServer.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.Debug("Alist API raw response body: ", string(body))
+	var fsGetResponse Response[FsGetData]
+	if err = json.Unmarshal(body, &fsGetResponse); err != nil {
+		return nil, err
+	}
+	if fsGetResponse.Code != http.StatusOK {
+		return nil, fmt.Errorf("Alist API 请求失败：%d %s", fsGetResponse.Code, fsGetResponse.Message)
+	}
+	return &fsGetResponse.Data, nil
+}
+
+// 获取文件下载链接
+//
+// rawURL 为 true 时优先使用 raw_url，否则（或 raw_url 为空时）使用 Alist 的 /d 下载链接
+func (alistServer *AlistServer) GetDownloadURL(ctx context.Context, path string, rawURL bool) (string, *FsGetData, error) {
+	data, err := alistServer.FsGet(ctx, path)
+	if err != nil {
+		return "", nil, err
+	}
+	if data.IsDir {
+		return "", nil, fmt.Errorf("%s 是文件夹", path)
+	}
+	if rawURL && data.RawURL != "" {
+		return data.RawURL, data, nil
+	}
+
+	downloadURL := alistServer.GetEndpoint() + "/d" + (&url.URL{Path: path}).EscapedPath()
+	if data.Sign != "" {
+		downloadURL += "?sign=" + data.Sign
+	}
+	return downloadURL, data, nil
+}
+
+// 获取AlistServer实例
+func New(addr string, token string) *AlistServer {
+	alist := &AlistServer{
+		client:   &http.Client{Timeout: requestTimeout},
+		endpoint: utils.GetEndpoint(addr),
+		token:    token,
+	}
+	return alist
+}
@@ -0,0 +1,25 @@
+package alist
+
+// Alist API 通用响应
+type Response[T any] struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    T      `json:"data"`
+}
+
+// /api/fs/get 请求体
+type FsGetRequest struct {
+	Path     string `json:"path"`
+	Password string `json:"password"`
+}
+
+// /api/fs/get 响应数据
+type FsGetData struct {
+	Name     string `json:"name"`
+	Size     int64  `json:"size"`
+	IsDir    bool   `json:"is_dir"`
+	Modified string `json:"modified"`
+	Sign     string `json:"sign"`
+	RawURL   string `json:"raw_url"`
+	Provider string `json:"provider"`
+}
@@ -4,14 +4,20 @@ import (
 	"MediaWarp/constants"
 	"MediaWarp/internal/logging"
 	"MediaWarp/utils"
+	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 )
 
+const requestTimeout = 30 * time.Second // 请求 Emby API 的超时时间
+
 type EmbyServer struct {
+	client   *http.Client
 	endpoint string
 	apiKey   string // 认证方式：APIKey；获取方式：Emby控制台 -> 高级 -> API密钥
 }
@@ -47,7 +53,7 @@ func (embyServer *EmbyServer) ItemsServiceQueryItem(ids string, limit int, field
 	params.Add("Recursive", "true")
 	params.Add("api_key", embyServer.GetAPIKey())
 	api := embyServer.GetEndpoint() + "/Items?" + params.Encode()
-	resp, err := http.Get(api)
+	resp, err := embyServer.client.Get(api)
 	if err != nil {
 		return nil, err
 	}
@@ -66,9 +72,124 @@ func (embyServer *EmbyServer) ItemsServiceQueryItem(ids string, limit int, field
 	return itemResponse, nil
 }
 
+// 请求 API 并解析 JSON 响应
+func (embyServer *EmbyServer) getJSON(path string, params url.Values, v any) error {
+	params.Set("api_key", embyServer.GetAPIKey())
+	resp, err := embyServer.client.Get(embyServer.GetEndpoint() + path + "?" + params.Encode())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("请求 %s 失败，状态码：%d", path, resp.StatusCode)
+	}
+	return json.Unmarshal(body, v)
+}
+
+// 获取剧集列表
+//
+// /Shows/:seriesId/Episodes
+// 从 startItemID 开始（包含）按播放顺序返回最多 limit 集
+func (embyServer *EmbyServer) ShowsServiceGetEpisodes(seriesID string, userID string, startItemID string, limit int, fields string) (*EmbyResponse, error) {
+	var (
+		params   = url.Values{}
+		response = &EmbyResponse{}
+	)
+	if userID != "" {
+		params.Add("UserId", userID)
+	}
+	params.Add("StartItemId", startItemID)
+	params.Add("Limit", strconv.Itoa(limit))
+	params.Add("Fields", fields)
+	if err := embyServer.getJSON("/Shows/"+url.PathEscape(seriesID)+"/Episodes", params, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// 获取继续观看列表
+//
+// /Users/:userId/Items/Resume
+func (embyServer *EmbyServer) ItemsServiceGetResumeItems(userID string, limit int, fields string) (*EmbyResponse, error) {
+	var (
+		params   = url.Values{}
+		response = &EmbyResponse{}
+	)
+	params.Add("Limit", strconv.Itoa(limit))
+	params.Add("Fields", fields)
+	params.Add("MediaTypes", "Video")
+	params.Add("Recursive", "true")
+	if err := embyServer.getJSON("/Users/"+url.PathEscape(userID)+"/Items/Resume", params, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// 获取会话列表
+//
+// /Sessions
+// deviceID 不为空时只返回该设备的会话
+func (embyServer *EmbyServer) SessionsServiceGetSessions(deviceID string) ([]SessionInfo, error) {
+	var (
+		params   = url.Values{}
+		sessions []SessionInfo
+	)
+	if deviceID != "" {
+		params.Add("DeviceId", deviceID)
+	}
+	if err := embyServer.getJSON("/Sessions", params, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// 获取字幕流 API：/Videos/:itemId/:mediaSourceId/Subtitles/:index/Stream.srt
+//
+// 使用客户端的认证令牌请求，调用方需关闭响应体
+func (embyServer *EmbyServer) VideosServiceGetSubtitle(ctx context.Context, itemID string, mediaSourceID string, index int64, token string) (*http.Response, error) {
+	api := fmt.Sprintf("%s/Videos/%s/%s/Subtitles/%d/Stream.srt", embyServer.GetEndpoint(), url.PathEscape(itemID), url.PathEscape(mediaSourceID), index)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, api, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("X-Emby-Token", token)
+	}
+	return embyServer.client.Do(req)
+}
+
+// 验证用户认证令牌 API：/System/Info
+//
+// 该接口需要登录，令牌有效时返回 true；上游返回 401、403 以外的异常状态码时返回错误
+func (embyServer *EmbyServer) ValidateToken(ctx context.Context, token string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, embyServer.GetEndpoint()+"/System/Info", nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("X-Emby-Token", token)
+	resp, err := embyServer.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return false, nil
+	default:
+		return false, fmt.Errorf("Emby API 响应状态码异常：%d", resp.StatusCode)
+	}
+}
+
 // 获取index.html内容 API：/web/index.html
 func (embyServer *EmbyServer) GetIndexHtml() ([]byte, error) {
-	resp, err := http.Get(embyServer.GetEndpoint() + "/web/index.html")
+	resp, err := embyServer.client.Get(embyServer.GetEndpoint() + "/web/index.html")
 	if err != nil {
 		return nil, err
 	}
@@ -84,6 +205,7 @@ func (embyServer *EmbyServer) GetIndexHtml() ([]byte, error) {
 // 获取EmbyServer实例
 func New(addr string, apiKey string) *EmbyServer {
 	emby := &EmbyServer{
+		client:   &http.Client{Timeout: requestTimeout},
 		endpoint: utils.GetEndpoint(addr),
 		apiKey:   apiKey,
 	}
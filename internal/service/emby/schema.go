@@ -179,6 +179,25 @@ type BaseItemDto struct {
 	Width                        *int64                   `json:"Width"`
 }
 
+// Session.SessionInfo
+type SessionInfo struct {
+	ID             *string      `json:"Id,omitempty"`
+	UserID         *string      `json:"UserId,omitempty"`
+	UserName       *string      `json:"UserName,omitempty"`
+	DeviceID       *string      `json:"DeviceId,omitempty"`
+	Client         *string      `json:"Client,omitempty"`
+	NowPlayingItem *BaseItemDto `json:"NowPlayingItem,omitempty"`
+}
+
+// PlaybackStartInfo
+//
+// /Sessions/Playing 的请求体
+type PlaybackStartInfo struct {
+	ItemID        *string `json:"ItemId,omitempty"`
+	MediaSourceID *string `json:"MediaSourceId,omitempty"`
+	PlaySessionID *string `json:"PlaySessionId,omitempty"`
+}
+
 // NameIdPair
 type NameIDPair struct {
 	ID   *string `json:"Id,omitempty"`
@@ -0,0 +1,114 @@
+package jellyfin
+
+import (
+	"MediaWarp/constants"
+	"MediaWarp/internal/logging"
+	"MediaWarp/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const requestTimeout = 30 * time.Second // 请求 Jellyfin API 的超时时间
+
+type JellyfinServer struct {
+	client   *http.Client
+	endpoint string
+	apiKey   string // 认证方式：APIKey；获取方式：Jellyfin控制台 -> 高级 -> API密钥
+}
+
+// 获取媒体服务器类型
+func (jellyfinServer *JellyfinServer) GetType() constants.MediaServerType {
+	return constants.JELLYFIN
+}
+
+// 获取Jellyfin连接地址
+//
+// 包含协议、服务器域名（IP）、端口号
+// 示例：return "http://jellyfin.example.com:8096"
+func (jellyfinServer *JellyfinServer) GetEndpoint() string {
+	return jellyfinServer.endpoint
+}
+
+// 获取Jellyfin的API Key
+func (jellyfinServer *JellyfinServer) GetAPIKey() string {
+	return jellyfinServer.apiKey
+}
+
+// ItemsService
+// /Items
+func (jellyfinServer *JellyfinServer) ItemsServiceQueryItem(ids string, limit int, fields string) (*Response, error) {
+	var (
+		params       = url.Values{}
+		itemResponse = &Response{}
+	)
+	params.Add("Ids", ids)
+	params.Add("Limit", strconv.Itoa(limit))
+	params.Add("Fields", fields)
+	params.Add("Recursive", "true")
+	api := jellyfinServer.GetEndpoint() + "/Items?" + params.Encode()
+	req, err := http.NewRequest(http.MethodGet, api, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf(`MediaBrowser Token="%s"`, jellyfinServer.GetAPIKey()))
+	resp, err := jellyfinServer.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Jellyfin API 响应状态码异常：%d", resp.StatusCode)
+	}
+
+	logging.Debug("Jellyfin API raw response body: ", string(body))
+	err = json.Unmarshal(body, itemResponse)
+	if err != nil {
+		return nil, err
+	}
+	return itemResponse, nil
+}
+
+// 验证用户认证令牌 API：/System/Info
+//
+// 该接口需要登录，令牌有效时返回 true；上游返回 401、403 以外的异常状态码时返回错误
+func (jellyfinServer *JellyfinServer) ValidateToken(ctx context.Context, token string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jellyfinServer.GetEndpoint()+"/System/Info", nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf(`MediaBrowser Token="%s"`, token))
+	resp, err := jellyfinServer.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return false, nil
+	default:
+		return false, fmt.Errorf("Jellyfin API 响应状态码异常：%d", resp.StatusCode)
+	}
+}
+
+// 获取Jellyfin实例
+func New(addr string, apiKey string) *JellyfinServer {
+	jellyfin := &JellyfinServer{
+		client:   &http.Client{Timeout: requestTimeout},
+		endpoint: utils.GetEndpoint(addr),
+		apiKey:   apiKey,
+	}
+	return jellyfin
+}
@@ -0,0 +1,92 @@
+package jellyfin
+
+import "encoding/json"
+
+// 未显式声明的字段使用 json.RawMessage 原样保留，避免修改响应时丢失上游返回的数据
+
+type Response struct {
+	Items            []BaseItemDto `json:"Items,omitempty"`
+	TotalRecordCount *int64        `json:"TotalRecordCount,omitempty"`
+	StartIndex       *int64        `json:"StartIndex,omitempty"`
+}
+
+// /Items/:itemID/PlaybackInfo的响应
+type PlaybackInfoResponse struct {
+	ErrorCode     *string           `json:"ErrorCode,omitempty"`
+	MediaSources  []MediaSourceInfo `json:"MediaSources,omitempty"`
+	PlaySessionID *string           `json:"PlaySessionId,omitempty"`
+}
+
+// BaseItemDto（仅包含 MediaWarp 需要使用的字段）
+type BaseItemDto struct {
+	ID           *string           `json:"Id,omitempty"`
+	Name         *string           `json:"Name,omitempty"`
+	Path         *string           `json:"Path,omitempty"`
+	Type         *string           `json:"Type,omitempty"`
+	MediaType    *string           `json:"MediaType,omitempty"`
+	SeriesID     *string           `json:"SeriesId,omitempty"`
+	MediaSources []MediaSourceInfo `json:"MediaSources,omitempty"`
+}
+
+// MediaSourceInfo
+type MediaSourceInfo struct {
+	Protocol                            *MediaProtocol    `json:"Protocol,omitempty"`
+	ID                                  *string           `json:"Id,omitempty"`
+	Path                                *string           `json:"Path,omitempty"` // 本地视频文件则是正常的本地路径，Strm 则是 Strm 文件的内容
+	EncoderPath                         *string           `json:"EncoderPath,omitempty"`
+	EncoderProtocol                     *MediaProtocol    `json:"EncoderProtocol,omitempty"`
+	Type                                *string           `json:"Type,omitempty"`
+	Container                           *string           `json:"Container,omitempty"`
+	Size                                *int64            `json:"Size,omitempty"`
+	Name                                *string           `json:"Name,omitempty"`
+	IsRemote                            *bool             `json:"IsRemote,omitempty"` // HTTPStrm 会被设置为 true
+	ETag                                *string           `json:"ETag,omitempty"`
+	RunTimeTicks                        *int64            `json:"RunTimeTicks,omitempty"`
+	ReadAtNativeFramerate               *bool             `json:"ReadAtNativeFramerate,omitempty"`
+	IgnoreDts                           *bool             `json:"IgnoreDts,omitempty"`
+	IgnoreIndex                         *bool             `json:"IgnoreIndex,omitempty"`
+	GenPtsInput                         *bool             `json:"GenPtsInput,omitempty"`
+	SupportsTranscoding                 *bool             `json:"SupportsTranscoding,omitempty"`
+	SupportsDirectStream                *bool             `json:"SupportsDirectStream,omitempty"`
+	SupportsDirectPlay                  *bool             `json:"SupportsDirectPlay,omitempty"`
+	IsInfiniteStream                    *bool             `json:"IsInfiniteStream,omitempty"`
+	UseMostCompatibleTranscodingProfile *bool             `json:"UseMostCompatibleTranscodingProfile,omitempty"`
+	RequiresOpening                     *bool             `json:"RequiresOpening,omitempty"`
+	OpenToken                           *string           `json:"OpenToken,omitempty"`
+	RequiresClosing                     *bool             `json:"RequiresClosing,omitempty"`
+	LiveStreamID                        *string           `json:"LiveStreamId,omitempty"`
+	BufferMS                            *int64            `json:"BufferMs,omitempty"`
+	RequiresLooping                     *bool             `json:"RequiresLooping,omitempty"`
+	SupportsProbing                     *bool             `json:"SupportsProbing,omitempty"`
+	VideoType                           *string           `json:"VideoType,omitempty"`
+	IsoType                             *string           `json:"IsoType,omitempty"`
+	Video3DFormat                       *string           `json:"Video3DFormat,omitempty"`
+	MediaStreams                        []json.RawMessage `json:"MediaStreams"`
+	MediaAttachments                    []json.RawMessage `json:"MediaAttachments,omitempty"`
+	Formats                             []string          `json:"Formats,omitempty"`
+	Bitrate                             *int64            `json:"Bitrate,omitempty"`
+	FallbackMaxStreamingBitrate         *int64            `json:"FallbackMaxStreamingBitrate,omitempty"`
+	Timestamp                           *string           `json:"Timestamp,omitempty"`
+	RequiredHTTPHeaders                 map[string]string `json:"RequiredHttpHeaders,omitempty"`
+	TranscodingURL                      *string           `json:"TranscodingUrl,omitempty"`
+	TranscodingSubProtocol              *string           `json:"TranscodingSubProtocol,omitempty"`
+	TranscodingContainer                *string           `json:"TranscodingContainer,omitempty"`
+	AnalyzeDurationMS                   *int64            `json:"AnalyzeDurationMs,omitempty"`
+	DefaultAudioStreamIndex             *int64            `json:"DefaultAudioStreamIndex,omitempty"`
+	DefaultSubtitleStreamIndex          *int64            `json:"DefaultSubtitleStreamIndex,omitempty"`
+	HasSegments                         *bool             `json:"HasSegments,omitempty"`
+}
+
+// MediaProtocol
+type MediaProtocol string
+
+const (
+	File MediaProtocol = "File"
+	HTTP MediaProtocol = "Http"
+	Rtmp MediaProtocol = "Rtmp"
+	Rtsp MediaProtocol = "Rtsp"
+	Udp  MediaProtocol = "Udp"
+	Rtp  MediaProtocol = "Rtp"
+	Ftp  MediaProtocol = "Ftp"
+	Mms  MediaProtocol = "Mms"
+)
@@ -0,0 +1,98 @@
+package plex
+
+import (
+	"MediaWarp/constants"
+	"MediaWarp/internal/logging"
+	"MediaWarp/utils"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const requestTimeout = 30 * time.Second // 请求 Plex API 的超时时间
+
+type PlexServer struct {
+	client   *http.Client
+	endpoint string
+	token    string // 认证方式：X-Plex-Token；获取方式：Plex Web -> 媒体信息 -> 查看 XML
+}
+
+// 获取媒体服务器类型
+func (plexServer *PlexServer) GetType() constants.MediaServerType {
+	return constants.PLEX
+}
+
+// 获取Plex连接地址
+//
+// 包含协议、服务器域名（IP）、端口号
+// 示例：return "http://plex.example.com:32400"
+func (plexServer *PlexServer) GetEndpoint() string {
+	return plexServer.endpoint
+}
+
+// 获取Plex的Token
+func (plexServer *PlexServer) GetToken() string {
+	return plexServer.token
+}
+
+// 获取媒体元数据
+//
+// /library/metadata/:ratingKey
+func (plexServer *PlexServer) GetMetadata(ratingKey string) (*Response, error) {
+	return plexServer.getJSON("/library/metadata/" + url.PathEscape(ratingKey))
+}
+
+// 获取媒体文件分段
+//
+// /library/parts/:partId
+func (plexServer *PlexServer) GetPart(partID string) (*Response, error) {
+	return plexServer.getJSON("/library/parts/" + url.PathEscape(partID))
+}
+
+// 请求 API 并解析 JSON 响应
+func (plexServer *PlexServer) getJSON(path string) (*Response, error) {
+	var (
+		params           = url.Values{}
+		metadataResponse = &Response{}
+	)
+	params.Add("X-Plex-Token", plexServer.GetToken())
+	api := plexServer.GetEndpoint() + path + "?" + params.Encode()
+	req, err := http.NewRequest(http.MethodGet, api, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json") // Plex 默认返回 XML
+	resp, err := plexServer.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Plex API 响应状态码异常：%d", resp.StatusCode)
+	}
+
+	logging.Debug("Plex API raw response body: ", string(body))
+	err = json.Unmarshal(body, metadataResponse)
+	if err != nil {
+		return nil, err
+	}
+	return metadataResponse, nil
+}
+
+// 获取PlexServer实例
+func New(addr string, token string) *PlexServer {
+	plex := &PlexServer{
+		client:   &http.Client{Timeout: requestTimeout},
+		endpoint: utils.GetEndpoint(addr),
+		token:    token,
+	}
+	return plex
+}
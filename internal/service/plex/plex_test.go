@@ -0,0 +1,48 @@
+package plex_test
+
+import (
+	"MediaWarp/internal/logging"
+	"MediaWarp/internal/service/plex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const metadataJSON = `{"MediaContainer":{"size":1,"Metadata":[{"ratingKey":"1234","key":"/library/metadata/1234","type":"movie","title":"Strm Movie","Media":[{"id":55,"container":"strm","Part":[{"id":777,"key":"/library/parts/777/1700000000/file.strm","file":"/media/115/Movie (2024)/Movie (2024).strm","size":20}]}]}]}}`
+
+func TestGetMetadata(t *testing.T) {
+	logging.Init()
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/library/metadata/1234" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("X-Plex-Token") != "plex-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get("Accept") != "application/json" {
+			t.Errorf("Accept 请求头错误。期望: application/json, 实际: %s", r.Header.Get("Accept"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(metadataJSON))
+	}))
+	defer stub.Close()
+
+	server := plex.New(stub.URL, "plex-token")
+	resp, err := server.GetMetadata("1234")
+	if err != nil {
+		t.Fatalf("获取元数据失败：%s", err)
+	}
+	if len(resp.MediaContainer.Metadata) != 1 {
+		t.Fatalf("元数据数量错误。期望: 1, 实际: %d", len(resp.MediaContainer.Metadata))
+	}
+	part := resp.MediaContainer.Metadata[0].Media[0].Part[0]
+	if part.ID != 777 || part.File != "/media/115/Movie (2024)/Movie (2024).strm" {
+		t.Errorf("Part 解析错误：%+v", part)
+	}
+
+	if _, err := plex.New(stub.URL, "wrong-token").GetMetadata("1234"); err == nil {
+		t.Error("Token 错误时应返回错误")
+	}
+}
@@ -0,0 +1,49 @@
+package plex
+
+// /library/metadata/:ratingKey、/library/parts/:partId 的响应（仅包含 MediaWarp 需要使用的字段）
+type Response struct {
+	MediaContainer MediaContainer `json:"MediaContainer"`
+}
+
+type MediaContainer struct {
+	Size     int        `json:"size"`
+	Metadata []Metadata `json:"Metadata,omitempty"`
+	Part     []Part     `json:"Part,omitempty"` // /library/parts/:partId 直接返回 Part
+}
+
+// 遍历响应中的全部 Part
+func (container MediaContainer) Parts() []Part {
+	parts := container.Part
+	for _, metadata := range container.Metadata {
+		for _, media := range metadata.Media {
+			parts = append(parts, media.Part...)
+		}
+	}
+	return parts
+}
+
+// 媒体元数据
+type Metadata struct {
+	RatingKey string  `json:"ratingKey"`
+	Key       string  `json:"key"`
+	Type      string  `json:"type"`
+	Title     string  `json:"title"`
+	Media     []Media `json:"Media,omitempty"`
+}
+
+// 媒体版本
+type Media struct {
+	ID         int64  `json:"id"`
+	Container  string `json:"container,omitempty"`
+	VideoCodec string `json:"videoCodec,omitempty"`
+	Part       []Part `json:"Part,omitempty"`
+}
+
+// 媒体文件分段
+type Part struct {
+	ID        int64  `json:"id"`
+	Key       string `json:"key"`  // /library/parts/:partId/:changestamp/file.mkv
+	File      string `json:"file"` // 媒体文件在 Plex 服务器上的路径
+	Size      int64  `json:"size,omitempty"`
+	Container string `json:"container,omitempty"`
+}
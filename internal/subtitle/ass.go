@@ -0,0 +1,225 @@
+package subtitle
+
+import (
+	"MediaWarp/utils"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// 默认 ASS 样式
+var defaultASSStyle = []string{
+	"Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding",
+	"Style: Default,Arial,20,&H00FFFFFF,&H000000FF,&H00000000,&H00000000,0,0,0,0,100,100,0,0,1,2,1,2,10,10,10,1",
+}
+
+// 缺少 Format 行时使用的事件格式（SSA 的第一列为 Marked，列数相同）
+var defaultEventFormat = []string{"Layer", "Start", "End", "Style", "Name", "MarginL", "MarginR", "MarginV", "Effect", "Text"}
+
+var (
+	assTimePattern     = regexp.MustCompile(`^(\d+):(\d{1,2}):(\d{1,2})[.:](\d{1,3})$`)
+	assOverridePattern = regexp.MustCompile(`\{([^}]*)\}`)
+	assOverrideTag     = regexp.MustCompile(`\\(i|b|u|p)(\d+)`)
+	htmlStyleTag       = regexp.MustCompile(`<(/?)([ibu])>`)
+	htmlFontColorTag   = regexp.MustCompile(`<font\s+color="?#([0-9a-fA-F]{2})([0-9a-fA-F]{2})([0-9a-fA-F]{2})"?\s*>`)
+	htmlOtherTag       = regexp.MustCompile(`</?font[^>]*>`)
+)
+
+// 解析 ASS、SSA 字幕
+//
+// 只解析 [Events] 中的 Dialogue 行；i、b、u 覆盖标签转为对应的标签，绘图和其余覆盖标签移除
+func parseASS(content string) ([]Cue, error) {
+	var (
+		cues    []Cue
+		inEvent bool
+		format  = defaultEventFormat
+	)
+	for _, line := range strings.Split(normalizeNewLine(content), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			inEvent = strings.EqualFold(line, "[Events]")
+			continue
+		}
+		if !inEvent {
+			continue
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "Format":
+			format = strings.Split(value, ",")
+			for i := range format {
+				format[i] = strings.TrimSpace(format[i])
+			}
+		case "Dialogue":
+			fields := strings.SplitN(strings.TrimSpace(value), ",", len(format))
+			if len(fields) != len(format) {
+				continue
+			}
+			var (
+				cue        Cue
+				start, end bool
+			)
+			for i, name := range format {
+				switch strings.ToLower(name) {
+				case "start":
+					cue.Start, start = assTime(fields[i])
+				case "end":
+					cue.End, end = assTime(fields[i])
+				case "text":
+					cue.Text = assText(fields[i])
+				}
+			}
+			if start && end && cue.Text != "" {
+				cues = append(cues, cue)
+			}
+		}
+	}
+	if len(cues) == 0 {
+		return nil, fmt.Errorf("未找到字幕条目")
+	}
+	return cues, nil
+}
+
+// 解析 ASS 时间（H:MM:SS.cc）
+func assTime(value string) (time.Duration, bool) {
+	matches := assTimePattern.FindStringSubmatch(strings.TrimSpace(value))
+	if matches == nil {
+		return 0, false
+	}
+	return clockTime(matches[1], matches[2], matches[3], matches[4]), true
+}
+
+// 转换 ASS 字幕文本
+func assText(text string) string {
+	var (
+		builder strings.Builder
+		open    = make(map[string]bool) // 未闭合的标签
+		drawing bool                    // 绘图模式（\p1）中的文本为绘图指令
+		last    int
+	)
+	write := func(segment string) {
+		if !drawing {
+			builder.WriteString(segment)
+		}
+	}
+	for _, match := range assOverridePattern.FindAllStringSubmatchIndex(text, -1) {
+		write(text[last:match[0]])
+		last = match[1]
+		for _, tag := range assOverrideTag.FindAllStringSubmatch(text[match[2]:match[3]], -1) {
+			name, enabled := tag[1], tag[2] != "0"
+			if name == "p" {
+				drawing = enabled
+				continue
+			}
+			if enabled && !open[name] {
+				builder.WriteString("<" + name + ">")
+			} else if !enabled && open[name] {
+				builder.WriteString("</" + name + ">")
+			}
+			open[name] = enabled
+		}
+	}
+	write(text[last:])
+	for _, name := range []string{"u", "b", "i"} {
+		if open[name] {
+			builder.WriteString("</" + name + ">")
+		}
+	}
+
+	result := builder.String()
+	result = strings.NewReplacer(`\N`, "\n", `\n`, "\n", `\h`, " ").Replace(result)
+	return strings.TrimSpace(result)
+}
+
+// 格式化 ASS 时间
+func formatASSTime(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	cs := d.Milliseconds() / 10
+	return fmt.Sprintf("%d:%02d:%02d.%02d", cs/360000, cs/6000%60, cs/100%60, cs%100)
+}
+
+// 写入 ASS 字幕
+func writeASS(cues []Cue, style []string) []byte {
+	if len(style) == 0 {
+		style = defaultASSStyle
+	}
+	var builder strings.Builder
+	builder.WriteString(utils.ASSHeader1 + "\n")
+	builder.WriteString(strings.Join(style, "\n") + "\n\n")
+	builder.WriteString(utils.ASSHeader2 + "\n")
+	for _, cue := range cues {
+		text := htmlStyleTag.ReplaceAllStringFunc(cue.Text, func(tag string) string {
+			matches := htmlStyleTag.FindStringSubmatch(tag)
+			if matches[1] == "/" {
+				return `{\` + matches[2] + `0}`
+			}
+			return `{\` + matches[2] + `1}`
+		})
+		text = htmlFontColorTag.ReplaceAllString(text, `{\c&H$3$2$1&}`)
+		text = strings.ReplaceAll(text, "</font>", `{\c}`)
+		text = htmlOtherTag.ReplaceAllString(text, "")
+		text = strings.ReplaceAll(text, "\n", `\N`)
+		fmt.Fprintf(&builder, "Dialogue: 0,%s,%s,Default,,0,0,0,,%s\n", formatASSTime(cue.Start), formatASSTime(cue.End), text)
+	}
+	return []byte(builder.String())
+}
+
+// 转换 ASS 字幕 Dialogue 行的文本，覆盖标签保持不变
+func mapASSText(content string, mapping func(string) string) string {
+	var (
+		lines   = strings.Split(content, "\n")
+		inEvent bool
+		fields  = len(defaultEventFormat)
+	)
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			inEvent = strings.EqualFold(trimmed, "[Events]")
+			continue
+		}
+		if !inEvent {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "Format:") {
+			fields = len(strings.Split(trimmed, ","))
+			continue
+		}
+		if !strings.HasPrefix(trimmed, "Dialogue:") {
+			continue
+		}
+
+		// 第 fields-1 个逗号之后为文本
+		offset := 0
+		for n := 0; n < fields-1 && offset >= 0; n++ {
+			next := strings.IndexByte(line[offset:], ',')
+			if next < 0 {
+				offset = -1
+				break
+			}
+			offset += next + 1
+		}
+		if offset < 0 {
+			continue
+		}
+
+		var (
+			text    = line[offset:]
+			builder strings.Builder
+			last    int
+		)
+		for _, match := range assOverridePattern.FindAllStringIndex(text, -1) {
+			builder.WriteString(mapping(text[last:match[0]]))
+			builder.WriteString(text[match[0]:match[1]])
+			last = match[1]
+		}
+		builder.WriteString(mapping(text[last:]))
+		lines[i] = line[:offset] + builder.String()
+	}
+	return strings.Join(lines, "\n")
+}
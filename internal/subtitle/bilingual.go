@@ -0,0 +1,53 @@
+package subtitle
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// 合并双语字幕
+//
+// 每条次要字幕并入与其时间重叠最多的主要字幕作为第二行，未与任何主要字幕重叠的次要字幕单独保留
+func Merge(primary []Cue, secondary []Cue) []Cue {
+	var (
+		merged = make([]Cue, len(primary), len(primary)+len(secondary))
+		lines  = make([][]string, len(primary)) // 每条主要字幕并入的次要字幕
+	)
+	copy(merged, primary)
+	for _, cue := range secondary {
+		best, bestOverlap := -1, time.Duration(0)
+		for i, p := range primary {
+			if overlap := min(p.End, cue.End) - max(p.Start, cue.Start); overlap > bestOverlap {
+				best, bestOverlap = i, overlap
+			}
+		}
+		if best < 0 {
+			merged = append(merged, cue)
+			continue
+		}
+		lines[best] = append(lines[best], strings.ReplaceAll(cue.Text, "\n", " "))
+	}
+	for i := range primary {
+		if len(lines[i]) > 0 {
+			merged[i].Text = strings.ReplaceAll(merged[i].Text, "\n", " ") + "\n" + strings.Join(lines[i], " ")
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].Start < merged[j].Start })
+	return merged
+}
+
+// 生成双语 ASS 字幕
+//
+// primary 为第一行（如中文），secondary 为第二行（如英文）；支持 Parse 能解析的所有格式
+func Bilingual(primary []byte, secondary []byte, assStyle []string) ([]byte, error) {
+	first, err := Parse(primary)
+	if err != nil {
+		return nil, err
+	}
+	second, err := Parse(secondary)
+	if err != nil {
+		return nil, err
+	}
+	return writeASS(Merge(first.Cues, second.Cues), assStyle), nil
+}
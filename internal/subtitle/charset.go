@@ -0,0 +1,161 @@
+package subtitle
+
+import (
+	"bytes"
+	"errors"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// 字幕编码
+const (
+	CharsetUTF8     = "UTF-8"
+	CharsetUTF16LE  = "UTF-16LE"
+	CharsetUTF16BE  = "UTF-16BE"
+	CharsetGB18030  = "GB18030"
+	CharsetBig5     = "Big5"
+	CharsetShiftJIS = "Shift_JIS"
+)
+
+var ErrUnknownCharset = errors.New("无法识别字幕编码")
+
+// 需要按字频判断的多字节编码，得分相同时靠前的优先
+var multiByteCharsets = []struct {
+	name     string
+	encoding encoding.Encoding
+}{
+	{CharsetGB18030, simplifiedchinese.GB18030},
+	{CharsetBig5, traditionalchinese.Big5},
+	{CharsetShiftJIS, japanese.ShiftJIS},
+}
+
+// 简体、繁体中文常用字
+//
+// 以错误编码解码时得到的多为生僻字，据此判断编码
+var commonHan = func() map[rune]bool {
+	const chars = "的一是不了在人有我他这个们中来上大为和国地到以说时要就出会可也你对生能而子那得于着下自之年过发后作里用道行所然家种事成方多经么去法学如都同现当没动面起看定天分还进好小部其些主样理心她本前开但因只从想实日者意无力它与长把机十第公此已工使情明性知全三又关点正业外将两高间由问很最重并物手应向头文体相见被利什二等产或新己制身果加月话合回特代内信表化老给世位次度门任常先海通教儿原东声提立比员解水名真论处走义各入几口认条平气题活更别打女变四总何电数安少报才结反受目太量再感建务做接必场件计管期直命山金指许区保至形便空决展马科五基眼书非则听白却界达光放强即像难且思完设式色路记品住告类求据程北边死张该交规万取拉格望觉术领共确传师观清今切让识候带导争运笑飞风步改收根干造言联持组每车亲极林服快办议往元士证近失转夫令准布始怎呢吗吧啊呀哦嗯哪谁您嘛啦哈喂别哥姐妈爸朋友谢对请帮等钱吃走快跑" +
+		"這個們來為國說時會對於過發後裡麼學現當沒動點關樣體開從實無與長機愛讓聽話還進東邊買賣嗎幾處義員認條氣題別變總電數報結費隻見頭間問應兩將產給門種經許區保書則聽難設記類據邊張該規萬覺術領確傳師觀識帶導爭運飛風聯組車親極辦議證轉準謝請幫錢喫媽爺們誰嘛喔呢"
+	m := make(map[rune]bool)
+	for _, r := range chars {
+		m[r] = true
+	}
+	return m
+}()
+
+// 识别字幕编码
+//
+// 依次根据 BOM、UTF-16 的零字节分布、UTF-8 合法性和 GB18030、Big5、Shift_JIS 解码后的字频判断，无法识别时返回空字符串
+func DetectCharset(content []byte) string {
+	switch {
+	case bytes.HasPrefix(content, []byte("\xEF\xBB\xBF")):
+		return CharsetUTF8
+	case bytes.HasPrefix(content, []byte("\xFF\xFE")):
+		return CharsetUTF16LE
+	case bytes.HasPrefix(content, []byte("\xFE\xFF")):
+		return CharsetUTF16BE
+	}
+	if charset := detectUTF16(content); charset != "" {
+		return charset
+	}
+	if utf8.Valid(content) {
+		return CharsetUTF8
+	}
+
+	var (
+		best      string
+		bestScore int
+	)
+	for _, candidate := range multiByteCharsets {
+		decoded, err := candidate.encoding.NewDecoder().Bytes(content)
+		if err != nil {
+			continue
+		}
+		if score := charsetScore(decoded); score > bestScore {
+			best, bestScore = candidate.name, score
+		}
+	}
+	return best
+}
+
+// 根据零字节分布识别无 BOM 的 UTF-16（字幕以 ASCII 字符为主）
+func detectUTF16(content []byte) string {
+	sample := content
+	if len(sample) > 4096 {
+		sample = sample[:4096]
+	}
+	if len(sample) < 4 {
+		return ""
+	}
+	var even, odd int
+	for i, b := range sample {
+		if b != 0 {
+			continue
+		}
+		if i%2 == 0 {
+			even++
+		} else {
+			odd++
+		}
+	}
+	half := len(sample) / 2
+	switch {
+	case even > half*3/10 && odd < half/20:
+		return CharsetUTF16BE
+	case odd > half*3/10 && even < half/20:
+		return CharsetUTF16LE
+	}
+	return ""
+}
+
+// 计算解码结果的得分：常用汉字和假名加分，非法字符和半角片假名减分
+func charsetScore(decoded []byte) int {
+	var score int
+	for _, r := range string(decoded) {
+		switch {
+		case r < utf8.RuneSelf:
+		case r == utf8.RuneError:
+			score -= 4
+		case r >= 0x3040 && r <= 0x30FF: // 平假名、片假名
+			score++
+		case r >= 0xFF61 && r <= 0xFF9F: // 半角片假名
+			score--
+		case commonHan[r]:
+			score++
+		}
+	}
+	return score
+}
+
+// 将字幕转为 UTF-8
+//
+// 返回转换后的字幕（去除 BOM）和识别出的原编码
+func ToUTF8(content []byte) ([]byte, string, error) {
+	charset := DetectCharset(content)
+	var decoder *encoding.Decoder
+	switch charset {
+	case CharsetUTF8:
+		return bytes.TrimPrefix(content, []byte("\xEF\xBB\xBF")), charset, nil
+	case CharsetUTF16LE:
+		decoder = unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewDecoder()
+	case CharsetUTF16BE:
+		decoder = unicode.UTF16(unicode.BigEndian, unicode.UseBOM).NewDecoder()
+	case CharsetGB18030:
+		decoder = simplifiedchinese.GB18030.NewDecoder()
+	case CharsetBig5:
+		decoder = traditionalchinese.Big5.NewDecoder()
+	case CharsetShiftJIS:
+		decoder = japanese.ShiftJIS.NewDecoder()
+	default:
+		return content, "", ErrUnknownCharset
+	}
+	decoded, err := decoder.Bytes(content)
+	if err != nil {
+		return content, charset, err
+	}
+	return decoded, charset, nil
+}
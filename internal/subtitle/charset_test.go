@@ -0,0 +1,52 @@
+package subtitle_test
+
+import (
+	"MediaWarp/internal/subtitle"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func TestToUTF8(t *testing.T) {
+	var (
+		simplified  = "1\n00:00:01,000 --> 00:00:03,000\n这是一个简体中文字幕，我们来看看它的编码。\n\n2\n00:00:04,000 --> 00:00:06,000\n你说什么？没有听到。\n"
+		traditional = "1\n00:00:01,000 --> 00:00:03,000\n這是一個繁體中文字幕，我們來看看它的編碼。\n\n2\n00:00:04,000 --> 00:00:06,000\n你說什麼？沒有聽到。\n"
+		japaneseSub = "1\n00:00:01,000 --> 00:00:03,000\nこれは日本語の字幕です。\n\n2\n00:00:04,000 --> 00:00:06,000\nありがとうございました！\n"
+	)
+	for caseName, testCase := range map[string]struct {
+		Text     string
+		Encoding encoding.Encoding
+		Charset  string
+	}{
+		"UTF-8":        {simplified, encoding.Nop, subtitle.CharsetUTF8},
+		"UTF-8 BOM":    {"\xEF\xBB\xBF" + simplified, encoding.Nop, subtitle.CharsetUTF8},
+		"UTF-16LE BOM": {simplified, unicode.UTF16(unicode.LittleEndian, unicode.UseBOM), subtitle.CharsetUTF16LE},
+		"UTF-16BE":     {traditional, unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM), subtitle.CharsetUTF16BE},
+		"GBK":          {simplified, simplifiedchinese.GBK, subtitle.CharsetGB18030},
+		"GBK 繁体":       {traditional, simplifiedchinese.GBK, subtitle.CharsetGB18030},
+		"Big5":         {traditional, traditionalchinese.Big5, subtitle.CharsetBig5},
+		"Shift_JIS":    {japaneseSub, japanese.ShiftJIS, subtitle.CharsetShiftJIS},
+	} {
+		t.Run(caseName, func(t *testing.T) {
+			encoded, err := testCase.Encoding.NewEncoder().Bytes([]byte(testCase.Text))
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, charset, err := subtitle.ToUTF8(encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if charset != testCase.Charset {
+				t.Errorf("编码识别错误。期望: %s, 实际: %s", testCase.Charset, charset)
+			}
+			if want := strings.TrimPrefix(testCase.Text, "\xEF\xBB\xBF"); string(result) != want {
+				t.Errorf("转换结果错误。期望: %q, 实际: %q", want, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package subtitle
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	cueTimingPattern = regexp.MustCompile(`((?:\d+:)?\d{2}:\d{2}[,.]\d{1,3})(\s*-->\s*)((?:\d+:)?\d{2}:\d{2}[,.]\d{1,3})`)
+	cueTimePattern   = regexp.MustCompile(`^(?:(\d+):)?(\d{2}):(\d{2})([,.])(\d{1,3})$`)
+)
+
+// 调整字幕时间轴
+//
+// 支持 SRT、WebVTT 和 ASS、SSA 字幕，correct 根据原时间返回调整后的时间（小于 0 时视为 0）；其余格式返回原字幕
+func Retime(content []byte, correct func(time.Duration) time.Duration) []byte {
+	switch Detect(content) {
+	case FormatSRT, FormatVTT:
+		return []byte(cueTimingPattern.ReplaceAllStringFunc(string(content), func(timing string) string {
+			matches := cueTimingPattern.FindStringSubmatch(timing)
+			return retimeClock(matches[1], correct) + matches[2] + retimeClock(matches[3], correct)
+		}))
+	case FormatASS, FormatSSA:
+		return []byte(retimeASS(string(content), correct))
+	}
+	return content
+}
+
+// 调整 SRT、WebVTT 时间，保留原分隔符
+func retimeClock(value string, correct func(time.Duration) time.Duration) string {
+	matches := cueTimePattern.FindStringSubmatch(value)
+	if matches == nil {
+		return value
+	}
+	d := correct(clockTime(matches[1], matches[2], matches[3], matches[5]))
+	result := formatClock(d, matches[4])
+	if matches[1] == "" && d < time.Hour { // WebVTT 可省略小时
+		result = result[3:]
+	}
+	return result
+}
+
+// 调整 ASS 字幕 Dialogue 行的开始和结束时间
+func retimeASS(content string, correct func(time.Duration) time.Duration) string {
+	var (
+		lines   = strings.Split(content, "\n")
+		inEvent bool
+		format  = defaultEventFormat
+	)
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			inEvent = strings.EqualFold(trimmed, "[Events]")
+			continue
+		}
+		if !inEvent {
+			continue
+		}
+		key, value, found := strings.Cut(trimmed, ":")
+		if !found {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "Format":
+			format = strings.Split(value, ",")
+			for i := range format {
+				format[i] = strings.TrimSpace(format[i])
+			}
+		case "Dialogue":
+			fields := strings.SplitN(strings.TrimSpace(value), ",", len(format))
+			if len(fields) != len(format) {
+				continue
+			}
+			for j, name := range format {
+				if !strings.EqualFold(name, "Start") && !strings.EqualFold(name, "End") {
+					continue
+				}
+				if d, ok := assTime(fields[j]); ok {
+					fields[j] = formatASSTime(correct(d))
+				}
+			}
+			lines[i] = "Dialogue: " + strings.Join(fields, ",")
+			if strings.HasSuffix(line, "\r") {
+				lines[i] += "\r"
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,78 @@
+package subtitle
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	srtTimingPattern = regexp.MustCompile(`(-?\d+):(\d{2}):(\d{2})[,.](\d{1,3})\s*-->\s*(-?\d+):(\d{2}):(\d{2})[,.](\d{1,3})`)
+	blankLinePattern = regexp.MustCompile(`\n\s*\n`)
+)
+
+// 统一换行符
+func normalizeNewLine(content string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(content, "\r\n", "\n"), "\r", "\n")
+}
+
+// 根据时、分、秒和毫秒字符串计算时间
+//
+// 毫秒不足三位时按小数处理（如 5 -> 500ms），负数时间视为 0
+func clockTime(hours string, minutes string, seconds string, fraction string) time.Duration {
+	h, _ := strconv.Atoi(hours)
+	m, _ := strconv.Atoi(minutes)
+	s, _ := strconv.Atoi(seconds)
+	if h < 0 {
+		h = 0
+	}
+	var ms int
+	if fraction != "" {
+		ms, _ = strconv.Atoi((fraction + "00")[:3])
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second + time.Duration(ms)*time.Millisecond
+}
+
+// 格式化时间为 HH:MM:SS{sep}mmm
+func formatClock(d time.Duration, sep string) string {
+	if d < 0 {
+		d = 0
+	}
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
+
+// 解析 SRT 字幕
+func parseSRT(content string) ([]Cue, error) {
+	var cues []Cue
+	for _, block := range blankLinePattern.Split(normalizeNewLine(content), -1) {
+		lines := strings.Split(strings.TrimSpace(block), "\n")
+		for i, line := range lines {
+			matches := srtTimingPattern.FindStringSubmatch(line)
+			if matches == nil {
+				continue
+			}
+			cues = append(cues, Cue{
+				Start: clockTime(matches[1], matches[2], matches[3], matches[4]),
+				End:   clockTime(matches[5], matches[6], matches[7], matches[8]),
+				Text:  strings.TrimSpace(strings.Join(lines[i+1:], "\n")),
+			})
+			break
+		}
+	}
+	if len(cues) == 0 {
+		return nil, fmt.Errorf("未找到字幕条目")
+	}
+	return cues, nil
+}
+
+// 写入 SRT 字幕
+func writeSRT(cues []Cue) []byte {
+	var builder strings.Builder
+	for i, cue := range cues {
+		fmt.Fprintf(&builder, "%d\n%s --> %s\n%s\n\n", i+1, formatClock(cue.Start, ","), formatClock(cue.End, ","), cue.Text)
+	}
+	return []byte(builder.String())
+}
@@ -0,0 +1,146 @@
+package subtitle
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 字幕格式
+type Format string
+
+const (
+	FormatUnknown Format = ""
+	FormatSRT     Format = "srt"
+	FormatVTT     Format = "vtt"
+	FormatASS     Format = "ass"
+	FormatSSA     Format = "ssa"
+	FormatTTML    Format = "ttml"
+)
+
+var ErrUnsupportedFormat = errors.New("不支持的字幕格式")
+
+// 字幕条目
+//
+// Text 使用类 HTML 标签表示样式：<i>、<b>、<u>、<font color="#RRGGBB">，多行以 \n 分隔
+type Cue struct {
+	Start time.Duration
+	End   time.Duration
+	Text  string
+}
+
+// 字幕
+type Subtitle struct {
+	Format Format
+	Cues   []Cue
+}
+
+// 根据扩展名获取字幕格式
+//
+// 支持 srt、vtt/webvtt、ass、ssa、ttml/dfxp/xml
+func FormatFromExt(ext string) Format {
+	switch strings.ToLower(strings.TrimPrefix(ext, ".")) {
+	case "srt":
+		return FormatSRT
+	case "vtt", "webvtt":
+		return FormatVTT
+	case "ass":
+		return FormatASS
+	case "ssa":
+		return FormatSSA
+	case "ttml", "dfxp", "xml":
+		return FormatTTML
+	}
+	return FormatUnknown
+}
+
+// 识别字幕格式
+func Detect(content []byte) Format {
+	content = bytes.TrimPrefix(content, []byte("\xEF\xBB\xBF")) // 去除 UTF-8 BOM
+	head := bytes.TrimSpace(content)
+	if len(head) > 2048 {
+		head = head[:2048]
+	}
+	switch {
+	case bytes.HasPrefix(head, []byte("WEBVTT")):
+		return FormatVTT
+	case bytes.Contains(head, []byte("[V4 Styles]")):
+		return FormatSSA
+	case bytes.Contains(head, []byte("[Script Info]")) || bytes.Contains(head, []byte("[V4+ Styles]")):
+		return FormatASS
+	case bytes.HasPrefix(head, []byte("<")) && bytes.Contains(head, []byte("<tt")):
+		return FormatTTML
+	case srtTimingPattern.Match(head):
+		return FormatSRT
+	}
+	return FormatUnknown
+}
+
+// 解析字幕
+func Parse(content []byte) (*Subtitle, error) {
+	content = bytes.TrimPrefix(content, []byte("\xEF\xBB\xBF"))
+	format := Detect(content)
+	var (
+		cues []Cue
+		err  error
+	)
+	switch format {
+	case FormatSRT:
+		cues, err = parseSRT(string(content))
+	case FormatVTT:
+		cues, err = parseVTT(string(content))
+	case FormatASS, FormatSSA:
+		cues, err = parseASS(string(content))
+	case FormatTTML:
+		cues, err = parseTTML(content)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, fmt.Errorf("解析 %s 字幕失败：%w", format, err)
+	}
+	return &Subtitle{Format: format, Cues: cues}, nil
+}
+
+// 将字幕写入为指定格式
+//
+// 写入 ASS、SSA 时均使用 ASS（v4+）格式，assStyle 为样式（为空时使用默认样式）
+func (s *Subtitle) Encode(format Format, assStyle []string) ([]byte, error) {
+	switch format {
+	case FormatSRT:
+		return writeSRT(s.Cues), nil
+	case FormatVTT:
+		return writeVTT(s.Cues), nil
+	case FormatASS, FormatSSA:
+		return writeASS(s.Cues, assStyle), nil
+	}
+	return nil, fmt.Errorf("%w：%s", ErrUnsupportedFormat, format)
+}
+
+// 转换字幕格式
+//
+// 字幕已是目标格式时原样返回；请求 SSA 时 ASS 字幕原样返回（播放器均支持 ASS）
+func Convert(content []byte, target Format, assStyle []string) ([]byte, error) {
+	source := Detect(content)
+	if source == target || source == FormatASS && target == FormatSSA {
+		return content, nil
+	}
+	subtitle, err := Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	return subtitle.Encode(target, assStyle)
+}
+
+// 转换字幕中的文本
+//
+// ASS、SSA 字幕只转换 Dialogue 行的文本（不包括覆盖标签），以免修改样式名和字体名；其余格式转换全部内容
+func MapText(content []byte, mapping func(string) string) []byte {
+	switch Detect(content) {
+	case FormatASS, FormatSSA:
+		return []byte(mapASSText(string(content), mapping))
+	}
+	return []byte(mapping(string(content)))
+}
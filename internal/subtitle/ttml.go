@@ -0,0 +1,241 @@
+package subtitle
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ttmlClockPattern  = regexp.MustCompile(`^(\d+):(\d{2}):(\d{2})(?:\.(\d+)|:(\d+)(?:\.\d+)?)?$`)
+	ttmlOffsetPattern = regexp.MustCompile(`^(\d+(?:\.\d+)?)(h|ms|m|s|f|t)$`)
+	whitespacePattern = regexp.MustCompile(`\s+`)
+)
+
+// TTML 样式
+type ttmlStyle struct {
+	italic    bool
+	bold      bool
+	underline bool
+	color     string // #RRGGBB
+}
+
+// 根据属性更新样式
+func (s *ttmlStyle) apply(attrs []xml.Attr, styles map[string]ttmlStyle) {
+	for _, attr := range attrs { // 先应用引用的样式，再应用内联样式
+		if attr.Name.Local == "style" {
+			for _, id := range strings.Fields(attr.Value) {
+				if style, ok := styles[id]; ok {
+					s.italic = s.italic || style.italic
+					s.bold = s.bold || style.bold
+					s.underline = s.underline || style.underline
+					if style.color != "" {
+						s.color = style.color
+					}
+				}
+			}
+		}
+	}
+	for _, attr := range attrs {
+		switch attr.Name.Local {
+		case "fontStyle":
+			s.italic = attr.Value == "italic" || attr.Value == "oblique"
+		case "fontWeight":
+			s.bold = attr.Value == "bold"
+		case "textDecoration":
+			s.underline = strings.Contains(attr.Value, "underline")
+		case "color":
+			if strings.HasPrefix(attr.Value, "#") && len(attr.Value) >= 7 {
+				s.color = attr.Value[:7]
+			}
+		}
+	}
+}
+
+// 样式对应的开始和结束标签
+func (s ttmlStyle) tags() (string, string) {
+	var open, end string
+	if s.color != "" {
+		open += `<font color="` + s.color + `">`
+		end = "</font>"
+	}
+	for _, tag := range []struct {
+		enabled bool
+		name    string
+	}{{s.italic, "i"}, {s.bold, "b"}, {s.underline, "u"}} {
+		if tag.enabled {
+			open += "<" + tag.name + ">"
+			end = "</" + tag.name + ">" + end
+		}
+	}
+	return open, end
+}
+
+// TTML 时间参数
+type ttmlTiming struct {
+	frameRate float64
+	tickRate  float64
+}
+
+// 解析 TTML 时间表达式
+//
+// 支持时钟时间（HH:MM:SS.fff、HH:MM:SS:frames）和偏移时间（如 1.5s、500ms、25f、10000t）
+func (t ttmlTiming) parse(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if matches := ttmlClockPattern.FindStringSubmatch(value); matches != nil {
+		d := clockTime(matches[1], matches[2], matches[3], "")
+		if matches[4] != "" {
+			fraction, _ := strconv.ParseFloat("0."+matches[4], 64)
+			d += time.Duration(fraction * float64(time.Second))
+		} else if matches[5] != "" {
+			frames, _ := strconv.ParseFloat(matches[5], 64)
+			d += time.Duration(frames / t.frameRate * float64(time.Second))
+		}
+		return d, nil
+	}
+	if matches := ttmlOffsetPattern.FindStringSubmatch(value); matches != nil {
+		number, _ := strconv.ParseFloat(matches[1], 64)
+		var seconds float64
+		switch matches[2] {
+		case "h":
+			seconds = number * 3600
+		case "m":
+			seconds = number * 60
+		case "s":
+			seconds = number
+		case "ms":
+			seconds = number / 1000
+		case "f":
+			seconds = number / t.frameRate
+		case "t":
+			seconds = number / t.tickRate
+		}
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+	return 0, fmt.Errorf("无效的时间表达式：%s", value)
+}
+
+// 解析 TTML（包括 DFXP）字幕
+//
+// 时间相对于父元素的 begin；支持 br 换行、span 和 styling 中定义的斜体、粗体、下划线和颜色
+func parseTTML(content []byte) ([]Cue, error) {
+	var (
+		decoder = xml.NewDecoder(bytes.NewReader(content))
+		timing  = ttmlTiming{frameRate: 30, tickRate: 1}
+		styles  = make(map[string]ttmlStyle)
+		offsets = []time.Duration{0} // 每层元素的开始时间
+		cues    []Cue
+		current *Cue            // 正在解析的 p 元素
+		text    strings.Builder // p 元素的文本
+		closes  []string        // p 中每层元素的结束标签
+	)
+	decoder.Strict = false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			attr := func(name string) string {
+				for _, a := range element.Attr {
+					if a.Name.Local == name {
+						return a.Value
+					}
+				}
+				return ""
+			}
+			switch element.Name.Local {
+			case "tt":
+				if rate, err := strconv.ParseFloat(attr("frameRate"), 64); err == nil && rate > 0 {
+					timing.frameRate = rate
+				}
+				if rate, err := strconv.ParseFloat(attr("tickRate"), 64); err == nil && rate > 0 {
+					timing.tickRate = rate
+				}
+			case "style":
+				if id := attr("id"); id != "" && current == nil {
+					var style ttmlStyle
+					style.apply(element.Attr, styles)
+					styles[id] = style
+				}
+			case "br":
+				if current != nil {
+					text.WriteString("\n")
+				}
+			}
+
+			begin := offsets[len(offsets)-1]
+			if value := attr("begin"); value != "" {
+				d, err := timing.parse(value)
+				if err != nil {
+					return nil, err
+				}
+				begin += d
+			}
+			offsets = append(offsets, begin)
+
+			if element.Name.Local == "p" && current == nil {
+				current = &Cue{Start: begin}
+				text.Reset()
+				if value := attr("end"); value != "" {
+					d, err := timing.parse(value)
+					if err != nil {
+						return nil, err
+					}
+					current.End = offsets[len(offsets)-2] + d
+				} else if value := attr("dur"); value != "" {
+					d, err := timing.parse(value)
+					if err != nil {
+						return nil, err
+					}
+					current.End = begin + d
+				}
+			}
+			if current != nil && element.Name.Local != "br" {
+				var style ttmlStyle
+				style.apply(element.Attr, styles)
+				open, end := style.tags()
+				text.WriteString(open)
+				closes = append(closes, end)
+			}
+
+		case xml.EndElement:
+			offsets = offsets[:len(offsets)-1]
+			if current == nil || element.Name.Local == "br" {
+				continue
+			}
+			text.WriteString(closes[len(closes)-1])
+			closes = closes[:len(closes)-1]
+			if element.Name.Local == "p" {
+				lines := strings.Split(text.String(), "\n")
+				for i, line := range lines {
+					lines[i] = strings.TrimSpace(line)
+				}
+				current.Text = strings.Join(lines, "\n")
+				if current.Text != "" && current.End > current.Start {
+					cues = append(cues, *current)
+				}
+				current = nil
+			}
+
+		case xml.CharData:
+			if current != nil {
+				text.WriteString(whitespacePattern.ReplaceAllString(string(element), " "))
+			}
+		}
+	}
+	if len(cues) == 0 {
+		return nil, fmt.Errorf("未找到字幕条目")
+	}
+	return cues, nil
+}
@@ -0,0 +1,72 @@
+package subtitle
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	vttTimingPattern = regexp.MustCompile(`(?:(\d+):)?(\d{2}):(\d{2})\.(\d{1,3})\s*-->\s*(?:(\d+):)?(\d{2}):(\d{2})\.(\d{1,3})`)
+	vttTagPattern    = regexp.MustCompile(`</?([a-zA-Z]+)(?:\.[^\s>]*)?(?:\s[^>]*)?>|<\d[\d:.]*>`) // 标签（包括 class 和注释）及时间戳标签
+	fontTagPattern   = regexp.MustCompile(`</?font[^>]*>`)
+	assTagPattern    = regexp.MustCompile(`\{\\[^}]*\}`) // ASS 覆盖标签（SRT 字幕中常见的 {\an8} 等）
+)
+
+// 解析 WebVTT 字幕
+//
+// 跳过 NOTE、STYLE、REGION 块；只保留 i、b、u 标签，其余标签（如 c、v、ruby、时间戳）移除
+func parseVTT(content string) ([]Cue, error) {
+	var cues []Cue
+	for _, block := range blankLinePattern.Split(normalizeNewLine(content), -1) {
+		lines := strings.Split(strings.TrimSpace(block), "\n")
+		for i, line := range lines {
+			matches := vttTimingPattern.FindStringSubmatch(line)
+			if matches == nil {
+				continue
+			}
+			cues = append(cues, Cue{
+				Start: clockTime(matches[1], matches[2], matches[3], matches[4]),
+				End:   clockTime(matches[5], matches[6], matches[7], matches[8]),
+				Text:  vttText(strings.Join(lines[i+1:], "\n")),
+			})
+			break
+		}
+	}
+	if len(cues) == 0 {
+		return nil, fmt.Errorf("未找到字幕条目")
+	}
+	return cues, nil
+}
+
+// 转换 WebVTT 字幕文本
+func vttText(text string) string {
+	text = vttTagPattern.ReplaceAllStringFunc(text, func(tag string) string {
+		name := strings.ToLower(vttTagPattern.FindStringSubmatch(tag)[1])
+		if name != "i" && name != "b" && name != "u" {
+			return ""
+		}
+		if strings.HasPrefix(tag, "</") {
+			return "</" + name + ">"
+		}
+		return "<" + name + ">"
+	})
+	return strings.TrimSpace(html.UnescapeString(text))
+}
+
+// 写入 WebVTT 字幕
+//
+// WebVTT 不支持 font 标签和 ASS 覆盖标签，写入时移除
+func writeVTT(cues []Cue) []byte {
+	var builder strings.Builder
+	builder.WriteString("WEBVTT\n\n")
+	for _, cue := range cues {
+		text := fontTagPattern.ReplaceAllString(cue.Text, "")
+		text = assTagPattern.ReplaceAllString(text, "")
+		text = strings.ReplaceAll(text, "&", "&amp;")
+		text = strings.ReplaceAll(text, "-->", "--&gt;")
+		fmt.Fprintf(&builder, "%s --> %s\n%s\n\n", formatClock(cue.Start, "."), formatClock(cue.End, "."), text)
+	}
+	return []byte(builder.String())
+}
@@ -0,0 +1,109 @@
+package useragent
+
+import (
+	"MediaWarp/internal/config"
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// 规范化规则
+type rule struct {
+	class     string
+	pattern   *regexp.Regexp
+	userAgent string
+}
+
+// 规范化结果
+type Client struct {
+	Raw       string `json:"raw"`             // 原始 User-Agent
+	Class     string `json:"class,omitempty"` // 客户端类别，未匹配任何规则时为空
+	Pattern   string `json:"pattern,omitempty"`
+	UserAgent string `json:"user_agent"` // 获取下载链接时使用的 User-Agent，规则未指定时保持原始 User-Agent
+	CacheKey  string `json:"cache_key"`  // 下载链接缓存键中的 User-Agent 部分，与 UserAgent 一一对应：规则指定 User-Agent 时为客户端类别，否则为原始 User-Agent
+}
+
+// 内置规则
+var defaultRules = []config.UserAgentRule{
+	{Class: "Infuse", Pattern: `(?i)infuse`},
+	{Class: "VidHub", Pattern: `(?i)vidhub`},
+	{Class: "Fileball", Pattern: `(?i)fileball|filebar`},
+	{Class: "SenPlayer", Pattern: `(?i)senplayer`},
+	{Class: "Emby Theater", Pattern: `(?i)emby ?theater`},
+	{Class: "Emby", Pattern: `(?i)^emby`},
+	{Class: "Jellyfin", Pattern: `(?i)jellyfin`},
+	{Class: "Emby Web", Pattern: `(?i)^mozilla/`},
+}
+
+var (
+	rules   []rule
+	enabled bool
+	mutex   sync.RWMutex
+)
+
+// 初始化规范化规则
+func Init() error {
+	setting := config.UserAgent()
+	settingRules := setting.Rules
+	if len(settingRules) == 0 {
+		settingRules = defaultRules
+	}
+
+	compiled := make([]rule, 0, len(settingRules))
+	for _, settingRule := range settingRules {
+		if settingRule.Class == "" {
+			return fmt.Errorf("User-Agent 规则 %s 缺少 Class", settingRule.Pattern)
+		}
+		pattern, err := regexp.Compile(settingRule.Pattern)
+		if err != nil {
+			return fmt.Errorf("User-Agent 规则 %s 的正则表达式 %s 无效：%w", settingRule.Class, settingRule.Pattern, err)
+		}
+		compiled = append(compiled, rule{class: settingRule.Class, pattern: pattern, userAgent: settingRule.UserAgent})
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	rules = compiled
+	enabled = setting.Enable
+	return nil
+}
+
+// 规范化 User-Agent
+//
+// 返回第一条匹配规则的客户端类别；
+// 规则指定 User-Agent 时同一类别使用该 User-Agent 获取下载链接并共用缓存键，
+// 否则仍使用原始 User-Agent 获取下载链接和作为缓存键（部分网盘的下载链接与 User-Agent 绑定）
+func Classify(userAgent string) Client {
+	client := Client{Raw: userAgent, UserAgent: userAgent, CacheKey: userAgent}
+	if userAgent == "" {
+		return client
+	}
+
+	mutex.RLock()
+	defer mutex.RUnlock()
+	if !enabled {
+		return client
+	}
+	for _, r := range rules {
+		if r.pattern.MatchString(userAgent) {
+			client.Class = r.class
+			client.Pattern = r.pattern.String()
+			if r.userAgent != "" {
+				client.UserAgent = r.userAgent
+				client.CacheKey = r.class
+			}
+			break
+		}
+	}
+	return client
+}
+
+// 获取下载链接时使用的 User-Agent
+func Normalize(userAgent string) string {
+	return Classify(userAgent).UserAgent
+}
+
+// 获取下载链接缓存键中的 User-Agent 部分
+func CacheKey(userAgent string) string {
+	return Classify(userAgent).CacheKey
+}
@@ -0,0 +1,57 @@
+package useragent_test
+
+import (
+	"MediaWarp/internal/config"
+	"MediaWarp/internal/useragent"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	restore := config.Update(func(s *config.Snapshot) { s.UserAgent = config.UserAgentSetting{Enable: false} })
+	defer func() {
+		restore()
+		useragent.Init()
+	}()
+
+	if err := useragent.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if got := useragent.Normalize("Infuse-Direct/7.7.5"); got != "Infuse-Direct/7.7.5" {
+		t.Errorf("未启用时不应规范化：%s", got)
+	}
+
+	config.Update(func(s *config.Snapshot) {
+		s.UserAgent = config.UserAgentSetting{
+			Enable: true,
+			Rules: []config.UserAgentRule{
+				{Class: "Infuse", Pattern: `(?i)infuse`, UserAgent: "Infuse-Direct/8.0"},
+				{Class: "VidHub", Pattern: `(?i)vidhub`},
+			},
+		}
+	})
+	if err := useragent.Init(); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		userAgent string
+		class     string
+		expected  string
+		cacheKey  string
+	}{
+		{"Infuse-Direct/7.7.5", "Infuse", "Infuse-Direct/8.0", "Infuse"},
+		{"Infuse-Library/8.1 (iPhone)", "Infuse", "Infuse-Direct/8.0", "Infuse"},
+		{"VidHub/1.7.2", "VidHub", "VidHub/1.7.2", "VidHub/1.7.2"},
+		{"curl/8.0", "", "curl/8.0", "curl/8.0"},
+	}
+	for _, tt := range tests {
+		client := useragent.Classify(tt.userAgent)
+		if client.Class != tt.class || client.UserAgent != tt.expected || client.CacheKey != tt.cacheKey {
+			t.Errorf("%s 规范化错误：%+v", tt.userAgent, client)
+		}
+	}
+
+	config.Update(func(s *config.Snapshot) { s.UserAgent.Rules = []config.UserAgentRule{{Class: "Broken", Pattern: `(`}} })
+	if err := useragent.Init(); err == nil {
+		t.Error("无效的正则表达式应返回错误")
+	}
+}
@@ -6,10 +6,13 @@ import (
 	"MediaWarp/internal/config"
 	"MediaWarp/internal/handler"
 	"MediaWarp/internal/health"
+	"MediaWarp/internal/limiter"
 	"MediaWarp/internal/logging"
 	"MediaWarp/internal/metrics"
 	"MediaWarp/internal/process"
+	"MediaWarp/internal/resolver"
 	"MediaWarp/internal/router"
+	"MediaWarp/internal/useragent"
 	"MediaWarp/utils"
 	"encoding/json"
 	"flag"
@@ -26,12 +29,14 @@ import (
 var (
 	isDebug     bool   // 开启调试模式
 	showVersion bool   // 显示版本信息
+	checkConfig bool   // 验证配置文件后退出
 	configPath  string // 配置文件路径
 )
 
 func init() {
 	flag.BoolVar(&showVersion, "version", false, "显示版本信息")
 	flag.BoolVar(&isDebug, "debug", false, "是否启用调试模式")
+	flag.BoolVar(&checkConfig, "check-config", false, "验证配置文件后退出")
 	flag.StringVar(&configPath, "config", "", "指定配置文件路径")
 	flag.Parse()
 
@@ -60,6 +65,7 @@ func initializeEnhancedSystem() error {
 
 	// 3. 添加健康检查
 	health.GlobalHealthChecker.AddCheck(&health.RcloneHealthCheck{})
+	health.GlobalHealthChecker.AddCheck(&limiter.RemoteHealthCheck{})
 
 	// 4. 启动指标收集
 	go startMetricsCollection()
@@ -85,6 +91,14 @@ func main() {
 		fmt.Println(string(versionInfo))
 		return
 	}
+	if checkConfig {
+		if err := config.ValidateConfig(configPath); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Println("配置文件验证通过")
+		return
+	}
 
 	signChan := make(chan os.Signal, 1)
 	errChan := make(chan error, 1)
@@ -97,31 +111,48 @@ func main() {
 		fmt.Println("配置初始化失败：", err)
 		return
 	}
-	logging.Init()                                                                           // 初始化日志
-	logging.Infof("上游媒体服务器类型：%s，服务器地址：%s", config.MediaServer.Type, config.MediaServer.ADDR) // 日志打印
-	// Alist service has been removed
+	logging.Init()                                 // 初始化日志
+	for _, server := range config.MediaServers() { // 日志打印
+		logging.Infof("上游媒体服务器：%s，类型：%s，服务器地址：%s", server.Name, server.Type, server.ADDR)
+	}
+	for _, warning := range config.Warnings() {
+		logging.Warning("配置验证警告：", warning)
+	}
+	limiter.Init()                           // 初始化远程存储限流器
+	if err := useragent.Init(); err != nil { // 初始化 User-Agent 规范化规则
+		logging.Error("User-Agent 规范化规则初始化失败：", err)
+		return
+	}
+	if err := resolver.Init(); err != nil { // 初始化链接解析器
+		logging.Error("链接解析器初始化失败：", err)
+		return
+	}
 	if err := handler.Init(); err != nil { // 初始化媒体服务器处理器
 		logging.Error("媒体服务器处理器初始化失败：", err)
 		return
 	}
+	config.OnReload(reloadComponents) // 热重载配置后重建组件
+
+	if config.Cache().Persist { // 初始化持久化缓存
+		if err := handler.InitCacheStore(config.CachePath()); err != nil {
+			logging.Warning("持久化缓存初始化失败，仅使用内存缓存：", err)
+		}
+	}
 
 	// 初始化增强系统
 	if err := initializeEnhancedSystem(); err != nil {
 		logging.Error("增强系统初始化失败：", err)
 		return
 	}
-	if !isDebug {
-		isDebug = config.Debug
-	}
-	if isDebug {
+	if isDebug || config.Debug() {
 		logging.SetLevel(logrus.DebugLevel)
 		logging.Warning("已启用调试模式")
 	} else {
 		gin.SetMode(gin.ReleaseMode)
 	}
 	// make_config()
-	logging.Info("Environ ", os.Environ())
-	logging.Info("MediaWarp 监听端口：", config.Port)
+	logging.Info("Environ ", utils.MaskEnviron(os.Environ()))
+	logging.Info("MediaWarp 监听端口：", config.Port())
 	ginR := router.InitRouter() // 路由初始化
 
 	// 添加健康检查和监控端点
@@ -148,11 +179,13 @@ func main() {
 
 	// 注册缓存统计API路由
 	handler.RegisterCacheStatsRoutes(ginR)
+	handler.RegisterUserAgentRoutes(ginR)
 	logging.Info("缓存监控API已启用")
 
 	// Web监控系统已移除
 
 	// 缓存预热功能已移除
+	config.Watch(func() { router.ReloadConfig() }) // 配置文件修改后自动热重载
 	logging.Info("MediaWarp 启动成功")
 	go func() {
 		if err := ginR.Run(config.ListenAddr()); err != nil {
@@ -171,6 +204,30 @@ func main() {
 
 }
 
+// 热重载配置后按新配置重建组件
+//
+// 内存缓存由各组件保留，不会被清空
+func reloadComponents() error {
+	limiter.Reload()
+	if err := useragent.Init(); err != nil {
+		return fmt.Errorf("User-Agent 规范化规则初始化失败：%w", err)
+	}
+	if err := resolver.Init(); err != nil {
+		return fmt.Errorf("链接解析器初始化失败：%w", err)
+	}
+	if err := handler.Init(); err != nil {
+		return fmt.Errorf("媒体服务器处理器初始化失败：%w", err)
+	}
+	if !isDebug { // 未通过命令行参数开启调试模式时按配置切换日志级别
+		if config.Debug() {
+			logging.SetLevel(logrus.DebugLevel)
+		} else {
+			logging.SetLevel(logrus.InfoLevel)
+		}
+	}
+	return nil
+}
+
 // gracefulShutdown 优雅关闭
 func gracefulShutdown() {
 	logging.Info("正在执行优雅关闭...")
@@ -184,5 +241,11 @@ func gracefulShutdown() {
 		logging.Info("播放信息缓存已关闭")
 	}
 
+	// 停止后台刷新下载链接
+	handler.StopLinkRefresher()
+
+	// 关闭持久化缓存
+	handler.CloseCacheStore()
+
 	logging.Info("优雅关闭完成")
 }
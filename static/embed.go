@@ -6,6 +6,7 @@ import (
 
 //go:embed embyExternalUrl/embyWebAddExternalUrl/embyLaunchPotplayer.js
 //go:embed dd-danmaku/ede.js
+//go:embed jellyfin-danmaku/ede.js
 //go:embed emby-web-mod/actorPlus/actorPlus.js
 //go:embed emby-web-mod/emby-swiper/emby-swiper.js
 //go:embed emby-web-mod/emby-tab/emby-tab.js
@@ -18,5 +19,6 @@ import (
 //go:embed emby-crx/static/js/jquery-3.6.0.min.js
 //go:embed emby-crx/static/js/md5.min.js
 //go:embed emby-crx/content/main.js
+//go:embed subtitle-offset/subtitleOffset.js
 //go:embed templates/*.html
 var EmbeddedStaticAssets embed.FS
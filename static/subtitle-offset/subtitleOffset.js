@@ -0,0 +1,122 @@
+// MediaWarp 字幕时间轴调整
+//
+// 记录播放页最近加载的字幕流，按 Alt+S 打开调整面板；保存后重新选择字幕即可生效
+// 使用 Web 客户端当前登录用户的认证令牌访问接口
+(function () {
+    'use strict';
+
+    const subtitlePattern = /\/Videos\/([^/]+)\/([^/]+)\/Subtitles\/(\d+)\//i;
+    const framerates = [
+        { label: '不调整', from: 0, to: 0 },
+        { label: '23.976 → 25', from: 23.976, to: 25 },
+        { label: '25 → 23.976', from: 25, to: 23.976 },
+        { label: '24 → 25', from: 24, to: 25 },
+        { label: '25 → 24', from: 25, to: 24 },
+    ];
+    let current = null; // { itemId, mediaSourceId, index }
+    let panel = null;
+
+    // 记录最近加载的字幕流
+    function track(url) {
+        const matches = subtitlePattern.exec(url);
+        if (matches) {
+            current = { itemId: matches[1], mediaSourceId: matches[2], index: matches[3] };
+        }
+    }
+    performance.getEntriesByType('resource').forEach(entry => track(entry.name));
+    new PerformanceObserver(list => list.getEntries().forEach(entry => track(entry.name)))
+        .observe({ type: 'resource', buffered: true });
+
+    function endpoint() {
+        return `/MediaWarp/subtitles/${encodeURIComponent(current.itemId)}/${encodeURIComponent(current.mediaSourceId)}/${current.index}/offset`;
+    }
+
+    // 当前登录用户的认证请求头
+    function authHeaders() {
+        const apiClient = window.ApiClient;
+        return apiClient && apiClient.accessToken() ? { 'X-Emby-Token': apiClient.accessToken() } : {};
+    }
+
+    function createPanel() {
+        panel = document.createElement('div');
+        panel.style.cssText = 'position:fixed;top:20%;right:2em;z-index:99999;padding:1em;border-radius:.5em;' +
+            'background:rgba(0,0,0,.85);color:#fff;font-size:14px;line-height:2;min-width:16em;';
+        panel.innerHTML = `
+            <div style="font-weight:bold">字幕时间轴调整</div>
+            <div class="mw-subtitle"></div>
+            <label>偏移（秒，正数延后）<input class="mw-offset" type="number" step="0.1" value="0" style="width:6em"></label><br>
+            <label>帧率（字幕 → 视频）<select class="mw-framerate">${framerates.map((f, i) => `<option value="${i}">${f.label}</option>`).join('')}</select></label><br>
+            <button class="mw-save">保存</button> <button class="mw-reset">清除</button> <button class="mw-close">关闭</button>
+            <div class="mw-status" style="color:#8f8"></div>`;
+        panel.querySelector('.mw-save').onclick = save;
+        panel.querySelector('.mw-reset').onclick = reset;
+        panel.querySelector('.mw-close').onclick = () => { panel.style.display = 'none'; };
+        document.body.appendChild(panel);
+    }
+
+    function setStatus(text) {
+        panel.querySelector('.mw-status').textContent = text;
+    }
+
+    async function open() {
+        if (!panel) {
+            createPanel();
+        }
+        panel.style.display = 'block';
+        setStatus('');
+        if (!current) {
+            panel.querySelector('.mw-subtitle').textContent = '尚未加载字幕，请先选择字幕';
+            return;
+        }
+        panel.querySelector('.mw-subtitle').textContent = `媒体项 ${current.itemId}，媒体源 ${current.mediaSourceId}，字幕流 ${current.index}`;
+        try {
+            const resp = await fetch(endpoint(), { headers: authHeaders() });
+            if (!resp.ok) {
+                throw new Error(await resp.text());
+            }
+            const offset = await resp.json();
+            panel.querySelector('.mw-offset').value = offset.offset || 0;
+            const index = framerates.findIndex(f => f.to && Math.abs(f.from / f.to - (offset.ratio || 1)) < 1e-6);
+            panel.querySelector('.mw-framerate').value = Math.max(index, 0);
+        } catch (e) {
+            setStatus('读取失败：' + e);
+        }
+    }
+
+    async function save() {
+        if (!current) {
+            return;
+        }
+        const framerate = framerates[panel.querySelector('.mw-framerate').value];
+        const body = {
+            offset: parseFloat(panel.querySelector('.mw-offset').value) || 0,
+            from_fps: framerate.from,
+            to_fps: framerate.to,
+        };
+        const resp = await fetch(endpoint(), {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json', ...authHeaders() },
+            body: JSON.stringify(body),
+        });
+        setStatus(resp.ok ? '已保存，重新选择字幕后生效' : '保存失败：' + (await resp.text()));
+    }
+
+    async function reset() {
+        if (!current) {
+            return;
+        }
+        const resp = await fetch(endpoint(), { method: 'DELETE', headers: authHeaders() });
+        if (resp.ok) {
+            panel.querySelector('.mw-offset').value = 0;
+            panel.querySelector('.mw-framerate').value = 0;
+        }
+        setStatus(resp.ok ? '已清除，重新选择字幕后生效' : '清除失败：' + (await resp.text()));
+    }
+
+    document.addEventListener('keydown', event => {
+        if (event.altKey && (event.key === 's' || event.key === 'S')) {
+            event.preventDefault();
+            open();
+        }
+    });
+})();
@@ -6,7 +6,7 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
-	"strconv"
+	"slices"
 	"strings"
 )
 
@@ -106,12 +106,6 @@ func ResolveEmbyAPIKVPairs(urlString string) (string, error) {
 	return "", nil
 }
 
-// 判断字符串是否为整型数字
-func isInt[T ~[]byte | ~[]rune | ~string](s T) bool {
-	_, err := strconv.Atoi(string(s))
-	return err == nil
-}
-
 // 在 []string 中找到某个字符串的索引
 // 如果未找到，返回 -1
 //
@@ -139,3 +133,38 @@ func FindStringIndex(slice []string, target string, caseInsensitive bool, trim b
 	}
 	return -1
 }
+
+// 环境变量名中包含以下关键字时视为敏感信息
+var secretEnvKeywords = []string{"AUTH", "KEY", "TOKEN", "SECRET", "PASSWORD", "PASSWD", "COOKIE", "CREDENTIAL"}
+
+// 遮盖敏感信息
+//
+// 较长的值保留首尾各 2 个字符，便于核对是否配置正确
+func MaskSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	if len(secret) < 12 {
+		return "******"
+	}
+	return secret[:2] + "******" + secret[len(secret)-2:]
+}
+
+// 遮盖环境变量中的敏感信息
+//
+// environ 为 os.Environ 格式的 KEY=VALUE 列表；
+// 以 _FILE 结尾的变量为文件路径，不做遮盖
+func MaskEnviron(environ []string) []string {
+	masked := make([]string, 0, len(environ))
+	for _, env := range environ {
+		name, value, found := strings.Cut(env, "=")
+		upper := strings.ToUpper(name)
+		if found && !strings.HasSuffix(upper, "_FILE") && slices.ContainsFunc(secretEnvKeywords, func(keyword string) bool {
+			return strings.Contains(upper, keyword)
+		}) {
+			env = name + "=" + MaskSecret(value)
+		}
+		masked = append(masked, env)
+	}
+	return masked
+}
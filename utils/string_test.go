@@ -133,3 +133,25 @@ func BenchmarkMethod2(b *testing.B) {
 		buf.Write(newLine)
 	}
 }
+
+func TestMaskEnviron(t *testing.T) {
+	masked := utils.MaskEnviron([]string{
+		"PATH=/usr/bin",
+		"MEDIAWARP_MEDIASERVER_AUTH=5fc6d09d96c34eb7b0636f0e770d3c68",
+		"MEDIAWARP_MEDIASERVER_AUTH_FILE=/run/secrets/emby_api_key",
+		"ALIST_TOKEN=short",
+		"EMPTY_PASSWORD=",
+	})
+	want := []string{
+		"PATH=/usr/bin",
+		"MEDIAWARP_MEDIASERVER_AUTH=5f******68",
+		"MEDIAWARP_MEDIASERVER_AUTH_FILE=/run/secrets/emby_api_key",
+		"ALIST_TOKEN=******",
+		"EMPTY_PASSWORD=",
+	}
+	for i := range want {
+		if masked[i] != want[i] {
+			t.Errorf("遮盖结果错误。期望: %s, 实际: %s", want[i], masked[i])
+		}
+	}
+}